@@ -37,6 +37,10 @@ type ChainValidator interface {
 	ValidateBlock(block *types.Block) (*state.StateDB, types.Receipts, *types.PendingOps, error)
 }
 
+type ReceiptReader interface {
+	GetReceiptsByHash(hash common.Hash) types.Receipts
+}
+
 type Engine interface {
 	Author(header *types.Header) (common.Address, error)
 
@@ -0,0 +1,71 @@
+package misc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/neatio-net/neatio/chain/core/types"
+	"github.com/neatio-net/neatio/params"
+)
+
+func londonConfig() *params.ChainConfig {
+	config := *params.TestChainConfig
+	config.LondonBlock = big.NewInt(5)
+	return &config
+}
+
+func TestCalcBaseFee(t *testing.T) {
+	config := londonConfig()
+
+	tests := []struct {
+		parentNumber  int64
+		parentBaseFee int64
+		parentGasUsed uint64
+		expected      int64
+	}{
+		{4, 0, 0, params.InitialBaseFee},                            // fork block
+		{5, params.InitialBaseFee, 10000000, params.InitialBaseFee}, // usage at the target
+		{5, params.InitialBaseFee, 9000000, 98750000000},            // usage below the target
+		{5, params.InitialBaseFee, 11000000, 101250000000},          // usage above the target
+		{5, params.InitialBaseFee, 0, 87500000000},                  // empty block
+	}
+	for i, test := range tests {
+		parent := &types.Header{
+			Number:   big.NewInt(test.parentNumber),
+			GasLimit: 20000000,
+			GasUsed:  test.parentGasUsed,
+			BaseFee:  big.NewInt(test.parentBaseFee),
+		}
+		if have, want := CalcBaseFee(config, parent), big.NewInt(test.expected); have.Cmp(want) != 0 {
+			t.Errorf("test %d: have %d want %d", i, have, want)
+		}
+	}
+}
+
+func TestVerifyEip1559Header(t *testing.T) {
+	config := londonConfig()
+
+	parent := &types.Header{Number: big.NewInt(3), GasLimit: 20000000}
+	header := &types.Header{Number: big.NewInt(4), GasLimit: 20000000}
+	if err := VerifyEip1559Header(config, parent, header); err != nil {
+		t.Errorf("header before the fork rejected: %v", err)
+	}
+	header.BaseFee = big.NewInt(1)
+	if err := VerifyEip1559Header(config, parent, header); err == nil {
+		t.Errorf("base fee before the fork accepted")
+	}
+
+	parent, header = header, &types.Header{Number: big.NewInt(5), GasLimit: 20000000}
+	parent.BaseFee = nil
+	if err := VerifyEip1559Header(config, parent, header); err == nil {
+		t.Errorf("missing base fee at the fork accepted")
+	}
+	header.BaseFee = new(big.Int).SetUint64(params.InitialBaseFee)
+	if err := VerifyEip1559Header(config, parent, header); err != nil {
+		t.Errorf("fork block rejected: %v", err)
+	}
+	header.BaseFee = big.NewInt(1)
+	if err := VerifyEip1559Header(config, parent, header); err == nil {
+		t.Errorf("wrong base fee accepted")
+	}
+}
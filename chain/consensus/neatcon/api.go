@@ -37,25 +37,7 @@ func (api *API) GetEpoch(num hexutil.Uint64) (*ntcTypes.EpochApi, error) {
 		resultEpoch = epoch.LoadOneEpoch(curEpoch.GetDB(), number, nil)
 	}
 
-	validators := make([]*ntcTypes.EpochValidator, len(resultEpoch.Validators.Validators))
-	for i, val := range resultEpoch.Validators.Validators {
-		validators[i] = &ntcTypes.EpochValidator{
-			Address:        common.BytesToAddress(val.Address),
-			PubKey:         val.PubKey.KeyString(),
-			Amount:         (*hexutil.Big)(val.VotingPower),
-			RemainingEpoch: hexutil.Uint64(val.RemainingEpoch),
-		}
-	}
-
-	return &ntcTypes.EpochApi{
-		Number:         hexutil.Uint64(resultEpoch.Number),
-		RewardPerBlock: (*hexutil.Big)(resultEpoch.RewardPerBlock),
-		StartBlock:     hexutil.Uint64(resultEpoch.StartBlock),
-		EndBlock:       hexutil.Uint64(resultEpoch.EndBlock),
-		StartTime:      resultEpoch.StartTime,
-		EndTime:        resultEpoch.EndTime,
-		Validators:     validators,
-	}, nil
+	return resultEpoch.ToEpochApi(), nil
 }
 
 func (api *API) GetNextEpochVote() (*ntcTypes.EpochVotesApi, error) {
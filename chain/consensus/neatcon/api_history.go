@@ -0,0 +1,111 @@
+package neatcon
+
+import (
+	"errors"
+
+	"github.com/neatio-net/neatio/chain/consensus"
+	"github.com/neatio-net/neatio/chain/consensus/neatcon/epoch"
+	ntcTypes "github.com/neatio-net/neatio/chain/consensus/neatcon/types"
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/common/hexutil"
+)
+
+const maxEpochHistoryRange = 100
+
+// HistoryAPI serves the validator sets of the past epochs and the signing
+// stats of the validators under the neatcon namespace
+type HistoryAPI struct {
+	chain   consensus.ChainReader
+	neatcon *backend
+}
+
+func (api *HistoryAPI) GetValidatorsAtHeight(height hexutil.Uint64) (*ntcTypes.ValidatorsAtHeightApi, error) {
+	if uint64(height) > api.chain.CurrentHeader().Number.Uint64() {
+		return nil, errors.New("block height not reached yet")
+	}
+
+	ep, err := api.neatcon.core.consensusState.Epoch.FindEpochByBlockNumber(uint64(height))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ntcTypes.ValidatorsAtHeightApi{
+		Height:          height,
+		EpochHistoryApi: *toEpochHistoryApi(ep),
+	}, nil
+}
+
+func (api *HistoryAPI) GetEpochHistory(from, to hexutil.Uint64) ([]*ntcTypes.EpochHistoryApi, error) {
+	curEpoch := api.neatcon.core.consensusState.Epoch
+	if from > to || uint64(to) > curEpoch.Number {
+		return nil, errors.New("epoch number out of range")
+	}
+	if to-from >= maxEpochHistoryRange {
+		return nil, errors.New("too many epochs requested, at most 100 at a time")
+	}
+
+	history := make([]*ntcTypes.EpochHistoryApi, 0, to-from+1)
+	for number := uint64(from); number <= uint64(to); number++ {
+		ep := curEpoch
+		if number != curEpoch.Number {
+			ep = epoch.LoadOneEpoch(curEpoch.GetDB(), number, nil)
+		}
+		history = append(history, toEpochHistoryApi(ep))
+	}
+	return history, nil
+}
+
+func (api *HistoryAPI) GetSigningInfo(addr common.Address, num hexutil.Uint64) (*ntcTypes.SigningInfoApi, error) {
+	if api.neatcon.signingIndexer == nil {
+		return nil, errors.New("signing stats indexer is not running")
+	}
+
+	curEpoch := api.neatcon.core.consensusState.Epoch
+	number := uint64(num)
+	if number > curEpoch.Number {
+		return nil, errors.New("epoch number out of range")
+	}
+	ep := curEpoch
+	if number != curEpoch.Number {
+		ep = epoch.LoadOneEpoch(curEpoch.GetDB(), number, nil)
+	}
+
+	sections, _, _ := api.neatcon.signingIndexer.Sections()
+	stats, err := ReadSigningStats(api.neatcon.signingDb, ep, addr, sections)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexedBlock uint64
+	if sections > 0 {
+		indexedBlock = sections*signingSectionSize - 1
+	}
+	return &ntcTypes.SigningInfoApi{
+		Address:      addr,
+		EpochNumber:  num,
+		Proposed:     hexutil.Uint64(stats.Proposed),
+		Signed:       hexutil.Uint64(stats.Signed),
+		Missed:       hexutil.Uint64(stats.Missed),
+		IndexedBlock: hexutil.Uint64(indexedBlock),
+	}, nil
+}
+
+// toEpochHistoryApi leaves the changes nil for the epochs switched before they were recorded
+func toEpochHistoryApi(ep *epoch.Epoch) *ntcTypes.EpochHistoryApi {
+	var changes []*ntcTypes.ValidatorChangeApi
+	if recorded := epoch.LoadEpochValidatorChanges(ep.GetDB(), ep.Number); recorded != nil {
+		changes = make([]*ntcTypes.ValidatorChangeApi, len(recorded))
+		for i, c := range recorded {
+			changes[i] = &ntcTypes.ValidatorChangeApi{
+				Address:     c.Address,
+				Reason:      c.Reason,
+				VotingPower: (*hexutil.Big)(c.VotingPower),
+			}
+		}
+	}
+
+	return &ntcTypes.EpochHistoryApi{
+		EpochApi: *ep.ToEpochApi(),
+		Changes:  changes,
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/neatio-net/neatio/chain/core"
 	neatTypes "github.com/neatio-net/neatio/chain/core/types"
 	"github.com/neatio-net/neatio/chain/log"
+	"github.com/neatio-net/neatio/neatdb"
 	"github.com/neatio-net/neatio/params"
 	"github.com/neatio-net/neatio/utilities/common"
 	"github.com/neatio-net/neatio/utilities/event"
@@ -53,6 +54,11 @@ type backend struct {
 	coreMu            sync.RWMutex
 
 	broadcaster consensus.Broadcaster
+
+	txPool *core.TxPool
+
+	signingIndexer *core.ChainIndexer
+	signingDb      neatdb.Database
 }
 
 func GetBackend() backend {
@@ -71,12 +71,17 @@ func GetConfig(rootDir, chainId string) cfg.Config {
 	mapConfig.SetDefault("pex_reactor", false)
 	mapConfig.SetDefault("priv_validator_file", filepath.Join(rootDir, chainId, "priv_validator.json"))
 	mapConfig.SetDefault("priv_validator_file_root", filepath.Join(rootDir, chainId, "priv_validator"))
+	mapConfig.SetDefault("priv_validator_laddr", "")
+	mapConfig.SetDefault("priv_validator_tls_cert", "")
+	mapConfig.SetDefault("priv_validator_tls_key", "")
+	mapConfig.SetDefault("priv_validator_tls_ca", "")
 	mapConfig.SetDefault("db_dir", filepath.Join(rootDir, chainId, defaultDataDir))
 	mapConfig.SetDefault("grpc_laddr", "")
 	mapConfig.SetDefault("prof_laddr", "")
 	mapConfig.SetDefault("revision_file", filepath.Join(rootDir, chainId, "revision"))
 	mapConfig.SetDefault("cs_wal_file", filepath.Join(rootDir, chainId, defaultDataDir, "cs.wal", "wal"))
 	mapConfig.SetDefault("cs_wal_light", false)
+	mapConfig.SetDefault("external_relayer", false)
 	mapConfig.SetDefault("filter_peers", false)
 
 	mapConfig.SetDefault("block_size", 10000)
@@ -0,0 +1,66 @@
+package consensus
+
+import (
+	"sync"
+
+	"github.com/neatio-net/neatio/chain/consensus/neatcon/types"
+	"github.com/neatio-net/neatio/utilities/common"
+)
+
+const evidenceKeepHeights = 100000
+
+// EvidencePool keeps the double sign evidence seen by this node, so that
+// each evidence is gossiped and reported only once.
+type EvidencePool struct {
+	mtx      sync.Mutex
+	evidence map[common.Hash]*types.DuplicateVoteEvidence
+}
+
+func NewEvidencePool() *EvidencePool {
+	return &EvidencePool{
+		evidence: make(map[common.Hash]*types.DuplicateVoteEvidence),
+	}
+}
+
+// AddEvidence returns true if the evidence was not in the pool yet.
+func (evpool *EvidencePool) AddEvidence(ev *types.DuplicateVoteEvidence) bool {
+	evpool.mtx.Lock()
+	defer evpool.mtx.Unlock()
+
+	hash := ev.Hash()
+	if _, ok := evpool.evidence[hash]; ok {
+		return false
+	}
+	evpool.evidence[hash] = ev
+	return true
+}
+
+func (evpool *EvidencePool) PendingEvidence() []*types.DuplicateVoteEvidence {
+	evpool.mtx.Lock()
+	defer evpool.mtx.Unlock()
+
+	evList := make([]*types.DuplicateVoteEvidence, 0, len(evpool.evidence))
+	for _, ev := range evpool.evidence {
+		evList = append(evList, ev)
+	}
+	return evList
+}
+
+// Prune removes the evidence which is too old to be accepted by the chain.
+func (evpool *EvidencePool) Prune(height uint64) {
+	evpool.mtx.Lock()
+	defer evpool.mtx.Unlock()
+
+	for hash, ev := range evpool.evidence {
+		if ev.Height()+evidenceKeepHeights < height {
+			delete(evpool.evidence, hash)
+		}
+	}
+}
+
+func (evpool *EvidencePool) Size() int {
+	evpool.mtx.Lock()
+	defer evpool.mtx.Unlock()
+
+	return len(evpool.evidence)
+}
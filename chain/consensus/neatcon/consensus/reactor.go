@@ -199,6 +199,9 @@ func (conR *ConsensusReactor) Receive(chID uint64, src consensus.Peer, msgBytes
 
 			conR.conS.peerMsgQueue <- msgInfo{msg, src.GetKey()}
 
+		case *EvidenceMessage:
+			conR.conS.peerMsgQueue <- msgInfo{msg, src.GetKey()}
+
 		default:
 			conR.logger.Warn(Fmt("Unknown message type %v", reflect.TypeOf(msg)))
 		}
@@ -250,6 +253,11 @@ func (conR *ConsensusReactor) registerEventCallbacks() {
 		conR.broadcastSignAggr(edv.SignAggr)
 	})
 
+	types.AddListenerForEvent(conR.evsw, "conR", types.EventStringDupeout(), func(data types.TMEventData) {
+		edd := data.(types.EventDataDupeout)
+		conR.broadcastEvidence(edd.Evidence)
+	})
+
 	types.AddListenerForEvent(conR.evsw, "conR", types.EventStringVote2Proposer(), func(data types.TMEventData) {
 		edv := data.(types.EventDataVote2Proposer)
 		conR.sendVote2Proposer(edv.Vote, edv.ProposerKey)
@@ -293,6 +301,13 @@ func (conR *ConsensusReactor) broadcastSignAggr(sign *types.SignAggr) {
 	}
 }
 
+func (conR *ConsensusReactor) broadcastEvidence(ev *types.DuplicateVoteEvidence) {
+	if ev != nil {
+		msg := &EvidenceMessage{Evidence: ev}
+		conR.conS.backend.GetBroadcaster().BroadcastMessage(VoteChannel, struct{ ConsensusMessage }{msg})
+	}
+}
+
 func (conR *ConsensusReactor) sendVote2Proposer(vote *types.Vote, proposerKey string) {
 	if vote != nil {
 		peerState, ok := conR.peerStates.Load(proposerKey)
@@ -863,6 +878,7 @@ const (
 	msgTypeVoteSetMaj23  = byte(0x16)
 	msgTypeVoteSetBits   = byte(0x17)
 	msgTypeMaj23SignAggr = byte(0x18)
+	msgTypeEvidence      = byte(0x19)
 )
 
 type ConsensusMessage interface{}
@@ -879,6 +895,7 @@ var _ = wire.RegisterInterface(
 	wire.ConcreteType{&VoteSetMaj23Message{}, msgTypeVoteSetMaj23},
 	wire.ConcreteType{&VoteSetBitsMessage{}, msgTypeVoteSetBits},
 	wire.ConcreteType{&Maj23SignAggrMessage{}, msgTypeMaj23SignAggr},
+	wire.ConcreteType{&EvidenceMessage{}, msgTypeEvidence},
 )
 
 func DecodeMessage(bz []byte) (msgType byte, msg ConsensusMessage, err error) {
@@ -956,6 +973,14 @@ func (m *Maj23SignAggrMessage) String() string {
 	return fmt.Sprintf("[SignAggr %v]", m.Maj23SignAggr)
 }
 
+type EvidenceMessage struct {
+	Evidence *types.DuplicateVoteEvidence
+}
+
+func (m *EvidenceMessage) String() string {
+	return fmt.Sprintf("[Evidence %v]", m.Evidence)
+}
+
 type HasVoteMessage struct {
 	Height uint64
 	Round  int
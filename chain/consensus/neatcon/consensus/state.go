@@ -14,7 +14,6 @@ import (
 
 	"context"
 
-	"crypto/ecdsa"
 	"crypto/sha256"
 	"math/big"
 
@@ -29,7 +28,6 @@ import (
 	neatTypes "github.com/neatio-net/neatio/chain/core/types"
 	neatAbi "github.com/neatio-net/neatio/neatabi/abi"
 	"github.com/neatio-net/neatio/params"
-	"github.com/neatio-net/neatio/utilities/crypto"
 	"github.com/neatio-net/neatio/utilities/rlp"
 )
 
@@ -41,6 +39,7 @@ type Backend interface {
 	ChainReader() consss.ChainReader
 	GetBroadcaster() consss.Broadcaster
 	GetLogger() log.Logger
+	SendChainTx(input []byte, signer types.TxSigner) (common.Hash, error)
 }
 
 type TimeoutParams struct {
@@ -293,6 +292,16 @@ type ConsensusState struct {
 	blockFromMiner *neatTypes.Block
 	backend        Backend
 
+	evpool *EvidencePool
+
+	wal        *WAL
+	walFile    string
+	walLight   bool
+	replayMode bool
+
+	// the side chain blocks are relayed to the main chain by neatio relayer
+	externalRelayer bool
+
 	conR *ConsensusReactor
 
 	logger log.Logger
@@ -307,9 +316,16 @@ func NewConsensusState(backend Backend, config cfg.Config, chainConfig *params.C
 		timeoutParams:  InitTimeoutParamsFromConfig(config),
 		blockFromMiner: nil,
 		backend:        backend,
+		evpool:         NewEvidencePool(),
 		logger:         backend.GetLogger(),
 	}
 
+	if config.IsSet("cs_wal_file") {
+		cs.walFile = config.GetString("cs_wal_file")
+		cs.walLight = config.IsSet("cs_wal_light") && config.GetBool("cs_wal_light")
+	}
+	cs.externalRelayer = config.IsSet("external_relayer") && config.GetBool("external_relayer")
+
 	cs.decideProposal = cs.defaultDecideProposal
 	cs.doPrevote = cs.defaultDoPrevote
 	cs.setProposal = cs.defaultSetProposal
@@ -525,12 +541,24 @@ func (cs *ConsensusState) OnStart() error {
 	cs.peerMsgQueue = make(chan msgInfo, msgQueueSize)
 	cs.internalMsgQueue = make(chan msgInfo, msgQueueSize)
 
-	cs.timeoutTicker.Start()
+	if cs.walFile != "" {
+		wal, err := OpenWAL(cs.walFile, cs.walLight, cs.logger)
+		if err != nil {
+			cs.logger.Errorf("Failed to open consensus WAL %v, error: %v", cs.walFile, err)
+			return err
+		}
+		cs.wal = wal
+	}
 
-	go cs.receiveRoutine(0)
+	cs.timeoutTicker.Start()
 
 	cs.StartNewHeight()
 
+	// replay what happened at this height before a restart, ahead of any new message
+	cs.catchupReplay(cs.Height)
+
+	go cs.receiveRoutine(0)
+
 	return nil
 }
 
@@ -544,6 +572,42 @@ func (cs *ConsensusState) OnStop() {
 	cs.logger.Infof("ConsensusState wait")
 	cs.wg.Wait()
 	cs.logger.Infof("ConsensusState wait over")
+
+	cs.wal.Close()
+}
+
+// catchupReplay feeds the WAL messages of the given height back into the
+// consensus state, so we get back to the round and step we were in and sign
+// exactly what we signed before the restart
+func (cs *ConsensusState) catchupReplay(height uint64) {
+	msgs, err := cs.wal.ReadHead()
+	if err != nil {
+		cs.logger.Warnf("Consensus WAL replay stopped, error: %v", err)
+	}
+
+	cs.replayMode = true
+	defer func() { cs.replayMode = false }()
+
+	replayed := 0
+	for _, m := range msgs {
+		if WALMessageHeight(m.Msg) != height {
+			continue
+		}
+
+		switch msg := m.Msg.(type) {
+		case msgInfo:
+			cs.handleMsg(msg, cs.RoundState)
+		case timeoutInfo:
+			cs.handleTimeout(msg, cs.RoundState)
+		default:
+			continue
+		}
+		replayed++
+	}
+
+	if replayed > 0 {
+		cs.logger.Infof("Replayed %v consensus WAL messages of height %v, now at %v/%v/%v", replayed, height, cs.Height, cs.Round, cs.Step)
+	}
 }
 
 func (cs *ConsensusState) AddVote(vote *types.Vote, peerKey string) (added bool, err error) {
@@ -602,6 +666,10 @@ func (cs *ConsensusState) scheduleTimeout(duration time.Duration, height uint64,
 }
 
 func (cs *ConsensusState) sendInternalMessage(mi msgInfo) {
+	if !cs.IsRunning() {
+		cs.logger.Infof("ConsensusState sendInternalMessage, but need stop or not running, just return")
+		return
+	}
 	select {
 	case cs.internalMsgQueue <- mi:
 	default:
@@ -650,6 +718,7 @@ func (cs *ConsensusState) receiveRoutine(maxSteps int) {
 				cs.logger.Infof("ConsensusState peerMsgQueue, but need stop or not running, just return")
 				return
 			}
+			cs.wal.Save(mi)
 			rs := cs.RoundState
 			cs.handleMsg(mi, rs)
 		case mi = <-cs.internalMsgQueue:
@@ -657,6 +726,8 @@ func (cs *ConsensusState) receiveRoutine(maxSteps int) {
 				cs.logger.Infof("ConsensusState internalMsgQueue, but need stop or not running, just return")
 				return
 			}
+			// our own proposal and votes must be on disk before they are handled and sent
+			cs.wal.SaveSync(mi)
 			rs := cs.RoundState
 			cs.handleMsg(mi, rs)
 		case ti := <-cs.timeoutTicker.Chan():
@@ -664,6 +735,7 @@ func (cs *ConsensusState) receiveRoutine(maxSteps int) {
 				cs.logger.Infof("ConsensusState timeoutTicker.Chan(), but need stop or not running, just return")
 				return
 			}
+			cs.wal.Save(ti)
 			rs := cs.RoundState
 			cs.handleTimeout(ti, rs)
 		}
@@ -699,6 +771,10 @@ func (cs *ConsensusState) handleMsg(mi msgInfo, rs RoundState) {
 		cs.mtx.Unlock()
 		if err == ErrAddingVote {
 		}
+	case *EvidenceMessage:
+		cs.mtx.Lock()
+		err = cs.addEvidence(msg.Evidence)
+		cs.mtx.Unlock()
 
 	default:
 		cs.logger.Warnf("handleMsg. Unknown msg type %v", reflect.TypeOf(msg))
@@ -847,7 +923,7 @@ func (cs *ConsensusState) enterPropose(height uint64, round int) {
 		}
 	}()
 
-	if cs.state.NTCExtra.NeedToSave &&
+	if cs.state.NTCExtra.NeedToSave && !cs.externalRelayer &&
 		(cs.state.NTCExtra.ChainID != params.MainnetChainConfig.NeatChainId && cs.state.NTCExtra.ChainID != params.TestnetChainConfig.NeatChainId) {
 		if cs.privValidator != nil && cs.IsProposer() {
 			//cs.logger.Infof("enterPropose: saveBlockToMainChain height: %v", cs.state.NTCExtra.Height)
@@ -857,7 +933,7 @@ func (cs *ConsensusState) enterPropose(height uint64, round int) {
 		}
 	}
 
-	if cs.state.NTCExtra.NeedToBroadcast &&
+	if cs.state.NTCExtra.NeedToBroadcast && !cs.externalRelayer &&
 		(cs.state.NTCExtra.ChainID != params.MainnetChainConfig.NeatChainId && cs.state.NTCExtra.ChainID != params.TestnetChainConfig.NeatChainId) {
 		if cs.privValidator != nil && cs.IsProposer() {
 			//cs.logger.Infof("enterPropose: broadcastTX3ProofDataToMainChain height: %v", cs.state.NTCExtra.Height)
@@ -1281,7 +1357,7 @@ func (cs *ConsensusState) finalizeCommit(height uint64) {
 						continue
 					}
 
-					if function == neatAbi.WithdrawFromSideChain {
+					if function == neatAbi.WithdrawFromSideChain || function == neatAbi.TransferToSideChain {
 						block.NTCExtra.NeedToBroadcast = true
 						cs.logger.Infof("NeedToBroadcast set to true due to tx. Tx: %s, Chain: %s, Height: %v", function.String(), block.NTCExtra.ChainID, block.NTCExtra.Height)
 						break
@@ -1296,6 +1372,8 @@ func (cs *ConsensusState) finalizeCommit(height uint64) {
 		err := cs.backend.Commit(block, [][]byte{}, cs.IsProposer)
 		if err != nil {
 			cs.logger.Errorf("Commit fail. error: %v", err)
+		} else if !cs.replayMode {
+			cs.wal.SaveEndHeight(height)
 		}
 	} else {
 		cs.logger.Warn("Calling finalizeCommit on already stored block", "height", block.NTCExtra.Height)
@@ -1552,11 +1630,14 @@ func (cs *ConsensusState) tryAddVote(vote *types.Vote, peerKey string) error {
 	if err != nil {
 		if err == ErrVoteHeightMismatch {
 			return err
-		} else if _, ok := err.(*types.ErrVoteConflictingVotes); ok {
+		} else if conflict, ok := err.(*types.ErrVoteConflictingVotes); ok {
 			if peerKey == "" {
 				cs.logger.Warn("Found conflicting vote from ourselves. Did you unsafe_reset a validator?", "height", vote.Height, "round", vote.Round, "type", vote.Type)
 				return err
 			}
+			if everr := cs.addEvidence(types.NewDuplicateVoteEvidence(conflict)); everr != nil {
+				cs.logger.Warn("Failed to add double sign evidence", "error", everr)
+			}
 			return err
 		} else {
 			cs.logger.Warn("Error attempting to add vote", "error", err)
@@ -1566,6 +1647,59 @@ func (cs *ConsensusState) tryAddVote(vote *types.Vote, peerKey string) error {
 	return nil
 }
 
+// addEvidence verifies the evidence, gossips it to the peers and reports it to
+// the chain if it was not seen before.
+func (cs *ConsensusState) addEvidence(ev *types.DuplicateVoteEvidence) error {
+
+	if err := ev.ValidateBasic(); err != nil {
+		return err
+	}
+
+	evEpoch := cs.Epoch.GetEpochByBlockNumber(ev.Height())
+	if evEpoch == nil {
+		return core.ErrEvidenceTooOld
+	}
+
+	if err := ev.Verify(cs.chainConfig.NeatChainId, evEpoch.Validators); err != nil {
+		return err
+	}
+
+	cs.evpool.Prune(cs.Height)
+	if !cs.evpool.AddEvidence(ev) {
+		return nil
+	}
+
+	cs.logger.Warnf("Found double sign evidence: %v", ev)
+	types.FireEventDupeout(cs.evsw, types.EventDataDupeout{ev})
+
+	if cs.privValidator != nil && !bytes.Equal(cs.privValidator.GetAddress(), ev.VoteA.ValidatorAddress) {
+		go cs.reportEvidence(ev)
+	}
+	return nil
+}
+
+func (cs *ConsensusState) reportEvidence(ev *types.DuplicateVoteEvidence) {
+
+	input, err := neatAbi.ChainABI.Pack(neatAbi.ReportDoubleSign.String(), ev.Bytes())
+	if err != nil {
+		cs.logger.Error("reportEvidence: failed to pack evidence", "err", err)
+		return
+	}
+
+	signer, ok := cs.privValidator.(types.TxSigner)
+	if !ok {
+		cs.logger.Error("reportEvidence: unexpected privValidator type")
+		return
+	}
+
+	hash, err := cs.backend.SendChainTx(input, signer)
+	if err != nil {
+		cs.logger.Error("reportEvidence: failed to send tx", "err", err)
+		return
+	}
+	cs.logger.Infof("reportEvidence success, hash: %x", hash)
+}
+
 func (cs *ConsensusState) addVote(vote *types.Vote, peerKey string) (added bool, err error) {
 
 	if !cs.IsProposer() {
@@ -1626,6 +1760,10 @@ func (cs *ConsensusState) signAddVote(type_ byte, hash []byte, header types.Part
 	vote, err := cs.signVote(type_, hash, header)
 	if err == nil {
 		if !cs.IsProposer() {
+			// the vote goes straight to the proposer, write it before it leaves
+			if !cs.replayMode {
+				cs.wal.SaveSync(msgInfo{&VoteMessage{vote}, ""})
+			}
 			if cs.ProposerPeerKey != "" {
 				v2pMsg := types.EventDataVote2Proposer{vote, cs.ProposerPeerKey}
 				types.FireEventVote2Proposer(cs.evsw, v2pMsg)
@@ -1767,17 +1905,16 @@ func (cs *ConsensusState) saveBlockToMainChain(block *neatTypes.Block) {
 		return
 	}
 
-	var prv *ecdsa.PrivateKey
-	if prvValidator, ok := cs.privValidator.(*types.PrivValidator); ok {
-		prv, err = crypto.ToECDSA(prvValidator.PrivKey.(tmdcrypto.BLSPrivKey).Bytes())
-		if err != nil {
-			cs.logger.Error("saveDataToMainChain: failed to get PrivateKey", "err", err)
-			return
-		}
-	} else {
+	signer, ok := cs.privValidator.(types.TxSigner)
+	if !ok {
 		panic("saveDataToMainChain: unexpected privValidator type")
 	}
-	hash, err := client.SendDataToMainChain(ctx, bs, prv, cs.cch.GetMainChainId())
+	account, err := signer.TxAccount()
+	if err != nil {
+		cs.logger.Error("saveDataToMainChain: failed to get the account", "err", err)
+		return
+	}
+	hash, err := client.SendDataToMainChainBySigner(ctx, bs, account, signer.SignTx, cs.cch.GetMainChainId())
 	if err != nil {
 		cs.logger.Error("saveDataToMainChain(rpc) failed", "err", err)
 		return
@@ -1814,7 +1951,11 @@ func (cs *ConsensusState) broadcastTX3ProofDataToMainChain(block *neatTypes.Bloc
 	client := cs.cch.GetClient()
 	ctx, _ := context.WithTimeout(context.Background(), 30*time.Second)
 
-	proofData, err := neatTypes.NewTX3ProofData(block)
+	var receipts neatTypes.Receipts
+	if rr, ok := cs.backend.ChainReader().(consss.ReceiptReader); ok {
+		receipts = rr.GetReceiptsByHash(block.Hash())
+	}
+	proofData, err := neatTypes.NewTX3ProofData(block, receipts)
 	if err != nil {
 		cs.logger.Error("broadcastTX3ProofDataToMainChain: failed to create proof data", "block", block, "err", err)
 		return
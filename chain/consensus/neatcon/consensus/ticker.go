@@ -68,6 +68,10 @@ func (t *timeoutTicker) Chan() <-chan timeoutInfo {
 }
 
 func (t *timeoutTicker) ScheduleTimeout(ti timeoutInfo) {
+	// the consensus state may still be finishing a step after the ticker is stopped
+	if !t.IsRunning() {
+		return
+	}
 	t.tickChan <- ti
 }
 
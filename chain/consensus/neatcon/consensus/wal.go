@@ -0,0 +1,339 @@
+package consensus
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/neatio-net/neatio/chain/log"
+	"github.com/neatio-net/wire-go"
+)
+
+const (
+	maxWALHeadSize   = 16 * 1024 * 1024 // rotate the head file at the next height once it is bigger
+	maxWALRecordSize = maxConsensusMessageSize + 1024
+	walRecordHeader  = 8 // crc32 + length
+)
+
+var (
+	ErrWALCorrupted     = errors.New("consensus WAL corrupted")
+	ErrWALRecordTooLong = errors.New("consensus WAL record too long")
+)
+
+// WALMessage is the content of a consensus WAL record: msgInfo, timeoutInfo or EndHeightMessage
+type WALMessage interface{}
+
+var _ = wire.RegisterInterface(
+	struct{ WALMessage }{},
+	wire.ConcreteType{msgInfo{}, 0x01},
+	wire.ConcreteType{timeoutInfo{}, 0x02},
+	wire.ConcreteType{EndHeightMessage{}, 0x03},
+)
+
+// EndHeightMessage marks the end of a height, it is written once the block is committed
+type EndHeightMessage struct {
+	Height uint64 `json:"height"`
+}
+
+type TimedWALMessage struct {
+	Time time.Time  `json:"time"`
+	Msg  WALMessage `json:"msg"`
+}
+
+func (m *TimedWALMessage) String() string {
+	switch msg := m.Msg.(type) {
+	case msgInfo:
+		source := "self"
+		if msg.PeerKey != "" {
+			source = "peer " + msg.PeerKey
+		}
+		return fmt.Sprintf("%v MSG     [%v] %v", m.Time.Format(time.RFC3339Nano), source, msg.Msg)
+	case timeoutInfo:
+		return fmt.Sprintf("%v TIMEOUT %v", m.Time.Format(time.RFC3339Nano), msg.String())
+	case EndHeightMessage:
+		return fmt.Sprintf("%v ENDHEIGHT %v", m.Time.Format(time.RFC3339Nano), msg.Height)
+	default:
+		return fmt.Sprintf("%v UNKNOWN %v", m.Time.Format(time.RFC3339Nano), msg)
+	}
+}
+
+// WAL is the write ahead log of the consensus state. Every message processed by
+// receiveRoutine, the timeouts and our own signatures are written to it before
+// they take effect, so a restarted validator can replay the current height and
+// never sign something conflicting with what it signed before the crash.
+//
+// In light mode the messages received from peers are not written.
+type WAL struct {
+	mtx    sync.Mutex
+	path   string
+	light  bool
+	file   *os.File
+	size   int64
+	logger log.Logger
+}
+
+func OpenWAL(path string, light bool, logger log.Logger) (*WAL, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+
+	wal := &WAL{
+		path:   path,
+		light:  light,
+		logger: logger,
+	}
+	if err := wal.open(); err != nil {
+		return nil, err
+	}
+	return wal, nil
+}
+
+// open opens the head file and cuts the incomplete record a crash may have left at the end
+func (wal *WAL) open() error {
+	file, err := os.OpenFile(wal.path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+
+	valid, err := validWALSize(file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if info, err := file.Stat(); err == nil && info.Size() != valid {
+		wal.logger.Warnf("Consensus WAL %v has a broken tail, truncate from %v to %v bytes", wal.path, info.Size(), valid)
+		if err := file.Truncate(valid); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if _, err := file.Seek(valid, io.SeekStart); err != nil {
+		file.Close()
+		return err
+	}
+
+	wal.file = file
+	wal.size = valid
+	return nil
+}
+
+func validWALSize(file *os.File) (int64, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	reader := NewWALReader(file)
+	for {
+		_, err := reader.Read()
+		if err == io.EOF || err == ErrWALCorrupted {
+			return reader.Offset(), nil
+		} else if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Save writes a message received from a peer, it is skipped in light mode
+func (wal *WAL) Save(msg WALMessage) {
+	if wal == nil {
+		return
+	}
+	if mi, ok := msg.(msgInfo); ok && mi.PeerKey != "" && wal.light {
+		return
+	}
+	if err := wal.write(msg, false); err != nil {
+		wal.logger.Errorf("Failed to write consensus WAL, msg: %v, error: %v", msg, err)
+	}
+}
+
+// SaveSync writes the message and flushes it to disk before returning, it is
+// used for everything we signed ourselves
+func (wal *WAL) SaveSync(msg WALMessage) {
+	if wal == nil {
+		return
+	}
+	if err := wal.write(msg, true); err != nil {
+		wal.logger.Errorf("Failed to write consensus WAL, msg: %v, error: %v", msg, err)
+	}
+}
+
+// SaveEndHeight marks the height as committed, the head file is rotated here
+// when it becomes too big, so the head always starts at a height boundary
+func (wal *WAL) SaveEndHeight(height uint64) {
+	if wal == nil {
+		return
+	}
+	wal.SaveSync(EndHeightMessage{height})
+
+	wal.mtx.Lock()
+	defer wal.mtx.Unlock()
+	if wal.size > maxWALHeadSize {
+		if err := wal.rotate(); err != nil {
+			wal.logger.Errorf("Failed to rotate consensus WAL, error: %v", err)
+		}
+	}
+}
+
+func (wal *WAL) write(msg WALMessage, sync bool) error {
+	data := wire.BinaryBytes(TimedWALMessage{time.Now(), msg})
+	if len(data) > maxWALRecordSize {
+		return ErrWALRecordTooLong
+	}
+
+	record := make([]byte, walRecordHeader+len(data))
+	binary.BigEndian.PutUint32(record[0:4], crc32.ChecksumIEEE(data))
+	binary.BigEndian.PutUint32(record[4:8], uint32(len(data)))
+	copy(record[walRecordHeader:], data)
+
+	wal.mtx.Lock()
+	defer wal.mtx.Unlock()
+
+	if wal.file == nil {
+		return errors.New("consensus WAL is closed")
+	}
+	n, err := wal.file.Write(record)
+	wal.size += int64(n)
+	if err != nil {
+		return err
+	}
+	if sync {
+		return wal.file.Sync()
+	}
+	return nil
+}
+
+func (wal *WAL) rotate() error {
+	if err := wal.file.Close(); err != nil {
+		return err
+	}
+	wal.file = nil
+	if err := os.Rename(wal.path, wal.path+".1"); err != nil {
+		return err
+	}
+	return wal.open()
+}
+
+// ReadHead returns all messages of the head file
+func (wal *WAL) ReadHead() ([]*TimedWALMessage, error) {
+	if wal == nil {
+		return nil, nil
+	}
+	wal.mtx.Lock()
+	defer wal.mtx.Unlock()
+
+	return ReadWALFile(wal.path)
+}
+
+func (wal *WAL) Close() {
+	if wal == nil {
+		return
+	}
+	wal.mtx.Lock()
+	defer wal.mtx.Unlock()
+
+	if wal.file != nil {
+		wal.file.Sync()
+		wal.file.Close()
+		wal.file = nil
+	}
+}
+
+// ReadWALFile reads the messages of a WAL file, stopping at the first broken record
+func ReadWALFile(path string) ([]*TimedWALMessage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var msgs []*TimedWALMessage
+	reader := NewWALReader(file)
+	for {
+		msg, err := reader.Read()
+		if err == io.EOF {
+			return msgs, nil
+		} else if err != nil {
+			return msgs, err
+		}
+		msgs = append(msgs, msg)
+	}
+}
+
+type WALReader struct {
+	r      *bufio.Reader
+	offset int64
+}
+
+func NewWALReader(r io.Reader) *WALReader {
+	return &WALReader{r: bufio.NewReader(r)}
+}
+
+// Offset returns the end of the last record read successfully
+func (wr *WALReader) Offset() int64 {
+	return wr.offset
+}
+
+// Read returns the next message, io.EOF at the end of the log and
+// ErrWALCorrupted for an incomplete or damaged record
+func (wr *WALReader) Read() (*TimedWALMessage, error) {
+	header := make([]byte, walRecordHeader)
+	if n, err := io.ReadFull(wr.r, header); err == io.EOF {
+		return nil, io.EOF
+	} else if err == io.ErrUnexpectedEOF || n < walRecordHeader {
+		return nil, ErrWALCorrupted
+	} else if err != nil {
+		return nil, err
+	}
+
+	checksum := binary.BigEndian.Uint32(header[0:4])
+	length := binary.BigEndian.Uint32(header[4:8])
+	if length > maxWALRecordSize {
+		return nil, ErrWALCorrupted
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(wr.r, data); err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil, ErrWALCorrupted
+	} else if err != nil {
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(data) != checksum {
+		return nil, ErrWALCorrupted
+	}
+
+	var msg TimedWALMessage
+	if err := wire.ReadBinaryBytes(data, &msg); err != nil {
+		return nil, ErrWALCorrupted
+	}
+
+	wr.offset += int64(walRecordHeader + len(data))
+	return &msg, nil
+}
+
+// WALMessageHeight returns the height a message belongs to, 0 if it has none
+func WALMessageHeight(msg WALMessage) uint64 {
+	switch m := msg.(type) {
+	case msgInfo:
+		switch cm := m.Msg.(type) {
+		case *ProposalMessage:
+			return cm.Proposal.Height
+		case *BlockPartMessage:
+			return cm.Height
+		case *VoteMessage:
+			return cm.Vote.Height
+		case *Maj23SignAggrMessage:
+			return cm.Maj23SignAggr.Height
+		}
+	case timeoutInfo:
+		return m.Height
+	case EndHeightMessage:
+		return m.Height
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package consensus
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/neatio-net/neatio/chain/consensus/neatcon/types"
+	"github.com/neatio-net/neatio/chain/log"
+)
+
+func TestWALWriteAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cs_wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cs.wal", "wal")
+
+	wal, err := OpenWAL(path, false, log.New())
+	if err != nil {
+		t.Fatalf("open wal error %v", err)
+	}
+
+	vote := &types.Vote{ValidatorAddress: []byte("validator"), Height: 10, Round: 1, Type: types.VoteTypePrevote}
+	wal.SaveSync(msgInfo{&VoteMessage{vote}, ""})
+	wal.Save(msgInfo{&VoteMessage{vote}, "peer"})
+	wal.Save(timeoutInfo{Height: 10, Round: 1, Step: RoundStepPrevoteWait})
+	wal.SaveEndHeight(10)
+	wal.Close()
+
+	// a crash in the middle of a write leaves a broken record at the end
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	file.Write([]byte{0x01, 0x02, 0x03})
+	file.Close()
+
+	if _, err := ReadWALFile(path); err != ErrWALCorrupted {
+		t.Errorf("expected %v, got %v", ErrWALCorrupted, err)
+	}
+
+	wal, err = OpenWAL(path, true, log.New())
+	if err != nil {
+		t.Fatalf("reopen wal error %v", err)
+	}
+	wal.Save(msgInfo{&VoteMessage{vote}, "peer"})
+	wal.Save(timeoutInfo{Height: 11, Round: 0, Step: RoundStepNewHeight})
+
+	msgs, err := wal.ReadHead()
+	wal.Close()
+	if err != nil {
+		t.Fatalf("read wal error %v", err)
+	}
+	if len(msgs) != 5 {
+		t.Fatalf("expected 5 messages, got %v", len(msgs))
+	}
+
+	mi, ok := msgs[0].Msg.(msgInfo)
+	if !ok || mi.PeerKey != "" {
+		t.Fatalf("first message should be our own vote, got %v", msgs[0])
+	}
+	if got := mi.Msg.(*VoteMessage).Vote; got.Height != 10 || got.Round != 1 || string(got.ValidatorAddress) != "validator" {
+		t.Errorf("vote not restored, got %v", got)
+	}
+	if _, ok := msgs[3].Msg.(EndHeightMessage); !ok {
+		t.Errorf("expected end height marker, got %v", msgs[3])
+	}
+	if WALMessageHeight(msgs[4].Msg) != 11 {
+		t.Errorf("peer messages should not be written in light mode, got %v", msgs[4])
+	}
+}
@@ -8,10 +8,13 @@ import (
 
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/neatio-net/neatio/chain/consensus"
+	"github.com/neatio-net/neatio/chain/consensus/misc"
 	"github.com/neatio-net/neatio/chain/consensus/neatcon/epoch"
 	ntcTypes "github.com/neatio-net/neatio/chain/consensus/neatcon/types"
 	"github.com/neatio-net/neatio/chain/core/state"
 	"github.com/neatio-net/neatio/chain/core/types"
+	"github.com/neatio-net/neatio/chain/log"
+	neatAbi "github.com/neatio-net/neatio/neatabi/abi"
 	"github.com/neatio-net/neatio/network/rpc"
 	"github.com/neatio-net/neatio/params"
 	"github.com/neatio-net/neatio/utilities/common"
@@ -20,6 +23,8 @@ import (
 
 const (
 	fetcherID = "neatcon"
+
+	doubleSignSlashPercent = 10
 )
 
 var (
@@ -70,8 +75,6 @@ var (
 
 	neatGenesisAddress = common.HexToAddress("0x0000000000000000000000000000000000000000")
 	foundationAddress  = common.HexToAddress("0xbbe9e63Dcb95105A3Ab5e9094B0C866F0f418987")
-
-	sideChainRewardAddress = common.StringToAddress("0x0000000000000000000000000000000000000535")
 )
 
 func (sb *backend) APIs(chain consensus.ChainReader) []rpc.API {
@@ -80,6 +83,11 @@ func (sb *backend) APIs(chain consensus.ChainReader) []rpc.API {
 		Version:   "1.0",
 		Service:   &API{chain: chain, neatcon: sb},
 		Public:    true,
+	}, {
+		Namespace: "neatcon",
+		Version:   "1.0",
+		Service:   &HistoryAPI{chain: chain, neatcon: sb},
+		Public:    true,
 	}}
 }
 
@@ -204,8 +212,10 @@ func (sb *backend) verifyHeader(chain consensus.ChainReader, header *types.Heade
 		tried := 0
 		for {
 
-			ourMainChainHeight := sb.core.cch.GetHeightFromMainChain()
-			if ourMainChainHeight.Cmp(header.MainChainNumber) >= 0 {
+			ourMainChainHeight, err := sb.core.cch.GetHeightFromMainChain()
+			if err != nil {
+				sb.logger.Warnf("NeatCon VerifyHeader, %v", err)
+			} else if ourMainChainHeight.Cmp(header.MainChainNumber) >= 0 {
 				break
 			}
 
@@ -241,6 +251,10 @@ func (sb *backend) verifyCascadingFields(chain consensus.ChainReader, header *ty
 		return consensus.ErrUnknownAncestor
 	}
 
+	if err := misc.VerifyEip1559Header(sb.chainConfig, parent, header); err != nil {
+		return err
+	}
+
 	err := sb.verifyCommittedSeals(chain, header, parents)
 	return err
 }
@@ -381,7 +395,11 @@ func (sb *backend) Prepare(chain consensus.ChainReader, header *types.Header) er
 	header.Time = big.NewInt(time.Now().Unix())
 
 	if sb.chainConfig.NeatChainId != params.MainnetChainConfig.NeatChainId && sb.chainConfig.NeatChainId != params.TestnetChainConfig.NeatChainId {
-		header.MainChainNumber = sb.core.cch.GetHeightFromMainChain()
+		mainChainNumber, err := sb.core.cch.GetHeightFromMainChain()
+		if err != nil {
+			return err
+		}
+		header.MainChainNumber = mainChainNumber
 	}
 
 	return nil
@@ -392,9 +410,11 @@ func (sb *backend) Finalize(chain consensus.ChainReader, header *types.Header, s
 
 	sb.logger.Debugf("NeatCon Finalize, receipts are: %v", receipts)
 
+	neatFork := sb.chainConfig.IsNeatFork(header.Number)
+
 	if sb.chainConfig.NeatChainId == params.MainnetChainConfig.NeatChainId || sb.chainConfig.NeatChainId == params.TestnetChainConfig.NeatChainId {
 
-		readyId, updateBytes, removedId := sb.core.cch.ReadyForLaunchSideChain(header.Number, state)
+		readyId, updateBytes, removedId := sb.core.cch.ReadyForLaunchSideChain(header.Number, state, neatFork)
 		if len(readyId) > 0 || updateBytes != nil || len(removedId) > 0 {
 			if ok := ops.Append(&types.LaunchSideChainsOp{
 				SideChainIds:       readyId,
@@ -405,6 +425,18 @@ func (sb *backend) Finalize(chain consensus.ChainReader, header *types.Header, s
 				sb.logger.Error("NeatCon Finalize, Fail to append LaunchSideChainsOp, only one LaunchSideChainsOp is allowed in each block")
 			}
 		}
+
+		if neatFork {
+			for _, chainId := range sb.core.cch.ReadyForDecommissionSideChain(header.Number, state) {
+				if ok := ops.Append(&types.DecommissionSideChainOp{ChainId: chainId}); !ok {
+					// already decommissioned by the owner in this block
+					continue
+				}
+				if err := sb.core.cch.SettleSideChainDeposits(chainId, state); err != nil {
+					sb.logger.Errorf("NeatCon Finalize, Fail to settle the deposits of side chain %v, %v", chainId, err)
+				}
+			}
+		}
 	}
 
 	curBlockNumber := header.Number.Uint64()
@@ -416,12 +448,20 @@ func (sb *backend) Finalize(chain consensus.ChainReader, header *types.Header, s
 		neatGenesisAddress = state.GetAddress(genesisCoinbase)
 	}
 
-	accumulateRewards(sb.chainConfig, state, header, epoch, totalGasFee)
+	if neatFork {
+		slashDoubleSigners(state, header.Number.Uint64(), txs, sb.logger)
+		sb.markMissedValidators(chain, header, state, epoch)
+	}
+
+	baseFeeReward := distributeBaseFee(sb.chainConfig, state, header)
 
-	if ok, newValidators, _ := epoch.ShouldEnterNewEpoch(header.Number.Uint64(), state); ok {
+	accumulateRewards(sb.chainConfig, state, header, epoch, totalGasFee, baseFeeReward)
+
+	if ok, newValidators, changes, _ := epoch.ShouldEnterNewEpoch(header.Number.Uint64(), state, neatFork); ok {
 		ops.Append(&ntcTypes.SwitchEpochOp{
 			ChainId:       sb.chainConfig.NeatChainId,
 			NewValidators: newValidators,
+			Changes:       changes,
 		})
 
 	}
@@ -614,7 +654,28 @@ func writeCommittedSeals(h *types.Header, ncExtra *ntcTypes.NeatConExtra) error
 	return nil
 }
 
-func accumulateRewards(config *params.ChainConfig, state *state.StateDB, header *types.Header, ep *epoch.Epoch, totalGasFee *big.Int) {
+// distributeBaseFee credits the base fee paid by the transactions of the block
+// to the configured destination, the base fee is burned by default. The part
+// going to the validator reward of the block is returned.
+func distributeBaseFee(config *params.ChainConfig, state *state.StateDB, header *types.Header) *big.Int {
+	if header.BaseFee == nil || header.GasUsed == 0 {
+		return nil
+	}
+	baseFee := new(big.Int).Mul(header.BaseFee, new(big.Int).SetUint64(header.GasUsed))
+
+	switch config.BaseFeeDestination {
+	case params.BaseFeeToFoundation:
+		state.AddBalance(foundationAddress, baseFee)
+	case params.BaseFeeToRewardPool:
+		if config.IsMainChain() {
+			return baseFee
+		}
+		state.AddBalance(ntcTypes.SideChainRewardAddress, baseFee)
+	}
+	return nil
+}
+
+func accumulateRewards(config *params.ChainConfig, state *state.StateDB, header *types.Header, ep *epoch.Epoch, totalGasFee, baseFeeReward *big.Int) {
 	tenPercentGasFee := big.NewInt(0).Div(totalGasFee, big.NewInt(10))
 	state.AddBalance(foundationAddress, tenPercentGasFee)
 
@@ -639,12 +700,12 @@ func accumulateRewards(config *params.ChainConfig, state *state.StateDB, header
 	} else {
 		rewardPerBlock := state.GetSideChainRewardPerBlock()
 		if rewardPerBlock != nil && rewardPerBlock.Sign() == 1 {
-			sideChainRewardBalance := state.GetBalance(sideChainRewardAddress)
+			sideChainRewardBalance := state.GetBalance(ntcTypes.SideChainRewardAddress)
 			if sideChainRewardBalance.Cmp(rewardPerBlock) == -1 {
 				rewardPerBlock = sideChainRewardBalance
 			}
 
-			state.SubBalance(sideChainRewardAddress, rewardPerBlock)
+			state.SubBalance(ntcTypes.SideChainRewardAddress, rewardPerBlock)
 
 			coinbaseReward = new(big.Int).Add(rewardPerBlock, tenPercentGasFee)
 		} else {
@@ -652,6 +713,10 @@ func accumulateRewards(config *params.ChainConfig, state *state.StateDB, header
 		}
 	}
 
+	if baseFeeReward != nil {
+		coinbaseReward = new(big.Int).Add(coinbaseReward, baseFeeReward)
+	}
+
 	selfDeposit := state.GetDepositBalance(header.Coinbase)
 	totalProxiedDeposit := state.GetTotalDepositProxiedBalance(header.Coinbase)
 	totalDeposit := new(big.Int).Add(selfDeposit, totalProxiedDeposit)
@@ -711,3 +776,122 @@ func accumulateRewards(config *params.ChainConfig, state *state.StateDB, header
 	}
 
 }
+
+// slashDoubleSigners executes the double sign evidence carried by the
+// ReportDoubleSign transactions of the block. The evidence has already been
+// verified by the apply callback, so only the first report of an offender
+// takes effect. The offender is tombstoned and can never be unbanned.
+func slashDoubleSigners(state *state.StateDB, height uint64, txs []*types.Transaction, logger log.Logger) {
+	for _, tx := range txs {
+		if !neatAbi.IsNeatChainContractAddr(tx.To()) || len(tx.Data()) < 4 {
+			continue
+		}
+
+		data := tx.Data()
+		function, err := neatAbi.FunctionTypeFromId(data[:4])
+		if err != nil || function != neatAbi.ReportDoubleSign {
+			continue
+		}
+
+		var args neatAbi.ReportDoubleSignArgs
+		if err := neatAbi.ChainABI.UnpackMethodInputs(&args, neatAbi.ReportDoubleSign.String(), data[4:]); err != nil {
+			continue
+		}
+		ev, err := ntcTypes.DecodeDuplicateVoteEvidence(args.Evidence)
+		if err != nil {
+			continue
+		}
+
+		offender := ev.Address()
+		if state.IsTombstoned(offender) {
+			continue
+		}
+
+		slashed := slashValidator(state, offender)
+		state.MarkAddressBanned(offender)
+		state.MarkAddressJailed(offender, height, true)
+
+		logger.Infof("NeatCon Finalize, validator %x double signed at height %v, slashed %v", offender, ev.Height(), slashed)
+	}
+}
+
+// markMissedValidators counts the validators absent from the commit of the
+// parent block, and bans those reaching the downtime threshold of the reward
+// scheme. The banned validators are removed when entering the next epoch.
+func (sb *backend) markMissedValidators(chain consensus.ChainReader, header *types.Header, state *state.StateDB, ep *epoch.Epoch) {
+	if ep == nil || ep.GetRewardScheme() == nil || ep.GetRewardScheme().DowntimeThreshold == 0 {
+		return
+	}
+
+	// the first block of the epoch carries the last commit of the previous epoch
+	number := header.Number.Uint64()
+	if number <= ep.StartBlock {
+		return
+	}
+
+	parent := chain.GetHeader(header.ParentHash, number-1)
+	if parent == nil {
+		return
+	}
+	ncExtra, err := ntcTypes.ExtractNeatConExtra(parent)
+	if err != nil || ncExtra.SeenCommit == nil || ncExtra.SeenCommit.BitArray == nil {
+		return
+	}
+
+	seen := ncExtra.SeenCommit.BitArray
+	if int(seen.Size()) != ep.Validators.Size() {
+		sb.logger.Warnf("NeatCon Finalize, commit of block %v does not match the validator set", number-1)
+		return
+	}
+
+	threshold := ep.GetRewardScheme().DowntimeThreshold
+	bannedSet := state.GetBannedSet()
+	for i, v := range ep.Validators.Validators {
+		if seen.GetIndex(uint64(i)) {
+			continue
+		}
+
+		vAddr := common.BytesToAddress(v.Address)
+		if _, banned := bannedSet[vAddr]; banned {
+			continue
+		}
+
+		if missed := state.MarkMissedBlock(vAddr); missed >= threshold {
+			state.MarkAddressBanned(vAddr)
+			state.MarkAddressJailed(vAddr, number, false)
+			sb.logger.Infof("NeatCon Finalize, validator %x missed %v blocks in epoch %v, banned", vAddr, missed, ep.Number)
+		}
+	}
+}
+
+func slashValidator(state *state.StateDB, offender common.Address) *big.Int {
+	totalSlashed := new(big.Int)
+
+	selfSlash := new(big.Int).Mul(state.GetDepositBalance(offender), big.NewInt(doubleSignSlashPercent))
+	selfSlash.Quo(selfSlash, big.NewInt(100))
+	if selfSlash.Sign() == 1 {
+		state.SubDepositBalance(offender, selfSlash)
+		totalSlashed.Add(totalSlashed, selfSlash)
+	}
+
+	state.ForEachProxied(offender, func(key common.Address, proxiedBalance, depositProxiedBalance, pendingRefundBalance *big.Int) bool {
+		if depositProxiedBalance.Sign() == 1 {
+			slash := new(big.Int).Mul(depositProxiedBalance, big.NewInt(doubleSignSlashPercent))
+			slash.Quo(slash, big.NewInt(100))
+
+			state.SubDepositProxiedBalanceByUser(offender, key, slash)
+			state.SubDelegateBalance(key, slash)
+
+			// the pending refund can not exceed what is left after slashing
+			remaining := new(big.Int).Sub(depositProxiedBalance, slash)
+			if pendingRefundBalance.Cmp(remaining) == 1 {
+				state.SubPendingRefundBalanceByUser(offender, key, new(big.Int).Sub(pendingRefundBalance, remaining))
+			}
+
+			totalSlashed.Add(totalSlashed, slash)
+		}
+		return true
+	})
+
+	return totalSlashed
+}
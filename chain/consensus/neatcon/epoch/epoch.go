@@ -9,6 +9,7 @@ import (
 	"github.com/neatio-net/neatio/chain/core/state"
 	"github.com/neatio-net/neatio/chain/log"
 	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/common/hexutil"
 	"github.com/neatio-net/wire-go"
 
 	"math/big"
@@ -32,6 +33,11 @@ const (
 
 	epochKey       = "Epoch:%v"
 	latestEpochKey = "LatestEpoch"
+
+	// the vote hash for the next epoch is sent before 75% of the epoch,
+	// and revealed after that, before 85% of the epoch
+	hashVoteEndPercent   = 75
+	revealVoteEndPercent = 85
 )
 
 type Epoch struct {
@@ -242,6 +248,24 @@ func (epoch *Epoch) ProposeNextEpoch(lastBlockHeight uint64, lastBlockTime time.
 	return nil
 }
 
+func (epoch *Epoch) GetVoteEndHeight() uint64 {
+	return epoch.StartBlock + (epoch.EndBlock-epoch.StartBlock)*hashVoteEndPercent/100
+}
+
+func (epoch *Epoch) GetRevealVoteEndHeight() uint64 {
+	return epoch.StartBlock + (epoch.EndBlock-epoch.StartBlock)*revealVoteEndPercent/100
+}
+
+// CheckInHashVoteStage returns true if the vote hash for the next epoch can be sent at the height
+func (epoch *Epoch) CheckInHashVoteStage(height uint64) bool {
+	return height >= epoch.StartBlock && height <= epoch.GetVoteEndHeight()
+}
+
+// CheckInRevealVoteStage returns true if the votes for the next epoch can be revealed at the height
+func (epoch *Epoch) CheckInRevealVoteStage(height uint64) bool {
+	return height > epoch.GetVoteEndHeight() && height <= epoch.GetRevealVoteEndHeight()
+}
+
 func (epoch *Epoch) GetNextEpoch() *Epoch {
 	if epoch.nextEpoch == nil {
 		epoch.nextEpoch = loadOneEpoch(epoch.db, epoch.Number+1, epoch.logger)
@@ -266,7 +290,7 @@ func (epoch *Epoch) GetPreviousEpoch() *Epoch {
 	return epoch.previousEpoch
 }
 
-func (epoch *Epoch) ShouldEnterNewEpoch(height uint64, state *state.StateDB) (bool, *ncTypes.ValidatorSet, error) {
+func (epoch *Epoch) ShouldEnterNewEpoch(height uint64, state *state.StateDB, neatFork bool) (bool, *ncTypes.ValidatorSet, []*ncTypes.ValidatorChange, error) {
 
 	if height == epoch.EndBlock {
 		epoch.nextEpoch = epoch.GetNextEpoch()
@@ -288,6 +312,14 @@ func (epoch *Epoch) ShouldEnterNewEpoch(height uint64, state *state.StateDB) (bo
 			}
 			state.ClearDelegateRefundSet()
 
+			if neatFork {
+				applyPendingRedelegations(state)
+			}
+			var compounded []common.Address
+			if neatFork {
+				compounded = applyAutoCompound(state)
+			}
+
 			var (
 				refunds []*ncTypes.RefundValidatorAmount
 			)
@@ -300,6 +332,10 @@ func (epoch *Epoch) ShouldEnterNewEpoch(height uint64, state *state.StateDB) (bo
 				nextEpochVoteSet = NewEpochValidatorVoteSet()
 				epoch.logger.Debugf("Should enter new epoch, next epoch vote set is nil, %v", nextEpochVoteSet)
 			}
+			if neatFork {
+				updateCompoundedVotes(state, nextEpochVoteSet, compounded)
+				nextEpochVoteSet = filterBannedVotes(state, nextEpochVoteSet)
+			}
 
 			for i := 0; i < len(newValidators.Validators); i++ {
 				v := newValidators.Validators[i]
@@ -321,9 +357,19 @@ func (epoch *Epoch) ShouldEnterNewEpoch(height uint64, state *state.StateDB) (bo
 
 			if err != nil {
 				epoch.logger.Warn("Error changing validator set", "error", err)
-				return false, nil, err
+				return false, nil, nil, err
 			}
 			refunds = append(refunds, refundsUpdate...)
+			var changes []*ncTypes.ValidatorChange
+			if neatFork {
+				refunds = append(refunds, removeBannedValidators(state, newValidators)...)
+				changes = diffValidatorSets(epoch.Validators, newValidators, refunds, state.GetBannedSet())
+
+				// missed blocks are counted per epoch
+				state.ClearMissedBlocks()
+			} else {
+				changes = diffValidatorSets(epoch.Validators, newValidators, refunds, nil)
+			}
 
 			for _, v := range newValidators.Validators {
 				vAddr := common.BytesToAddress(v.Address)
@@ -358,12 +404,12 @@ func (epoch *Epoch) ShouldEnterNewEpoch(height uint64, state *state.StateDB) (bo
 				}
 			}
 
-			return true, newValidators, nil
+			return true, newValidators, changes, nil
 		} else {
-			return false, nil, NextEpochNotExist
+			return false, nil, nil, NextEpochNotExist
 		}
 	}
-	return false, nil, nil
+	return false, nil, nil, nil
 }
 
 func compareAddress(addrA, addrB []byte) bool {
@@ -374,7 +420,7 @@ func compareAddress(addrA, addrB []byte) bool {
 	}
 }
 
-func (epoch *Epoch) EnterNewEpoch(newValidators *ncTypes.ValidatorSet) (*Epoch, error) {
+func (epoch *Epoch) EnterNewEpoch(newValidators *ncTypes.ValidatorSet, changes []*ncTypes.ValidatorChange) (*Epoch, error) {
 	if epoch.nextEpoch != nil {
 		now := time.Now()
 
@@ -390,6 +436,7 @@ func (epoch *Epoch) EnterNewEpoch(newValidators *ncTypes.ValidatorSet) (*Epoch,
 
 		nextEpoch.nextEpoch = nil
 		nextEpoch.Save()
+		SaveEpochValidatorChanges(epoch.db, nextEpoch.Number, changes)
 		epoch.logger.Infof("Enter into New Epoch %v", nextEpoch)
 		return nextEpoch, nil
 	} else {
@@ -419,8 +466,115 @@ func DryRunUpdateEpochValidatorSet(state *state.StateDB, validators *ncTypes.Val
 		voteSet = NewEpochValidatorVoteSet()
 	}
 
-	_, err := updateEpochValidatorSet(validators, voteSet)
-	return err
+	_, err := updateEpochValidatorSet(validators, filterBannedVotes(state, voteSet))
+	if err != nil {
+		return err
+	}
+	removeBannedValidators(state, validators)
+	return nil
+}
+
+// applyPendingRedelegations moves the bonded delegations to the new candidates,
+// the delegation is refunded if the new candidate is gone
+func applyPendingRedelegations(state *state.StateDB) {
+	for _, r := range state.GetPendingRedelegations() {
+		amount := r.Amount
+		remaining := new(big.Int).Sub(state.GetDepositProxiedBalanceByUser(r.From, r.Delegator), state.GetPendingRefundBalanceByUser(r.From, r.Delegator))
+		if amount.Cmp(remaining) == 1 {
+			amount = remaining
+		}
+		if amount.Sign() <= 0 {
+			continue
+		}
+
+		state.SubDepositProxiedBalanceByUser(r.From, r.Delegator, amount)
+		if state.IsCandidate(r.To) {
+			state.AddDepositProxiedBalanceByUser(r.To, r.Delegator, amount)
+		} else {
+			state.SubDelegateBalance(r.Delegator, amount)
+			state.AddBalance(r.Delegator, amount)
+		}
+	}
+	state.ClearPendingRedelegations()
+}
+
+// applyAutoCompound delegates the rewards of the flagged delegations again to
+// the candidates, the flags of the delegations gone are dropped. It returns
+// the candidates whose proxied balance changed
+func applyAutoCompound(state *state.StateDB) []common.Address {
+	var compounded []common.Address
+	seen := make(map[common.Address]bool)
+	for _, c := range state.GetAutoCompoundSet() {
+		stake := new(big.Int).Add(state.GetProxiedBalanceByUser(c.Candidate, c.Delegator), state.GetDepositProxiedBalanceByUser(c.Candidate, c.Delegator))
+		stake.Sub(stake, state.GetPendingRefundBalanceByUser(c.Candidate, c.Delegator))
+		if !state.IsCandidate(c.Candidate) || stake.Sign() <= 0 {
+			state.SetAutoCompound(c.Delegator, c.Candidate, false)
+			continue
+		}
+
+		reward := state.GetRewardBalanceByDelegateAddress(c.Delegator, c.Candidate)
+		if reward.Sign() <= 0 {
+			continue
+		}
+		reward = new(big.Int).Set(reward)
+		state.SubRewardBalanceByDelegateAddress(c.Delegator, c.Candidate, reward)
+		state.AddDelegateBalance(c.Delegator, reward)
+		state.AddProxiedBalanceByUser(c.Candidate, c.Delegator, reward)
+		if !seen[c.Candidate] {
+			seen[c.Candidate] = true
+			compounded = append(compounded, c.Candidate)
+		}
+	}
+	return compounded
+}
+
+// updateCompoundedVotes updates the amount of the next epoch votes of the
+// compounded candidates like a delegation does, so the compounded rewards
+// count as voting power of the new epoch. The revealed vote is kept as is
+// otherwise, no vote is made up for a candidate who did not vote
+func updateCompoundedVotes(state *state.StateDB, voteSet *EpochValidatorVoteSet, candidates []common.Address) {
+	for _, candidate := range candidates {
+		if vote, exist := voteSet.GetVoteByAddress(candidate); exist {
+			vote.Amount = new(big.Int).Add(state.GetTotalProxiedBalance(candidate), state.GetTotalDepositProxiedBalance(candidate))
+		}
+	}
+}
+
+// filterBannedVotes drops the votes of banned addresses, so a jailed
+// validator can not come back through the next epoch vote
+func filterBannedVotes(state *state.StateDB, voteSet *EpochValidatorVoteSet) *EpochValidatorVoteSet {
+	bannedSet := state.GetBannedSet()
+	if len(bannedSet) == 0 {
+		return voteSet
+	}
+
+	filtered := NewEpochValidatorVoteSet()
+	for _, v := range voteSet.Votes {
+		if _, banned := bannedSet[v.Address]; !banned {
+			filtered.StoreVote(v)
+		}
+	}
+	return filtered
+}
+
+// removeBannedValidators removes the jailed validators from the new validator
+// set and returns their refunds, at least MinimumValidatorsSize validators are kept
+func removeBannedValidators(state *state.StateDB, validators *ncTypes.ValidatorSet) []*ncTypes.RefundValidatorAmount {
+	var refund []*ncTypes.RefundValidatorAmount
+
+	bannedSet := state.GetBannedSet()
+	for i := 0; i < len(validators.Validators) && validators.Size() > MinimumValidatorsSize; i++ {
+		v := validators.Validators[i]
+		vAddr := common.BytesToAddress(v.Address)
+		if _, banned := bannedSet[vAddr]; !banned {
+			continue
+		}
+		if _, removed := validators.Remove(v.Address); removed {
+			refund = append(refund, &ncTypes.RefundValidatorAmount{Address: vAddr, Amount: nil, Voteout: true})
+			i--
+		}
+	}
+	return refund
 }
 
 func updateEpochValidatorSet(validators *ncTypes.ValidatorSet, voteSet *EpochValidatorVoteSet) ([]*ncTypes.RefundValidatorAmount, error) {
@@ -713,6 +867,29 @@ func (epoch *Epoch) String() string {
 	)
 }
 
+// ToEpochApi returns the epoch in the form of the rpc apis
+func (epoch *Epoch) ToEpochApi() *ncTypes.EpochApi {
+	validators := make([]*ncTypes.EpochValidator, len(epoch.Validators.Validators))
+	for i, val := range epoch.Validators.Validators {
+		validators[i] = &ncTypes.EpochValidator{
+			Address:        common.BytesToAddress(val.Address),
+			PubKey:         val.PubKey.KeyString(),
+			Amount:         (*hexutil.Big)(val.VotingPower),
+			RemainingEpoch: hexutil.Uint64(val.RemainingEpoch),
+		}
+	}
+
+	return &ncTypes.EpochApi{
+		Number:         hexutil.Uint64(epoch.Number),
+		RewardPerBlock: (*hexutil.Big)(epoch.RewardPerBlock),
+		StartBlock:     hexutil.Uint64(epoch.StartBlock),
+		EndBlock:       hexutil.Uint64(epoch.EndBlock),
+		StartTime:      epoch.StartTime,
+		EndTime:        epoch.EndTime,
+		Validators:     validators,
+	}
+}
+
 func UpdateEpochEndTime(db dbm.DB, epNumber uint64, endTime time.Time) {
 	ep := loadOneEpoch(db, epNumber, nil)
 	if ep != nil {
@@ -0,0 +1,112 @@
+package epoch
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/neatio-net/db-go"
+	ncTypes "github.com/neatio-net/neatio/chain/consensus/neatcon/types"
+	"github.com/neatio-net/neatio/chain/core/state"
+	"github.com/neatio-net/neatio/chain/log"
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/wire-go"
+)
+
+var ErrEpochNotFound = errors.New("no epoch found for the block height")
+
+func calcEpochValidatorChangesKey(epochNumber uint64) []byte {
+	return []byte(fmt.Sprintf("EpochValidatorChanges_%v", epochNumber))
+}
+
+// EpochValidatorChanges are the validators joining or leaving the validator
+// set at the start of the epoch. They are kept apart from the epoch record so
+// the epochs saved by older versions can still be decoded.
+type EpochValidatorChanges struct {
+	Changes []*ncTypes.ValidatorChange
+}
+
+func SaveEpochValidatorChanges(epochDB db.DB, epochNumber uint64, changes []*ncTypes.ValidatorChange) {
+	epochDB.SetSync(calcEpochValidatorChangesKey(epochNumber), wire.BinaryBytes(EpochValidatorChanges{Changes: changes}))
+}
+
+// LoadEpochValidatorChanges returns nil if the changes of the epoch were not recorded
+func LoadEpochValidatorChanges(epochDB db.DB, epochNumber uint64) []*ncTypes.ValidatorChange {
+	data := epochDB.Get(calcEpochValidatorChangesKey(epochNumber))
+	if len(data) == 0 {
+		return nil
+	}
+	var changes EpochValidatorChanges
+	if err := wire.ReadBinaryBytes(data, &changes); err != nil {
+		log.Error("Load Epoch Validator Changes failed", "error", err)
+		return nil
+	}
+	if changes.Changes == nil {
+		changes.Changes = make([]*ncTypes.ValidatorChange, 0)
+	}
+	return changes.Changes
+}
+
+// diffValidatorSets compares the validator sets of two epochs, the reason a
+// validator left comes from its refund at the epoch switch
+func diffValidatorSets(oldSet, newSet *ncTypes.ValidatorSet, refunds []*ncTypes.RefundValidatorAmount, bannedSet state.BannedSet) []*ncTypes.ValidatorChange {
+	refundByAddress := make(map[common.Address]*ncTypes.RefundValidatorAmount, len(refunds))
+	for _, r := range refunds {
+		refundByAddress[r.Address] = r
+	}
+
+	changes := make([]*ncTypes.ValidatorChange, 0)
+	for _, v := range oldSet.Validators {
+		if newSet.HasAddress(v.Address) {
+			continue
+		}
+		vAddr := common.BytesToAddress(v.Address)
+		reason := ncTypes.ValidatorNoStake
+		if r, ok := refundByAddress[vAddr]; ok {
+			if _, banned := bannedSet[vAddr]; banned && r.Voteout {
+				reason = ncTypes.ValidatorBanned
+			} else if r.Voteout {
+				reason = ncTypes.ValidatorVotedOut
+			} else {
+				reason = ncTypes.ValidatorWithdrawn
+			}
+		}
+		changes = append(changes, &ncTypes.ValidatorChange{Address: vAddr, Reason: reason, VotingPower: v.VotingPower})
+	}
+	for _, v := range newSet.Validators {
+		if !oldSet.HasAddress(v.Address) {
+			changes = append(changes, &ncTypes.ValidatorChange{Address: common.BytesToAddress(v.Address), Reason: ncTypes.ValidatorJoined, VotingPower: v.VotingPower})
+		}
+	}
+	return changes
+}
+
+// FindEpochByBlockNumber looks up the epoch containing the block among the
+// epochs up to this one, unlike GetEpochByBlockNumber it does a binary search
+func (epoch *Epoch) FindEpochByBlockNumber(blockNumber uint64) (*Epoch, error) {
+	if blockNumber > epoch.EndBlock {
+		return nil, ErrEpochNotFound
+	}
+	if blockNumber >= epoch.StartBlock {
+		return epoch, nil
+	}
+
+	var loadErr error
+	n := sort.Search(int(epoch.Number), func(i int) bool {
+		ep := loadOneEpoch(epoch.db, uint64(i), epoch.logger)
+		if ep == nil {
+			loadErr = fmt.Errorf("epoch %v not found in the epoch db", i)
+			return true
+		}
+		return ep.EndBlock >= blockNumber
+	})
+	if loadErr != nil {
+		return nil, loadErr
+	}
+
+	ep := loadOneEpoch(epoch.db, uint64(n), epoch.logger)
+	if ep == nil || blockNumber < ep.StartBlock || blockNumber > ep.EndBlock {
+		return nil, ErrEpochNotFound
+	}
+	return ep, nil
+}
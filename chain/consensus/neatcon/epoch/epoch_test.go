@@ -7,6 +7,10 @@ import (
 	"testing"
 	"time"
 
+	dbm "github.com/neatio-net/db-go"
+	ncTypes "github.com/neatio-net/neatio/chain/consensus/neatcon/types"
+	"github.com/neatio-net/neatio/chain/core/rawdb"
+	"github.com/neatio-net/neatio/chain/core/state"
 	"github.com/neatio-net/neatio/utilities/common"
 )
 
@@ -69,3 +73,191 @@ func TestVoteSetCompare(t *testing.T) {
 		fmt.Printf("address:%v, amount: %v\n", voteArr[i].Address, voteArr[i].Amount)
 	}
 }
+
+func TestVoteStages(t *testing.T) {
+	ep := &Epoch{StartBlock: 100, EndBlock: 200}
+
+	if !ep.CheckInHashVoteStage(100) || !ep.CheckInHashVoteStage(175) || ep.CheckInHashVoteStage(176) {
+		t.Errorf("hash vote stage should be [100, 175]")
+	}
+	if ep.CheckInRevealVoteStage(175) || !ep.CheckInRevealVoteStage(176) || !ep.CheckInRevealVoteStage(185) || ep.CheckInRevealVoteStage(186) {
+		t.Errorf("reveal vote stage should be [176, 185]")
+	}
+}
+
+func TestValidatorChanges(t *testing.T) {
+	addrA := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	addrB := common.HexToAddress("0x000000000000000000000000000000000000000b")
+	addrC := common.HexToAddress("0x000000000000000000000000000000000000000c")
+	addrD := common.HexToAddress("0x000000000000000000000000000000000000000d")
+	addrE := common.HexToAddress("0x000000000000000000000000000000000000000e")
+	addrF := common.HexToAddress("0x000000000000000000000000000000000000000f")
+
+	oldSet := ncTypes.NewValidatorSet([]*ncTypes.Validator{
+		ncTypes.NewValidator(addrA.Bytes(), nil, big.NewInt(10)),
+		ncTypes.NewValidator(addrB.Bytes(), nil, big.NewInt(10)),
+		ncTypes.NewValidator(addrC.Bytes(), nil, big.NewInt(10)),
+		ncTypes.NewValidator(addrD.Bytes(), nil, big.NewInt(10)),
+		ncTypes.NewValidator(addrE.Bytes(), nil, big.NewInt(10)),
+	})
+	newSet := ncTypes.NewValidatorSet([]*ncTypes.Validator{
+		ncTypes.NewValidator(addrA.Bytes(), nil, big.NewInt(10)),
+		ncTypes.NewValidator(addrF.Bytes(), nil, big.NewInt(20)),
+	})
+	refunds := []*ncTypes.RefundValidatorAmount{
+		{Address: addrB, Voteout: true},
+		{Address: addrC, Voteout: true},
+		{Address: addrD, Amount: big.NewInt(10), Voteout: false},
+	}
+	bannedSet := state.BannedSet{addrC: struct{}{}}
+
+	want := map[common.Address]string{
+		addrB: ncTypes.ValidatorVotedOut,
+		addrC: ncTypes.ValidatorBanned,
+		addrD: ncTypes.ValidatorWithdrawn,
+		addrE: ncTypes.ValidatorNoStake,
+		addrF: ncTypes.ValidatorJoined,
+	}
+	changes := diffValidatorSets(oldSet, newSet, refunds, bannedSet)
+	if len(changes) != len(want) {
+		t.Fatalf("expected %v changes, got %v", len(want), len(changes))
+	}
+	for _, c := range changes {
+		if want[c.Address] != c.Reason {
+			t.Errorf("validator %x, expected reason %v, got %v", c.Address, want[c.Address], c.Reason)
+		}
+	}
+
+	db := dbm.NewMemDB()
+	if LoadEpochValidatorChanges(db, 1) != nil {
+		t.Errorf("expected nil for the epoch without recorded changes")
+	}
+	SaveEpochValidatorChanges(db, 1, changes)
+	loaded := LoadEpochValidatorChanges(db, 1)
+	if len(loaded) != len(changes) || loaded[4].Address != addrF || loaded[4].VotingPower.Cmp(big.NewInt(20)) != 0 {
+		t.Errorf("validator changes mismatch, got %v", loaded)
+	}
+}
+
+func TestFindEpochByBlockNumber(t *testing.T) {
+	db := dbm.NewMemDB()
+	var cur *Epoch
+	for i := uint64(0); i < 5; i++ {
+		cur = &Epoch{db: db, Number: i, StartBlock: i*100 + 1, EndBlock: (i + 1) * 100, RewardPerBlock: big.NewInt(1), Validators: ncTypes.NewValidatorSet(nil)}
+		cur.Save()
+	}
+
+	for _, height := range []uint64{1, 100, 101, 250, 399, 401, 500} {
+		ep, err := cur.FindEpochByBlockNumber(height)
+		if err != nil {
+			t.Fatalf("find epoch of block %v error %v", height, err)
+		}
+		if height < ep.StartBlock || height > ep.EndBlock {
+			t.Errorf("block %v not in epoch %v [%v, %v]", height, ep.Number, ep.StartBlock, ep.EndBlock)
+		}
+	}
+	if _, err := cur.FindEpochByBlockNumber(501); err != ErrEpochNotFound {
+		t.Errorf("expected %v for a future block, got %v", ErrEpochNotFound, err)
+	}
+}
+
+func TestApplyPendingRedelegations(t *testing.T) {
+	st, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+
+	user := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	candA := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	candB := common.HexToAddress("0x000000000000000000000000000000000000000b")
+	candC := common.HexToAddress("0x000000000000000000000000000000000000000c")
+	st.ApplyForCandidate(candA, "", 10)
+	st.ApplyForCandidate(candB, "", 10)
+
+	st.AddDelegateBalance(user, big.NewInt(100))
+	st.AddDepositProxiedBalanceByUser(candA, user, big.NewInt(100))
+	st.AddPendingRedelegation(user, candA, candB, big.NewInt(60))
+	st.AddPendingRedelegation(user, candA, candC, big.NewInt(30))
+
+	applyPendingRedelegations(st)
+
+	if got := st.GetDepositProxiedBalanceByUser(candA, user); got.Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("deposit left with %x, got %v want 10", candA, got)
+	}
+	if got := st.GetDepositProxiedBalanceByUser(candB, user); got.Cmp(big.NewInt(60)) != 0 {
+		t.Errorf("deposit moved to %x, got %v want 60", candB, got)
+	}
+	// candidate C is gone, the delegation is refunded
+	if got := st.GetBalance(user); got.Cmp(big.NewInt(30)) != 0 {
+		t.Errorf("refund for the redelegation to a non candidate, got %v want 30", got)
+	}
+	if got := st.GetDelegateBalance(user); got.Cmp(big.NewInt(70)) != 0 {
+		t.Errorf("delegate balance, got %v want 70", got)
+	}
+	if len(st.GetPendingRedelegations()) != 0 {
+		t.Errorf("pending redelegations not cleared")
+	}
+}
+
+func TestApplyAutoCompound(t *testing.T) {
+	st, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+
+	user := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	candA := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	candB := common.HexToAddress("0x000000000000000000000000000000000000000b")
+	st.ApplyForCandidate(candA, "", 10)
+
+	st.AddDelegateBalance(user, big.NewInt(100))
+	st.AddDepositProxiedBalanceByUser(candA, user, big.NewInt(100))
+	st.AddRewardBalanceByDelegateAddress(user, candA, big.NewInt(7))
+	st.AddRewardBalanceByDelegateAddress(user, candB, big.NewInt(3))
+	st.SetAutoCompound(user, candA, true)
+	st.SetAutoCompound(user, candB, true)
+
+	compounded := applyAutoCompound(st)
+	if len(compounded) != 1 || compounded[0] != candA {
+		t.Fatalf("compounded candidates, got %v want [%x]", compounded, candA)
+	}
+
+	if got := st.GetProxiedBalanceByUser(candA, user); got.Cmp(big.NewInt(7)) != 0 {
+		t.Errorf("compounded reward, got %v want 7", got)
+	}
+	if got := st.GetRewardBalanceByDelegateAddress(user, candA); got.Sign() != 0 {
+		t.Errorf("reward left after compounding, got %v", got)
+	}
+	if got := st.GetDelegateBalance(user); got.Cmp(big.NewInt(107)) != 0 {
+		t.Errorf("delegate balance, got %v want 107", got)
+	}
+	// candidate B is not a candidate, the reward stays and the flag is dropped
+	if got := st.GetRewardBalanceByDelegateAddress(user, candB); got.Cmp(big.NewInt(3)) != 0 {
+		t.Errorf("reward of %x, got %v want 3", candB, got)
+	}
+	if st.IsAutoCompound(user, candB) || !st.IsAutoCompound(user, candA) {
+		t.Errorf("auto compound flags mismatch, got %v", st.GetAutoCompoundByUser(user))
+	}
+}
+
+func TestUpdateCompoundedVotes(t *testing.T) {
+	st, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+
+	user := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	candA := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	candB := common.HexToAddress("0x000000000000000000000000000000000000000b")
+	candC := common.HexToAddress("0x000000000000000000000000000000000000000c")
+	for _, cand := range []common.Address{candA, candB, candC} {
+		st.ApplyForCandidate(cand, "", 10)
+		st.AddDepositProxiedBalanceByUser(cand, user, big.NewInt(100))
+		st.AddProxiedBalanceByUser(cand, user, big.NewInt(7))
+	}
+
+	voteSet := NewEpochValidatorVoteSet()
+	voteSet.StoreVote(&EpochValidatorVote{Address: candA, Amount: big.NewInt(50), Salt: "secret"})
+	updateCompoundedVotes(st, voteSet, []common.Address{candA, candB, candC})
+
+	if vote, _ := voteSet.GetVoteByAddress(candA); vote.Amount.Cmp(big.NewInt(107)) != 0 || vote.Salt != "secret" {
+		t.Errorf("vote of %x, got %v want 107 with the revealed salt", candA, vote)
+	}
+	// candidates B and C did not vote, no vote is made up for them
+	for _, cand := range []common.Address{candB, candC} {
+		if _, exist := voteSet.GetVoteByAddress(cand); exist {
+			t.Errorf("vote of %x added", cand)
+		}
+	}
+}
@@ -21,6 +21,15 @@ type RewardScheme struct {
 	RewardFirstYear    *big.Int
 	EpochNumberPerYear uint64
 	TotalMintingYears  uint64
+	DowntimeThreshold  uint64
+}
+
+// legacyRewardScheme is the layout stored before DowntimeThreshold was added
+type legacyRewardScheme struct {
+	TotalReward        *big.Int
+	RewardFirstYear    *big.Int
+	EpochNumberPerYear uint64
+	TotalMintingYears  uint64
 }
 
 func LoadRewardScheme(db dbm.DB) *RewardScheme {
@@ -31,8 +40,17 @@ func LoadRewardScheme(db dbm.DB) *RewardScheme {
 		rs := &RewardScheme{}
 		err := wire.ReadBinaryBytes(buf, rs)
 		if err != nil {
-			log.Errorf("LoadRewardScheme Failed, error: %v", err)
-			return nil
+			legacy := &legacyRewardScheme{}
+			if legacyErr := wire.ReadBinaryBytes(buf, legacy); legacyErr != nil {
+				log.Errorf("LoadRewardScheme Failed, error: %v", err)
+				return nil
+			}
+			rs = &RewardScheme{
+				TotalReward:        legacy.TotalReward,
+				RewardFirstYear:    legacy.RewardFirstYear,
+				EpochNumberPerYear: legacy.EpochNumberPerYear,
+				TotalMintingYears:  legacy.TotalMintingYears,
+			}
 		}
 		return rs
 	}
@@ -46,6 +64,7 @@ func MakeRewardScheme(db dbm.DB, rsDoc *ncTypes.RewardSchemeDoc) *RewardScheme {
 		RewardFirstYear:    rsDoc.RewardFirstYear,
 		EpochNumberPerYear: rsDoc.EpochNumberPerYear,
 		TotalMintingYears:  rsDoc.TotalMintingYears,
+		DowntimeThreshold:  rsDoc.DowntimeThreshold,
 	}
 
 	return rs
@@ -63,8 +82,10 @@ func (rs *RewardScheme) String() string {
 		"totalReward : %v,\n"+
 		"rewardFirstYear : %v,\n"+
 		"epochNumberPerYear : %v,\n"+
+		"downtimeThreshold : %v,\n"+
 		"}",
 		rs.TotalReward,
 		rs.RewardFirstYear,
-		rs.EpochNumberPerYear)
+		rs.EpochNumberPerYear,
+		rs.DowntimeThreshold)
 }
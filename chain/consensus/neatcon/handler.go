@@ -5,13 +5,19 @@ import (
 
 	"github.com/neatio-net/neatio/chain/consensus"
 	ntcTypes "github.com/neatio-net/neatio/chain/consensus/neatcon/types"
+	"github.com/neatio-net/neatio/chain/core"
 	"github.com/neatio-net/neatio/chain/core/types"
 	"github.com/neatio-net/neatio/chain/log"
+	neatAbi "github.com/neatio-net/neatio/neatabi/abi"
+	"github.com/neatio-net/neatio/neatdb"
 	"github.com/neatio-net/neatio/params"
+	"github.com/neatio-net/neatio/utilities/common"
 )
 
 var (
 	errDecodeFailed = errors.New("fail to decode neatcon message")
+
+	errNoTxPool = errors.New("tx pool not set")
 )
 
 func (sb *backend) Protocol() consensus.Protocol {
@@ -61,6 +67,46 @@ func (sb *backend) NewChainHead(block *types.Block) error {
 	return nil
 }
 
+func (sb *backend) SetTxPool(txPool *core.TxPool) {
+
+	sb.txPool = txPool
+}
+
+func (sb *backend) SetSigningIndexer(db neatdb.Database, indexer *core.ChainIndexer) {
+
+	sb.signingDb = db
+	sb.signingIndexer = indexer
+}
+
+// SendChainTx signs a chain function call with prv and adds it to the local tx pool
+func (sb *backend) SendChainTx(input []byte, signer ntcTypes.TxSigner) (common.Hash, error) {
+	if sb.txPool == nil {
+		return common.Hash{}, errNoTxPool
+	}
+
+	function, err := neatAbi.FunctionTypeFromId(input[:4])
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	account, err := signer.TxAccount()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	nonce := sb.txPool.State().GetNonce(account)
+
+	tx := types.NewTransaction(nonce, neatAbi.NeatioSmartContractAddress, nil, function.RequiredGas(), sb.txPool.GasPrice(), input)
+	signedTx, err := signer.SignTx(tx, sb.chainConfig.ChainId)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	if err := sb.txPool.AddLocal(signedTx); err != nil {
+		return common.Hash{}, err
+	}
+	return signedTx.Hash(), nil
+}
+
 func (sb *backend) GetLogger() log.Logger {
 	return sb.logger
 }
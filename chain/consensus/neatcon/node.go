@@ -1,6 +1,7 @@
 package neatcon
 
 import (
+	"crypto/tls"
 	"io/ioutil"
 	"os"
 	"strings"
@@ -33,9 +34,16 @@ type Node struct {
 
 func NewNodeNotStart(backend *backend, config cfg.Config, chainConfig *params.ChainConfig, cch core.CrossChainHelper, genDoc *types.GenesisDoc) *Node {
 	var privValidator *types.PrivValidator
-	privValidatorFile := config.GetString("priv_validator_file")
-	if _, err := os.Stat(privValidatorFile); err == nil {
-		privValidator = types.LoadPrivValidator(privValidatorFile)
+	if signerAddr := config.GetString("priv_validator_laddr"); signerAddr != "" {
+		var err error
+		if privValidator, err = loadRemotePrivValidator(config, signerAddr, backend.logger); err != nil {
+			cmn.Exit(cmn.Fmt("Failed to connect remote signer %v: %v", signerAddr, err))
+		}
+	} else {
+		privValidatorFile := config.GetString("priv_validator_file")
+		if _, err := os.Stat(privValidatorFile); err == nil {
+			privValidator = types.LoadPrivValidator(privValidatorFile)
+		}
 	}
 
 	epochDB := dbm.NewDB("epoch", "leveldb", config.GetString("db_dir"))
@@ -75,6 +83,31 @@ func NewNodeNotStart(backend *backend, config cfg.Config, chainConfig *params.Ch
 	return node
 }
 
+// loadRemotePrivValidator connects the signer process holding the consensus
+// key, tcp connections must be authenticated on both sides with TLS
+func loadRemotePrivValidator(config cfg.Config, signerAddr string, logger log.Logger) (*types.PrivValidator, error) {
+	var tlsConfig *tls.Config
+	if certFile := config.GetString("priv_validator_tls_cert"); certFile != "" {
+		var err error
+		tlsConfig, err = types.LoadSignerTLSConfig(certFile, config.GetString("priv_validator_tls_key"), config.GetString("priv_validator_tls_ca"), false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	signer, err := types.NewRemoteSigner(signerAddr, tlsConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+	privValidator, err := types.NewRemotePrivValidator(signer)
+	if err != nil {
+		signer.Close()
+		return nil, err
+	}
+	logger.Infof("Consensus votes are signed by remote signer %v, validator %x", signerAddr, privValidator.Address)
+	return privValidator, nil
+}
+
 func (n *Node) OnStart() error {
 
 	n.logger.Info("(n *Node) OnStart()")
@@ -105,6 +138,9 @@ func (n *Node) OnStop() {
 
 	n.evsw.Stop()
 	n.consensusReactor.Stop()
+	if n.privValidator != nil {
+		n.privValidator.Close()
+	}
 }
 
 func (n *Node) RunForever() {
@@ -0,0 +1,179 @@
+package neatcon
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/neatio-net/neatio/chain/consensus"
+	"github.com/neatio-net/neatio/chain/consensus/neatcon/epoch"
+	ntcTypes "github.com/neatio-net/neatio/chain/consensus/neatcon/types"
+	"github.com/neatio-net/neatio/chain/core"
+	"github.com/neatio-net/neatio/chain/core/rawdb"
+	"github.com/neatio-net/neatio/chain/core/types"
+	"github.com/neatio-net/neatio/chain/log"
+	"github.com/neatio-net/neatio/neatdb"
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/rlp"
+)
+
+const (
+	signingSectionSize = 128
+
+	// blocks are final once committed, no need to wait for more confirmations
+	signingConfirms = 1
+
+	signingThrottling = 100 * time.Millisecond
+)
+
+var errEpochNotLoaded = errors.New("epoch not loaded yet")
+
+// ValidatorSigningStats counts the blocks a validator proposed, and the
+// blocks it signed or missed the precommit of, within one epoch
+type ValidatorSigningStats struct {
+	Address  common.Address
+	Proposed uint64
+	Signed   uint64
+	Missed   uint64
+}
+
+// SigningIndexer gathers the signing stats of the validators from the
+// committed seals in the headers, one section of blocks at a time
+type SigningIndexer struct {
+	db     neatdb.Database
+	engine consensus.NeatCon
+
+	section uint64
+	epoch   *epoch.Epoch
+	stats   map[uint64]map[common.Address]*ValidatorSigningStats
+	err     error
+}
+
+func NewSigningIndexer(db neatdb.Database, engine consensus.NeatCon) *core.ChainIndexer {
+	backend := &SigningIndexer{
+		db:     db,
+		engine: engine,
+	}
+	table := rawdb.NewTable(db, string(rawdb.SigningStatsIndexPrefix))
+
+	return core.NewChainIndexer(db, table, backend, signingSectionSize, signingConfirms, signingThrottling, "signingstats")
+}
+
+func (s *SigningIndexer) Reset(section uint64, lastSectionHead common.Hash) error {
+	s.section, s.err = section, nil
+	s.stats = make(map[uint64]map[common.Address]*ValidatorSigningStats)
+	return nil
+}
+
+func (s *SigningIndexer) Process(header *types.Header) {
+	number := header.Number.Uint64()
+	if s.err != nil || number == 0 {
+		return
+	}
+
+	if s.epoch == nil || number < s.epoch.StartBlock || number > s.epoch.EndBlock {
+		curEpoch := s.engine.GetEpoch()
+		if curEpoch == nil {
+			s.err = errEpochNotLoaded
+			return
+		}
+		if s.epoch, s.err = curEpoch.FindEpochByBlockNumber(number); s.err != nil {
+			return
+		}
+	}
+
+	epochStats, ok := s.stats[s.epoch.Number]
+	if !ok {
+		epochStats = make(map[common.Address]*ValidatorSigningStats)
+		s.stats[s.epoch.Number] = epochStats
+	}
+	statsOf := func(addr common.Address) *ValidatorSigningStats {
+		stats, ok := epochStats[addr]
+		if !ok {
+			stats = &ValidatorSigningStats{Address: addr}
+			epochStats[addr] = stats
+		}
+		return stats
+	}
+
+	statsOf(header.Coinbase).Proposed++
+
+	ncExtra, err := ntcTypes.ExtractNeatConExtra(header)
+	if err != nil {
+		s.err = err
+		return
+	}
+	if ncExtra.SeenCommit == nil || ncExtra.SeenCommit.BitArray == nil {
+		return
+	}
+	seen := ncExtra.SeenCommit.BitArray
+	if int(seen.Size()) != s.epoch.Validators.Size() {
+		log.Warnf("Signing indexer, commit of block %v does not match the validator set of epoch %v", number, s.epoch.Number)
+		return
+	}
+	for i, v := range s.epoch.Validators.Validators {
+		if seen.GetIndex(uint64(i)) {
+			statsOf(common.BytesToAddress(v.Address)).Signed++
+		} else {
+			statsOf(common.BytesToAddress(v.Address)).Missed++
+		}
+	}
+}
+
+func (s *SigningIndexer) Commit() error {
+	if s.err != nil {
+		return s.err
+	}
+
+	batch := s.db.NewBatch()
+	for epochNumber, epochStats := range s.stats {
+		list := make([]*ValidatorSigningStats, 0, len(epochStats))
+		for _, stats := range epochStats {
+			list = append(list, stats)
+		}
+		sort.Slice(list, func(i, j int) bool {
+			return bytes.Compare(list[i].Address[:], list[j].Address[:]) < 0
+		})
+
+		data, err := rlp.EncodeToBytes(list)
+		if err != nil {
+			return err
+		}
+		rawdb.WriteSigningStats(batch, epochNumber, s.section, data)
+	}
+	return batch.Write()
+}
+
+// ReadSigningStats sums up the signing stats of the validator in the epoch,
+// only the first sections sections of blocks are indexed
+func ReadSigningStats(db neatdb.Reader, ep *epoch.Epoch, addr common.Address, sections uint64) (*ValidatorSigningStats, error) {
+	total := &ValidatorSigningStats{Address: addr}
+	if sections == 0 {
+		return total, nil
+	}
+
+	last := ep.EndBlock / signingSectionSize
+	if last > sections-1 {
+		last = sections - 1
+	}
+	for section := ep.StartBlock / signingSectionSize; section <= last; section++ {
+		data := rawdb.ReadSigningStats(db, ep.Number, section)
+		if len(data) == 0 {
+			continue
+		}
+		var list []*ValidatorSigningStats
+		if err := rlp.DecodeBytes(data, &list); err != nil {
+			return nil, err
+		}
+		for _, stats := range list {
+			if stats.Address == addr {
+				total.Proposed += stats.Proposed
+				total.Signed += stats.Signed
+				total.Missed += stats.Missed
+				break
+			}
+		}
+	}
+	return total, nil
+}
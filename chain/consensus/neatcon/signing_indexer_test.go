@@ -0,0 +1,73 @@
+package neatcon
+
+import (
+	"math/big"
+	"testing"
+
+	. "github.com/neatio-net/common-go"
+	"github.com/neatio-net/neatio/chain/consensus/neatcon/epoch"
+	ntcTypes "github.com/neatio-net/neatio/chain/consensus/neatcon/types"
+	"github.com/neatio-net/neatio/chain/core/types"
+	"github.com/neatio-net/neatio/neatdb/memorydb"
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/wire-go"
+)
+
+func TestSigningIndexer(t *testing.T) {
+	addrA := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	addrB := common.HexToAddress("0x000000000000000000000000000000000000000b")
+	ep := &epoch.Epoch{
+		Number:     1,
+		StartBlock: 1,
+		EndBlock:   2 * signingSectionSize,
+		Validators: ntcTypes.NewValidatorSet([]*ntcTypes.Validator{
+			ntcTypes.NewValidator(addrA.Bytes(), nil, big.NewInt(1)),
+			ntcTypes.NewValidator(addrB.Bytes(), nil, big.NewInt(1)),
+		}),
+	}
+
+	header := func(number uint64, proposer common.Address, signedByB bool) *types.Header {
+		seen := NewBitArray(2)
+		seen.SetIndex(0, true)
+		if signedByB {
+			seen.SetIndex(1, true)
+		}
+		extra := ntcTypes.NeatConExtra{Height: number, EpochNumber: ep.Number, SeenCommit: &ntcTypes.Commit{BitArray: seen}}
+		return &types.Header{Number: new(big.Int).SetUint64(number), Coinbase: proposer, Extra: wire.BinaryBytes(extra)}
+	}
+
+	db := memorydb.New()
+	indexer := &SigningIndexer{db: db, epoch: ep}
+	for section := uint64(0); section < 2; section++ {
+		indexer.Reset(section, common.Hash{})
+		for number := section * signingSectionSize; number < (section+1)*signingSectionSize; number++ {
+			proposer := addrA
+			if number%4 == 0 {
+				proposer = addrB
+			}
+			indexer.Process(header(number, proposer, number%2 == 0))
+		}
+		if err := indexer.Commit(); err != nil {
+			t.Fatalf("commit section %v error %v", section, err)
+		}
+	}
+
+	// block 0 is skipped, blocks 1 to 255 are indexed
+	stats, err := ReadSigningStats(db, ep, addrB, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Proposed != 63 || stats.Signed != 127 || stats.Missed != 128 {
+		t.Errorf("validator B stats mismatch, got %+v", stats)
+	}
+	stats, _ = ReadSigningStats(db, ep, addrA, 2)
+	if stats.Proposed != 192 || stats.Signed != 255 || stats.Missed != 0 {
+		t.Errorf("validator A stats mismatch, got %+v", stats)
+	}
+
+	// only the first section is indexed yet
+	stats, _ = ReadSigningStats(db, ep, addrA, 1)
+	if stats.Signed != signingSectionSize-1 {
+		t.Errorf("expected %v signed blocks in the first section, got %v", signingSectionSize-1, stats.Signed)
+	}
+}
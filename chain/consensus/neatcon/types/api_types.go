@@ -19,6 +19,31 @@ type EpochApi struct {
 	Validators     []*EpochValidator `json:"validators"`
 }
 
+type EpochHistoryApi struct {
+	EpochApi
+	Changes []*ValidatorChangeApi `json:"changes"`
+}
+
+type ValidatorsAtHeightApi struct {
+	Height hexutil.Uint64 `json:"height"`
+	EpochHistoryApi
+}
+
+type ValidatorChangeApi struct {
+	Address     common.Address `json:"address"`
+	Reason      string         `json:"reason"`
+	VotingPower *hexutil.Big   `json:"votingPower"`
+}
+
+type SigningInfoApi struct {
+	Address      common.Address `json:"address"`
+	EpochNumber  hexutil.Uint64 `json:"epochNumber"`
+	Proposed     hexutil.Uint64 `json:"proposed"`
+	Signed       hexutil.Uint64 `json:"signed"`
+	Missed       hexutil.Uint64 `json:"missed"`
+	IndexedBlock hexutil.Uint64 `json:"indexedBlock"`
+}
+
 type EpochVotesApi struct {
 	EpochNumber hexutil.Uint64           `json:"voteForEpoch"`
 	StartBlock  hexutil.Uint64           `json:"startBlock"`
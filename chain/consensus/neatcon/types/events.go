@@ -51,6 +51,7 @@ const (
 	EventDataTypeVote          = byte(0x12)
 	EventDataTypeSignAggr      = byte(0x13)
 	EventDataTypeVote2Proposer = byte(0x14)
+	EventDataTypeDupeout       = byte(0x15)
 
 	EventDataTypeRequest        = byte(0x21)
 	EventDataTypeMessage        = byte(0x22)
@@ -67,6 +68,7 @@ var _ = wire.RegisterInterface(
 	wire.ConcreteType{EventDataVote{}, EventDataTypeVote},
 	wire.ConcreteType{EventDataSignAggr{}, EventDataTypeSignAggr},
 	wire.ConcreteType{EventDataVote2Proposer{}, EventDataTypeVote2Proposer},
+	wire.ConcreteType{EventDataDupeout{}, EventDataTypeDupeout},
 
 	wire.ConcreteType{EventDataRequest{}, EventDataTypeRequest},
 	wire.ConcreteType{EventDataMessage{}, EventDataTypeMessage},
@@ -110,6 +112,10 @@ type EventDataVote2Proposer struct {
 	ProposerKey string
 }
 
+type EventDataDupeout struct {
+	Evidence *DuplicateVoteEvidence
+}
+
 type EventDataRequest struct {
 	Proposal *neatTypes.Block `json:"proposal"`
 }
@@ -129,6 +135,7 @@ func (_ EventDataRoundState) AssertIsTMEventData()     {}
 func (_ EventDataVote) AssertIsTMEventData()           {}
 func (_ EventDataSignAggr) AssertIsTMEventData()       {}
 func (_ EventDataVote2Proposer) AssertIsTMEventData()  {}
+func (_ EventDataDupeout) AssertIsTMEventData()        {}
 
 func (_ EventDataRequest) AssertIsTMEventData()        {}
 func (_ EventDataMessage) AssertIsTMEventData()        {}
@@ -192,6 +199,10 @@ func FireEventVote2Proposer(fireable events.Fireable, vote EventDataVote2Propose
 	fireEvent(fireable, EventStringVote2Proposer(), vote)
 }
 
+func FireEventDupeout(fireable events.Fireable, dupeout EventDataDupeout) {
+	fireEvent(fireable, EventStringDupeout(), dupeout)
+}
+
 func FireEventTx(fireable events.Fireable, tx EventDataTx) {
 	fireEvent(fireable, EventStringTx(tx.Tx), tx)
 }
@@ -0,0 +1,105 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	neatCommon "github.com/neatio-net/neatio/utilities/common"
+	neatCrypto "github.com/neatio-net/neatio/utilities/crypto"
+	"github.com/neatio-net/wire-go"
+)
+
+var (
+	ErrEvidenceMissingVote      = errors.New("Evidence is missing a vote")
+	ErrEvidenceDifferentStep    = errors.New("Evidence votes are not for the same height/round/type")
+	ErrEvidenceDifferentAddress = errors.New("Evidence votes are not from the same validator")
+	ErrEvidenceSameBlock        = errors.New("Evidence votes are for the same block")
+	ErrEvidenceUnknownValidator = errors.New("Evidence validator is not in the validator set")
+	ErrEvidenceInvalidSignature = errors.New("Evidence vote has an invalid signature")
+)
+
+// DuplicateVoteEvidence holds two votes signed by the same validator for
+// different blocks at the same height, round and step.
+type DuplicateVoteEvidence struct {
+	VoteA *Vote
+	VoteB *Vote
+}
+
+func NewDuplicateVoteEvidence(conflict *ErrVoteConflictingVotes) *DuplicateVoteEvidence {
+	return &DuplicateVoteEvidence{
+		VoteA: conflict.VoteA,
+		VoteB: conflict.VoteB,
+	}
+}
+
+func DecodeDuplicateVoteEvidence(bs []byte) (*DuplicateVoteEvidence, error) {
+	ev := &DuplicateVoteEvidence{}
+	if err := wire.ReadBinaryBytes(bs, ev); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+func (ev *DuplicateVoteEvidence) Height() uint64 {
+	return ev.VoteA.Height
+}
+
+func (ev *DuplicateVoteEvidence) Address() neatCommon.Address {
+	return neatCommon.BytesToAddress(ev.VoteA.ValidatorAddress)
+}
+
+// Hash does not depend on the order of the two votes, so the same double sign
+// reported by different nodes is only counted once.
+func (ev *DuplicateVoteEvidence) Hash() neatCommon.Hash {
+	a, b := ev.VoteA.Copy(), ev.VoteB.Copy()
+	if bytes.Compare(a.BlockID.Hash, b.BlockID.Hash) > 0 {
+		a, b = b, a
+	}
+	a.SignBytes, b.SignBytes = nil, nil
+	return neatCrypto.Keccak256Hash(wire.BinaryBytes(DuplicateVoteEvidence{VoteA: a, VoteB: b}))
+}
+
+func (ev *DuplicateVoteEvidence) Bytes() []byte {
+	return wire.BinaryBytes(*ev)
+}
+
+func (ev *DuplicateVoteEvidence) ValidateBasic() error {
+	if ev.VoteA == nil || ev.VoteB == nil {
+		return ErrEvidenceMissingVote
+	}
+	if ev.VoteA.Height != ev.VoteB.Height ||
+		ev.VoteA.Round != ev.VoteB.Round ||
+		ev.VoteA.Type != ev.VoteB.Type {
+		return ErrEvidenceDifferentStep
+	}
+	if !bytes.Equal(ev.VoteA.ValidatorAddress, ev.VoteB.ValidatorAddress) {
+		return ErrEvidenceDifferentAddress
+	}
+	if ev.VoteA.BlockID.Equals(ev.VoteB.BlockID) {
+		return ErrEvidenceSameBlock
+	}
+	return nil
+}
+
+// Verify checks that both votes were signed by a validator of valSet.
+func (ev *DuplicateVoteEvidence) Verify(chainID string, valSet *ValidatorSet) error {
+	if err := ev.ValidateBasic(); err != nil {
+		return err
+	}
+
+	_, val := valSet.GetByAddress(ev.VoteA.ValidatorAddress)
+	if val == nil {
+		return ErrEvidenceUnknownValidator
+	}
+
+	if !val.PubKey.VerifyBytes(SignBytes(chainID, ev.VoteA), ev.VoteA.Signature) ||
+		!val.PubKey.VerifyBytes(SignBytes(chainID, ev.VoteB), ev.VoteB.Signature) {
+		return ErrEvidenceInvalidSignature
+	}
+	return nil
+}
+
+func (ev *DuplicateVoteEvidence) String() string {
+	return fmt.Sprintf("DuplicateVoteEvidence{%X %v %v}", ev.VoteA.ValidatorAddress, ev.VoteA, ev.VoteB)
+}
@@ -0,0 +1,83 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/neatio-net/neatio/utilities/common"
+)
+
+const testEvidenceChainID = "neatio"
+
+func makeTestVote(t *testing.T, pv *PrivValidator, blockHash []byte) *Vote {
+	vote := &Vote{
+		ValidatorAddress: pv.GetAddress(),
+		ValidatorIndex:   0,
+		Height:           10,
+		Round:            0,
+		Type:             VoteTypePrecommit,
+		BlockID:          BlockID{Hash: blockHash},
+	}
+	if err := pv.SignVote(testEvidenceChainID, vote); err != nil {
+		t.Fatalf("sign vote error %v", err)
+	}
+	return vote
+}
+
+func TestDuplicateVoteEvidence(t *testing.T) {
+	pv := GenPrivValidatorKey(common.HexToAddress("0x1000000000000000000000000000000000000001"))
+	valSet := NewValidatorSet([]*Validator{NewValidator(pv.GetAddress(), pv.PubKey, big.NewInt(100))})
+
+	// the same key running on two hosts, each one with its own last signed state
+	failover := &PrivValidator{Address: pv.Address, PubKey: pv.PubKey, PrivKey: pv.PrivKey, Signer: NewDefaultSigner(pv.PrivKey)}
+
+	voteA := makeTestVote(t, pv, []byte("block_a"))
+	voteB := makeTestVote(t, failover, []byte("block_b"))
+
+	ev := NewDuplicateVoteEvidence(&ErrVoteConflictingVotes{VoteA: voteA, VoteB: voteB})
+	if err := ev.Verify(testEvidenceChainID, valSet); err != nil {
+		t.Fatalf("valid evidence rejected: %v", err)
+	}
+	if ev.Address() != pv.Address {
+		t.Errorf("evidence address mismatch, got %x want %x", ev.Address(), pv.Address)
+	}
+
+	swapped := &DuplicateVoteEvidence{VoteA: voteB, VoteB: voteA}
+	if ev.Hash() != swapped.Hash() {
+		t.Errorf("evidence hash depends on the vote order")
+	}
+
+	decoded, err := DecodeDuplicateVoteEvidence(ev.Bytes())
+	if err != nil {
+		t.Fatalf("decode evidence error %v", err)
+	}
+	if err := decoded.Verify(testEvidenceChainID, valSet); err != nil {
+		t.Errorf("decoded evidence rejected: %v", err)
+	}
+
+	same := &DuplicateVoteEvidence{VoteA: voteA, VoteB: voteA.Copy()}
+	if err := same.Verify(testEvidenceChainID, valSet); err != ErrEvidenceSameBlock {
+		t.Errorf("expected %v, got %v", ErrEvidenceSameBlock, err)
+	}
+
+	forged := voteB.Copy()
+	forged.Round = 1
+	forged.Signature = voteB.Signature
+	forgedEv := &DuplicateVoteEvidence{VoteA: voteA, VoteB: forged}
+	if err := forgedEv.Verify(testEvidenceChainID, valSet); err != ErrEvidenceDifferentStep {
+		t.Errorf("expected %v, got %v", ErrEvidenceDifferentStep, err)
+	}
+
+	forged = voteB.Copy()
+	forged.BlockID = BlockID{Hash: []byte("block_c")}
+	forgedEv = &DuplicateVoteEvidence{VoteA: voteA, VoteB: forged}
+	if err := forgedEv.Verify(testEvidenceChainID, valSet); err != ErrEvidenceInvalidSignature {
+		t.Errorf("expected %v, got %v", ErrEvidenceInvalidSignature, err)
+	}
+
+	other := GenPrivValidatorKey(common.HexToAddress("0x2000000000000000000000000000000000000002"))
+	otherSet := NewValidatorSet([]*Validator{NewValidator(other.GetAddress(), other.PubKey, big.NewInt(100))})
+	if err := ev.Verify(testEvidenceChainID, otherSet); err != ErrEvidenceUnknownValidator {
+		t.Errorf("expected %v, got %v", ErrEvidenceUnknownValidator, err)
+	}
+}
@@ -45,6 +45,9 @@ type RewardSchemeDoc struct {
 	RewardFirstYear    *big.Int `json:"reward_first_year"`
 	EpochNumberPerYear uint64   `json:"epoch_no_per_year"`
 	TotalMintingYears  uint64   `json:"total_year"`
+	// DowntimeThreshold is the number of missed commits in one epoch after
+	// which a validator is banned, 0 disables the liveness check
+	DowntimeThreshold uint64 `json:"downtime_threshold"`
 }
 
 type GenesisDoc struct {
@@ -220,12 +223,14 @@ func (rs RewardSchemeDoc) MarshalJSON() ([]byte, error) {
 		RewardFirstYear    *hexutil.Big   `json:"reward_first_year"`
 		EpochNumberPerYear hexutil.Uint64 `json:"epoch_no_per_year"`
 		TotalMintingYears  hexutil.Uint64 `json:"total_year"`
+		DowntimeThreshold  hexutil.Uint64 `json:"downtime_threshold,omitempty"`
 	}
 	var enc hexRewardScheme
 	enc.TotalReward = (*hexutil.Big)(rs.TotalReward)
 	enc.RewardFirstYear = (*hexutil.Big)(rs.RewardFirstYear)
 	enc.EpochNumberPerYear = hexutil.Uint64(rs.EpochNumberPerYear)
 	enc.TotalMintingYears = hexutil.Uint64(rs.TotalMintingYears)
+	enc.DowntimeThreshold = hexutil.Uint64(rs.DowntimeThreshold)
 
 	return json.Marshal(&enc)
 }
@@ -236,6 +241,7 @@ func (rs *RewardSchemeDoc) UnmarshalJSON(input []byte) error {
 		RewardFirstYear    *hexutil.Big   `json:"reward_first_year"`
 		EpochNumberPerYear hexutil.Uint64 `json:"epoch_no_per_year"`
 		TotalMintingYears  hexutil.Uint64 `json:"total_year"`
+		DowntimeThreshold  hexutil.Uint64 `json:"downtime_threshold"`
 	}
 	var dec hexRewardScheme
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -252,6 +258,7 @@ func (rs *RewardSchemeDoc) UnmarshalJSON(input []byte) error {
 
 	rs.EpochNumberPerYear = uint64(dec.EpochNumberPerYear)
 	rs.TotalMintingYears = uint64(dec.TotalMintingYears)
+	rs.DowntimeThreshold = uint64(dec.DowntimeThreshold)
 
 	return nil
 }
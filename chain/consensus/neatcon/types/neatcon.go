@@ -6,10 +6,14 @@ import (
 
 	"github.com/neatio-net/merkle-go"
 	neatTypes "github.com/neatio-net/neatio/chain/core/types"
+	"github.com/neatio-net/neatio/utilities/common"
 	"github.com/neatio-net/neatio/utilities/common/hexutil"
 	"github.com/neatio-net/wire-go"
 )
 
+// SideChainRewardAddress holds the pool the side chain block rewards are paid from
+var SideChainRewardAddress = common.StringToAddress("0x0000000000000000000000000000000000000535")
+
 type NeatConExtra struct {
 	ChainID         string    `json:"chain_id"`
 	Height          uint64    `json:"height"`
@@ -2,24 +2,66 @@ package types
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"sync"
 
 	"github.com/neatio-net/bls-go"
 	. "github.com/neatio-net/common-go"
 	"github.com/neatio-net/crypto-go"
+	neatTypes "github.com/neatio-net/neatio/chain/core/types"
 	"github.com/neatio-net/neatio/utilities/common"
+	neatCrypto "github.com/neatio-net/neatio/utilities/crypto"
 	"github.com/neatio-net/wire-go"
 )
 
+const (
+	stepNone      int8 = 0
+	stepPropose   int8 = 1
+	stepPrevote   int8 = 2
+	stepPrecommit int8 = 3
+)
+
+var (
+	ErrPrivValHeightRegression = errors.New("PrivValidator height regression")
+	ErrPrivValRoundRegression  = errors.New("PrivValidator round regression")
+	ErrPrivValStepRegression   = errors.New("PrivValidator step regression")
+	ErrPrivValConflictingData  = errors.New("PrivValidator conflicting data at the last signed height/round/step")
+)
+
+func voteToStep(vote *Vote) int8 {
+	switch vote.Type {
+	case VoteTypePrevote:
+		return stepPrevote
+	case VoteTypePrecommit:
+		return stepPrecommit
+	default:
+		PanicSanity("Unknown vote type")
+		return stepNone
+	}
+}
+
 type PrivValidator struct {
 	Address common.Address `json:"address"`
 	PubKey  crypto.PubKey  `json:"consensus_pub_key"`
 	PrivKey crypto.PrivKey `json:"consensus_priv_key"`
 
+	// What we signed last, it is saved before the signature is returned, so
+	// after a restart or a fail over to another host with the same file we
+	// never sign a different message at the same height/round/step
+	LastHeight    uint64           `json:"last_height"`
+	LastRound     int              `json:"last_round"`
+	LastStep      int8             `json:"last_step"`
+	LastSignature crypto.Signature `json:"last_signature"`
+	LastSignBytes []byte           `json:"last_signbytes"`
+
 	Signer `json:"-"`
 
+	// votes and proposals are signed by a separate signer process when set
+	remote *RemoteSigner
+
 	filePath string
 	mtx      sync.Mutex
 }
@@ -28,6 +70,12 @@ type Signer interface {
 	Sign(msg []byte) crypto.Signature
 }
 
+// TxSigner signs the transactions sent with the account of the consensus key
+type TxSigner interface {
+	TxAccount() (common.Address, error)
+	SignTx(tx *neatTypes.Transaction, chainID *big.Int) (*neatTypes.Transaction, error)
+}
+
 type DefaultSigner struct {
 	priv crypto.PrivKey
 }
@@ -105,24 +153,123 @@ func (pv *PrivValidator) GetPubKey() crypto.PubKey {
 	return pv.PubKey
 }
 
+// IsRemote returns true when the private key is held by a remote signer
+func (pv *PrivValidator) IsRemote() bool {
+	return pv.remote != nil
+}
+
+// TxAccount returns the account the transactions signed with the consensus key
+// are sent from
+func (pv *PrivValidator) TxAccount() (common.Address, error) {
+	if pv.remote != nil {
+		return pv.remote.txAccount, nil
+	}
+
+	prv, err := neatCrypto.ToECDSA(pv.PrivKey.(crypto.BLSPrivKey).Bytes())
+	if err != nil {
+		return common.Address{}, err
+	}
+	return neatCrypto.PubkeyToAddress(prv.PublicKey), nil
+}
+
+// SignTx signs the transaction with the account of the consensus key
+func (pv *PrivValidator) SignTx(tx *neatTypes.Transaction, chainID *big.Int) (*neatTypes.Transaction, error) {
+	if pv.remote != nil {
+		signed, err := pv.remote.SignTx(tx, chainID)
+		if err != nil {
+			return nil, fmt.Errorf("Error signing tx remotely: %v", err)
+		}
+		return signed, nil
+	}
+
+	prv, err := neatCrypto.ToECDSA(pv.PrivKey.(crypto.BLSPrivKey).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return neatTypes.SignTx(tx, neatTypes.LatestSignerForChainID(chainID), prv)
+}
+
+func (pv *PrivValidator) Close() {
+	if pv.remote != nil {
+		pv.remote.Close()
+	}
+}
+
 func (pv *PrivValidator) SignVote(chainID string, vote *Vote) error {
+	if pv.remote != nil {
+		if err := pv.remote.SignVote(chainID, vote); err != nil {
+			return fmt.Errorf("Error signing vote remotely: %v", err)
+		}
+		return nil
+	}
+
 	pv.mtx.Lock()
 	defer pv.mtx.Unlock()
 
-	signature := pv.Sign(SignBytes(chainID, vote))
+	signature, err := pv.signBytesHRS(vote.Height, int(vote.Round), voteToStep(vote), SignBytes(chainID, vote))
+	if err != nil {
+		return fmt.Errorf("Error signing vote: %v", err)
+	}
 	vote.Signature = signature
 	return nil
 }
 
 func (pv *PrivValidator) SignProposal(chainID string, proposal *Proposal) error {
+	if pv.remote != nil {
+		if err := pv.remote.SignProposal(chainID, proposal); err != nil {
+			return fmt.Errorf("Error signing proposal remotely: %v", err)
+		}
+		return nil
+	}
+
 	pv.mtx.Lock()
 	defer pv.mtx.Unlock()
 
-	signature := pv.Sign(SignBytes(chainID, proposal))
+	signature, err := pv.signBytesHRS(proposal.Height, proposal.Round, stepPropose, SignBytes(chainID, proposal))
+	if err != nil {
+		return fmt.Errorf("Error signing proposal: %v", err)
+	}
 	proposal.Signature = signature
 	return nil
 }
 
+// signBytesHRS refuses to sign anything older than the last signed
+// height/round/step, and returns the last signature if asked to sign the same
+// bytes again
+func (pv *PrivValidator) signBytesHRS(height uint64, round int, step int8, signBytes []byte) (crypto.Signature, error) {
+	if pv.LastHeight > height {
+		return nil, ErrPrivValHeightRegression
+	}
+	if pv.LastHeight == height {
+		if pv.LastRound > round {
+			return nil, ErrPrivValRoundRegression
+		}
+		if pv.LastRound == round {
+			if pv.LastStep > step {
+				return nil, ErrPrivValStepRegression
+			} else if pv.LastStep == step {
+				if pv.LastSignBytes != nil && pv.LastSignature != nil && bytes.Equal(signBytes, pv.LastSignBytes) {
+					return pv.LastSignature, nil
+				}
+				return nil, ErrPrivValConflictingData
+			}
+		}
+	}
+
+	signature := pv.Sign(signBytes)
+
+	pv.LastHeight = height
+	pv.LastRound = round
+	pv.LastStep = step
+	pv.LastSignature = signature
+	pv.LastSignBytes = signBytes
+	if pv.filePath != "" {
+		pv.save()
+	}
+
+	return signature, nil
+}
+
 func (pv *PrivValidator) String() string {
 	return fmt.Sprintf("PrivValidator{%X}", pv.Address)
 }
@@ -0,0 +1,517 @@
+package types
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neatio-net/crypto-go"
+	neatTypes "github.com/neatio-net/neatio/chain/core/types"
+	"github.com/neatio-net/neatio/chain/log"
+	neatAbi "github.com/neatio-net/neatio/neatabi/abi"
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/wire-go"
+)
+
+const (
+	maxSignerMessageSize  = 64 * 1024
+	remoteSignerTimeout   = 3 * time.Second
+	remoteSignerKeepAlive = 30 * time.Second
+)
+
+var (
+	ErrRemoteSignerNoTLS       = errors.New("remote signer over tcp requires mutual TLS")
+	ErrRemoteSignerBadResponse = errors.New("unexpected response from remote signer")
+	ErrRemoteSignerMsgTooLong  = errors.New("remote signer message too long")
+	ErrRemoteSignerTxRefused   = errors.New("remote signer only signs the ReportDoubleSign and SaveDataToMainChain transactions")
+)
+
+// SignerMessage is a request or response exchanged between a node and its remote signer
+type SignerMessage interface{}
+
+var _ = wire.RegisterInterface(
+	struct{ SignerMessage }{},
+	wire.ConcreteType{&PubKeyRequest{}, 0x01},
+	wire.ConcreteType{&PubKeyResponse{}, 0x02},
+	wire.ConcreteType{&SignVoteRequest{}, 0x03},
+	wire.ConcreteType{&SignedVoteResponse{}, 0x04},
+	wire.ConcreteType{&SignProposalRequest{}, 0x05},
+	wire.ConcreteType{&SignedProposalResponse{}, 0x06},
+	wire.ConcreteType{&SignTxRequest{}, 0x07},
+	wire.ConcreteType{&SignedTxResponse{}, 0x08},
+)
+
+type PubKeyRequest struct{}
+
+type PubKeyResponse struct {
+	Address   common.Address
+	PubKey    crypto.PubKey
+	TxAccount common.Address
+}
+
+type SignVoteRequest struct {
+	ChainID string
+	Vote    *Vote
+}
+
+type SignedVoteResponse struct {
+	Vote  *Vote
+	Error string
+}
+
+type SignProposalRequest struct {
+	ChainID  string
+	Proposal *Proposal
+}
+
+type SignedProposalResponse struct {
+	Proposal *Proposal
+	Error    string
+}
+
+// SignTxRequest carries the binary encoding of the transaction and the big
+// endian chain id it is signed for
+type SignTxRequest struct {
+	ChainID []byte
+	Tx      []byte
+}
+
+type SignedTxResponse struct {
+	Tx    []byte
+	Error string
+}
+
+// Each message is sent as a 4 bytes big endian length followed by the wire encoding
+func writeSignerMessage(w io.Writer, msg SignerMessage) error {
+	data := wire.BinaryBytes(struct{ SignerMessage }{msg})
+	if len(data) > maxSignerMessageSize {
+		return ErrRemoteSignerMsgTooLong
+	}
+	frame := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(frame[0:4], uint32(len(data)))
+	copy(frame[4:], data)
+	_, err := w.Write(frame)
+	return err
+}
+
+func readSignerMessage(r io.Reader) (SignerMessage, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(header)
+	if length > maxSignerMessageSize {
+		return nil, ErrRemoteSignerMsgTooLong
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	var n int
+	var err error
+	msg := wire.ReadBinary(struct{ SignerMessage }{}, bytes.NewReader(data), maxSignerMessageSize, &n, &err)
+	if err != nil {
+		return nil, err
+	}
+	return msg.(struct{ SignerMessage }).SignerMessage, nil
+}
+
+// SignerProtocolAndAddress splits "unix:///path/to/socket" or "tcp://host:port"
+func SignerProtocolAndAddress(addr string) (string, string, error) {
+	parts := strings.SplitN(addr, "://", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid remote signer address %v, expect unix://<path> or tcp://<host:port>", addr)
+	}
+	if parts[0] != "unix" && parts[0] != "tcp" {
+		return "", "", fmt.Errorf("unsupported remote signer protocol %v", parts[0])
+	}
+	return parts[0], parts[1], nil
+}
+
+// LoadSignerTLSConfig loads the certificate of this side and the CA the other
+// side's certificate must be issued by. Both the node and the signer verify
+// each other.
+func LoadSignerTLSConfig(certFile, keyFile, caFile string, server bool) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load TLS key pair: %v", err)
+	}
+	caPEM, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("load TLS CA: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificate found in %v", caFile)
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if server {
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	} else {
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// RemoteSigner is the node side of the remote signer protocol. The connection
+// is kept open and dialed again when it breaks.
+type RemoteSigner struct {
+	protocol  string
+	address   string
+	tlsConfig *tls.Config
+
+	conn net.Conn
+	mtx  sync.Mutex
+
+	// account of the transactions signed with the consensus key
+	txAccount common.Address
+
+	logger log.Logger
+}
+
+func NewRemoteSigner(addr string, tlsConfig *tls.Config, logger log.Logger) (*RemoteSigner, error) {
+	protocol, address, err := SignerProtocolAndAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	if protocol == "tcp" && tlsConfig == nil {
+		return nil, ErrRemoteSignerNoTLS
+	}
+	if tlsConfig != nil && tlsConfig.ServerName == "" && protocol == "tcp" {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig = tlsConfig.Clone()
+		tlsConfig.ServerName = host
+	}
+
+	return &RemoteSigner{
+		protocol:  protocol,
+		address:   address,
+		tlsConfig: tlsConfig,
+		logger:    logger,
+	}, nil
+}
+
+func (rs *RemoteSigner) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: remoteSignerTimeout, KeepAlive: remoteSignerKeepAlive}
+	if rs.tlsConfig != nil {
+		return tls.DialWithDialer(dialer, rs.protocol, rs.address, rs.tlsConfig)
+	}
+	return dialer.Dial(rs.protocol, rs.address)
+}
+
+// request sends the message and waits for the response, a broken connection
+// is dialed again once
+func (rs *RemoteSigner) request(msg SignerMessage) (SignerMessage, error) {
+	rs.mtx.Lock()
+	defer rs.mtx.Unlock()
+
+	var err error
+	for i := 0; i < 2; i++ {
+		if rs.conn == nil {
+			if rs.conn, err = rs.dial(); err != nil {
+				rs.conn = nil
+				continue
+			}
+		}
+
+		var resp SignerMessage
+		rs.conn.SetDeadline(time.Now().Add(remoteSignerTimeout))
+		if err = writeSignerMessage(rs.conn, msg); err == nil {
+			if resp, err = readSignerMessage(rs.conn); err == nil {
+				return resp, nil
+			}
+		}
+
+		rs.logger.Warnf("Remote signer %v://%v connection broken: %v", rs.protocol, rs.address, err)
+		rs.conn.Close()
+		rs.conn = nil
+	}
+	return nil, err
+}
+
+func (rs *RemoteSigner) GetPubKey() (common.Address, crypto.PubKey, error) {
+	resp, err := rs.request(&PubKeyRequest{})
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	pkResp, ok := resp.(*PubKeyResponse)
+	if !ok || pkResp.PubKey == nil {
+		return common.Address{}, nil, ErrRemoteSignerBadResponse
+	}
+	rs.txAccount = pkResp.TxAccount
+	return pkResp.Address, pkResp.PubKey, nil
+}
+
+func (rs *RemoteSigner) SignVote(chainID string, vote *Vote) error {
+	resp, err := rs.request(&SignVoteRequest{ChainID: chainID, Vote: vote})
+	if err != nil {
+		return err
+	}
+	voteResp, ok := resp.(*SignedVoteResponse)
+	if !ok {
+		return ErrRemoteSignerBadResponse
+	}
+	if voteResp.Error != "" {
+		return errors.New(voteResp.Error)
+	}
+	if voteResp.Vote == nil || voteResp.Vote.Signature == nil {
+		return ErrRemoteSignerBadResponse
+	}
+	vote.Signature = voteResp.Vote.Signature
+	return nil
+}
+
+func (rs *RemoteSigner) SignProposal(chainID string, proposal *Proposal) error {
+	resp, err := rs.request(&SignProposalRequest{ChainID: chainID, Proposal: proposal})
+	if err != nil {
+		return err
+	}
+	proposalResp, ok := resp.(*SignedProposalResponse)
+	if !ok {
+		return ErrRemoteSignerBadResponse
+	}
+	if proposalResp.Error != "" {
+		return errors.New(proposalResp.Error)
+	}
+	if proposalResp.Proposal == nil || proposalResp.Proposal.Signature == nil {
+		return ErrRemoteSignerBadResponse
+	}
+	proposal.Signature = proposalResp.Proposal.Signature
+	return nil
+}
+
+func (rs *RemoteSigner) SignTx(tx *neatTypes.Transaction, chainID *big.Int) (*neatTypes.Transaction, error) {
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := rs.request(&SignTxRequest{ChainID: chainID.Bytes(), Tx: data})
+	if err != nil {
+		return nil, err
+	}
+	txResp, ok := resp.(*SignedTxResponse)
+	if !ok {
+		return nil, ErrRemoteSignerBadResponse
+	}
+	if txResp.Error != "" {
+		return nil, errors.New(txResp.Error)
+	}
+
+	signed := new(neatTypes.Transaction)
+	if err := signed.UnmarshalBinary(txResp.Tx); err != nil {
+		return nil, ErrRemoteSignerBadResponse
+	}
+	// the signer must not have changed the transaction
+	if signer := neatTypes.LatestSignerForChainID(chainID); signer.Hash(signed) != signer.Hash(tx) {
+		return nil, ErrRemoteSignerBadResponse
+	}
+	return signed, nil
+}
+
+func (rs *RemoteSigner) Close() {
+	rs.mtx.Lock()
+	defer rs.mtx.Unlock()
+
+	if rs.conn != nil {
+		rs.conn.Close()
+		rs.conn = nil
+	}
+}
+
+// NewRemotePrivValidator returns a PrivValidator without private key, the
+// votes and proposals are signed by the remote signer
+func NewRemotePrivValidator(rs *RemoteSigner) (*PrivValidator, error) {
+	address, pubKey, err := rs.GetPubKey()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting public key from remote signer: %v", err)
+	}
+	return &PrivValidator{
+		Address: address,
+		PubKey:  pubKey,
+		remote:  rs,
+	}, nil
+}
+
+// SignerServer is the signer process side, it holds the PrivValidator and
+// signs for the nodes connected to it. The last signed height/round/step of
+// the PrivValidator is checked here, so a compromised or misbehaving node can
+// not make the signer double sign.
+type SignerServer struct {
+	listener net.Listener
+	privVal  *PrivValidator
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+
+	logger log.Logger
+}
+
+// ListenSigner listens on the given address, tcp listeners require mutual TLS
+func ListenSigner(addr string, tlsConfig *tls.Config) (net.Listener, error) {
+	protocol, address, err := SignerProtocolAndAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	if protocol == "tcp" {
+		if tlsConfig == nil {
+			return nil, ErrRemoteSignerNoTLS
+		}
+		return tls.Listen(protocol, address, tlsConfig)
+	}
+	// a socket left by a signer which did not stop cleanly
+	if info, err := os.Stat(address); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(address)
+	}
+	return net.Listen(protocol, address)
+}
+
+func NewSignerServer(listener net.Listener, privVal *PrivValidator, logger log.Logger) *SignerServer {
+	return &SignerServer{
+		listener: listener,
+		privVal:  privVal,
+		quit:     make(chan struct{}),
+		logger:   logger,
+	}
+}
+
+// Serve accepts connections until Stop is called
+func (ss *SignerServer) Serve() error {
+	for {
+		conn, err := ss.listener.Accept()
+		if err != nil {
+			select {
+			case <-ss.quit:
+				return nil
+			default:
+				return err
+			}
+		}
+		ss.logger.Infof("Remote signer accepted connection from %v", conn.RemoteAddr())
+
+		ss.wg.Add(1)
+		go ss.handleConn(conn)
+	}
+}
+
+func (ss *SignerServer) Stop() {
+	close(ss.quit)
+	ss.listener.Close()
+	ss.wg.Wait()
+}
+
+func (ss *SignerServer) handleConn(conn net.Conn) {
+	defer ss.wg.Done()
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ss.quit:
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		req, err := readSignerMessage(conn)
+		if err != nil {
+			if err != io.EOF {
+				ss.logger.Warnf("Remote signer connection from %v closed: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+
+		var resp SignerMessage
+		switch req := req.(type) {
+		case *PubKeyRequest:
+			txAccount, _ := ss.privVal.TxAccount()
+			resp = &PubKeyResponse{Address: ss.privVal.Address, PubKey: ss.privVal.PubKey, TxAccount: txAccount}
+		case *SignVoteRequest:
+			voteResp := &SignedVoteResponse{Vote: req.Vote}
+			if req.Vote == nil {
+				voteResp.Error = "no vote to sign"
+			} else if err := ss.privVal.SignVote(req.ChainID, req.Vote); err != nil {
+				ss.logger.Errorf("Remote signer refused to sign vote %v: %v", req.Vote, err)
+				voteResp.Error = err.Error()
+			}
+			resp = voteResp
+		case *SignProposalRequest:
+			proposalResp := &SignedProposalResponse{Proposal: req.Proposal}
+			if req.Proposal == nil {
+				proposalResp.Error = "no proposal to sign"
+			} else if err := ss.privVal.SignProposal(req.ChainID, req.Proposal); err != nil {
+				ss.logger.Errorf("Remote signer refused to sign proposal %v: %v", req.Proposal, err)
+				proposalResp.Error = err.Error()
+			}
+			resp = proposalResp
+		case *SignTxRequest:
+			resp = ss.signTx(req)
+		default:
+			ss.logger.Warnf("Remote signer got unknown request %v from %v", req, conn.RemoteAddr())
+			return
+		}
+
+		if err := writeSignerMessage(conn, resp); err != nil {
+			ss.logger.Warnf("Remote signer failed to respond to %v: %v", conn.RemoteAddr(), err)
+			return
+		}
+	}
+}
+
+// signTx signs the transactions the consensus engine sends with the account of
+// the consensus key, no other transaction is signed for the node
+func (ss *SignerServer) signTx(req *SignTxRequest) *SignedTxResponse {
+	tx := new(neatTypes.Transaction)
+	if err := tx.UnmarshalBinary(req.Tx); err != nil {
+		return &SignedTxResponse{Error: err.Error()}
+	}
+	if err := checkSignerTx(tx); err != nil {
+		ss.logger.Errorf("Remote signer refused to sign tx %x: %v", tx.Hash(), err)
+		return &SignedTxResponse{Error: err.Error()}
+	}
+
+	signed, err := ss.privVal.SignTx(tx, new(big.Int).SetBytes(req.ChainID))
+	if err != nil {
+		return &SignedTxResponse{Error: err.Error()}
+	}
+	data, err := signed.MarshalBinary()
+	if err != nil {
+		return &SignedTxResponse{Error: err.Error()}
+	}
+	return &SignedTxResponse{Tx: data}
+}
+
+func checkSignerTx(tx *neatTypes.Transaction) error {
+	if !neatAbi.IsNeatChainContractAddr(tx.To()) || tx.Value().Sign() != 0 {
+		return ErrRemoteSignerTxRefused
+	}
+	function, err := neatAbi.FunctionTypeFromId(tx.Data())
+	if err != nil || (function != neatAbi.ReportDoubleSign && function != neatAbi.SaveDataToMainChain) {
+		return ErrRemoteSignerTxRefused
+	}
+	if tx.Gas() > function.RequiredGas() {
+		return ErrRemoteSignerTxRefused
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package types
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	neatTypes "github.com/neatio-net/neatio/chain/core/types"
+	"github.com/neatio-net/neatio/chain/log"
+	neatAbi "github.com/neatio-net/neatio/neatabi/abi"
+	"github.com/neatio-net/neatio/utilities/common"
+)
+
+func TestRemoteSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote_signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	addr := "unix://" + filepath.Join(dir, "signer.sock")
+
+	pv := GenPrivValidatorKey(common.HexToAddress("0x1000000000000000000000000000000000000001"))
+	pv.SetFile(filepath.Join(dir, "priv_validator.json"))
+	pv.Save()
+
+	startServer := func() *SignerServer {
+		listener, err := ListenSigner(addr, nil)
+		if err != nil {
+			t.Fatalf("listen error %v", err)
+		}
+		server := NewSignerServer(listener, pv, log.New())
+		go server.Serve()
+		return server
+	}
+	server := startServer()
+
+	if _, err := NewRemoteSigner("tcp://127.0.0.1:0", nil, log.New()); err != ErrRemoteSignerNoTLS {
+		t.Errorf("expected %v for tcp without TLS, got %v", ErrRemoteSignerNoTLS, err)
+	}
+
+	signer, err := NewRemoteSigner(addr, nil, log.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer signer.Close()
+	remote, err := NewRemotePrivValidator(signer)
+	if err != nil {
+		t.Fatalf("connect remote signer error %v", err)
+	}
+	if remote.Address != pv.Address || !remote.PubKey.Equals(pv.PubKey) || remote.PrivKey != nil {
+		t.Fatalf("remote priv validator mismatch, got %v", remote)
+	}
+
+	vote := &Vote{ValidatorAddress: remote.GetAddress(), Height: 10, Round: 0, Type: VoteTypePrevote, BlockID: BlockID{Hash: []byte("block_a")}}
+	if err := remote.SignVote(testEvidenceChainID, vote); err != nil {
+		t.Fatalf("sign vote error %v", err)
+	}
+	if !remote.PubKey.VerifyBytes(SignBytes(testEvidenceChainID, vote), vote.Signature) {
+		t.Errorf("invalid remote vote signature")
+	}
+
+	// the signer holds the last sign state, a conflicting vote is refused
+	conflict := &Vote{ValidatorAddress: remote.GetAddress(), Height: 10, Round: 0, Type: VoteTypePrevote, BlockID: BlockID{Hash: []byte("block_b")}}
+	if err := remote.SignVote(testEvidenceChainID, conflict); err == nil {
+		t.Errorf("remote signer signed a conflicting vote")
+	}
+
+	// restart the signer, the node dials it again
+	server.Stop()
+	server = startServer()
+	defer server.Stop()
+
+	proposal := &Proposal{Height: 11, Round: 0}
+	if err := remote.SignProposal(testEvidenceChainID, proposal); err != nil {
+		t.Fatalf("sign proposal after signer restart error %v", err)
+	}
+	if !remote.PubKey.VerifyBytes(SignBytes(testEvidenceChainID, proposal), proposal.Signature) {
+		t.Errorf("invalid remote proposal signature")
+	}
+	if LoadPrivValidator(filepath.Join(dir, "priv_validator.json")).LastHeight != 11 {
+		t.Errorf("last sign state not saved by the signer")
+	}
+
+	// the signer signs the evidence tx with the account of the consensus key
+	account, err := remote.TxAccount()
+	if err != nil {
+		t.Fatalf("remote tx account error %v", err)
+	}
+	if local, _ := pv.TxAccount(); account != local {
+		t.Fatalf("remote tx account mismatch, got %x want %x", account, local)
+	}
+	chainID := big.NewInt(1)
+	input, err := neatAbi.ChainABI.Pack(neatAbi.ReportDoubleSign.String(), []byte("evidence"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := neatTypes.NewTransaction(0, neatAbi.NeatioSmartContractAddress, big.NewInt(0), neatAbi.ReportDoubleSign.RequiredGas(), big.NewInt(1), input)
+	signed, err := remote.SignTx(tx, chainID)
+	if err != nil {
+		t.Fatalf("sign tx error %v", err)
+	}
+	if from, err := neatTypes.Sender(neatTypes.LatestSignerForChainID(chainID), signed); err != nil || from != account {
+		t.Errorf("remote tx signed by %x, want %x (%v)", from, account, err)
+	}
+
+	// any other tx is refused
+	transfer := neatTypes.NewTransaction(0, common.HexToAddress("0x2000000000000000000000000000000000000002"), big.NewInt(1), 21000, big.NewInt(1), nil)
+	if _, err := remote.SignTx(transfer, chainID); err == nil {
+		t.Errorf("remote signer signed a transfer")
+	}
+}
@@ -0,0 +1,72 @@
+package types
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/neatio-net/neatio/utilities/common"
+)
+
+func TestPrivValidatorLastSignState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "priv_validator")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "priv_validator.json")
+
+	pv := GenPrivValidatorKey(common.HexToAddress("0x1000000000000000000000000000000000000001"))
+	pv.SetFile(file)
+	pv.Save()
+
+	newVote := func(height, round uint64, type_ byte, blockHash string) *Vote {
+		return &Vote{
+			ValidatorAddress: pv.GetAddress(),
+			Height:           height,
+			Round:            round,
+			Type:             type_,
+			BlockID:          BlockID{Hash: []byte(blockHash)},
+		}
+	}
+
+	vote := newVote(10, 1, VoteTypePrevote, "block_a")
+	if err := pv.SignVote(testEvidenceChainID, vote); err != nil {
+		t.Fatalf("sign vote error %v", err)
+	}
+
+	// the file has moved to another host after a crash
+	restarted := LoadPrivValidator(file)
+	if restarted.LastHeight != 10 || restarted.LastRound != 1 || restarted.LastStep != stepPrevote {
+		t.Fatalf("last sign state not saved, got %v/%v/%v", restarted.LastHeight, restarted.LastRound, restarted.LastStep)
+	}
+
+	same := newVote(10, 1, VoteTypePrevote, "block_a")
+	if err := restarted.SignVote(testEvidenceChainID, same); err != nil {
+		t.Fatalf("signing the same vote again failed: %v", err)
+	}
+	if !same.Signature.Equals(vote.Signature) {
+		t.Errorf("expected the previous signature for the same vote")
+	}
+
+	if err := restarted.SignVote(testEvidenceChainID, newVote(10, 1, VoteTypePrevote, "block_b")); err == nil {
+		t.Errorf("signed a conflicting vote at the same height/round/step")
+	}
+	if err := restarted.SignVote(testEvidenceChainID, newVote(10, 0, VoteTypePrecommit, "block_b")); err == nil {
+		t.Errorf("signed a vote of a previous round")
+	}
+	if err := restarted.SignVote(testEvidenceChainID, newVote(9, 5, VoteTypePrecommit, "block_b")); err == nil {
+		t.Errorf("signed a vote of a previous height")
+	}
+	if err := restarted.SignProposal(testEvidenceChainID, &Proposal{Height: 10, Round: 1}); err == nil {
+		t.Errorf("signed a proposal after the prevote of the same round")
+	}
+
+	if err := restarted.SignVote(testEvidenceChainID, newVote(10, 1, VoteTypePrecommit, "block_a")); err != nil {
+		t.Errorf("precommit after prevote rejected: %v", err)
+	}
+	if err := restarted.SignProposal(testEvidenceChainID, &Proposal{Height: 11, Round: 0}); err != nil {
+		t.Errorf("proposal of the next height rejected: %v", err)
+	}
+}
@@ -80,9 +80,25 @@ type RefundValidatorAmount struct {
 	Voteout bool
 }
 
+// reasons of the validator set changes at the start of an epoch
+const (
+	ValidatorJoined    = "joined"
+	ValidatorVotedOut  = "voteout"
+	ValidatorBanned    = "banned"
+	ValidatorWithdrawn = "withdrawn"
+	ValidatorNoStake   = "nostake"
+)
+
+type ValidatorChange struct {
+	Address     common.Address
+	Reason      string
+	VotingPower *big.Int
+}
+
 type SwitchEpochOp struct {
 	ChainId       string
 	NewValidators *ValidatorSet
+	Changes       []*ValidatorChange
 }
 
 func (op *SwitchEpochOp) Conflict(op1 neatTypes.PendingOp) bool {
@@ -67,15 +67,16 @@ type BlockChain struct {
 	triegc *prque.Prque
 	gcproc time.Duration
 
-	hc                  *HeaderChain
-	rmLogsFeed          event.Feed
-	chainFeed           event.Feed
-	chainSideFeed       event.Feed
-	chainHeadFeed       event.Feed
-	logsFeed            event.Feed
-	createSideChainFeed event.Feed
-	startMiningFeed     event.Feed
-	stopMiningFeed      event.Feed
+	hc                        *HeaderChain
+	rmLogsFeed                event.Feed
+	chainFeed                 event.Feed
+	chainSideFeed             event.Feed
+	chainHeadFeed             event.Feed
+	logsFeed                  event.Feed
+	createSideChainFeed       event.Feed
+	decommissionSideChainFeed event.Feed
+	startMiningFeed           event.Feed
+	stopMiningFeed            event.Feed
 
 	scope        event.SubscriptionScope
 	genesisBlock *types.Block
@@ -1376,6 +1377,9 @@ func (bc *BlockChain) PostChainEvents(events []interface{}, logs []*types.Log) {
 		case CreateSideChainEvent:
 			bc.createSideChainFeed.Send(ev)
 
+		case DecommissionSideChainEvent:
+			bc.decommissionSideChainFeed.Send(ev)
+
 		case StartMiningEvent:
 			bc.startMiningFeed.Send(ev)
 
@@ -1524,6 +1528,10 @@ func (bc *BlockChain) SubscribeCreateSideChainEvent(ch chan<- CreateSideChainEve
 	return bc.scope.Track(bc.createSideChainFeed.Subscribe(ch))
 }
 
+func (bc *BlockChain) SubscribeDecommissionSideChainEvent(ch chan<- DecommissionSideChainEvent) event.Subscription {
+	return bc.scope.Track(bc.decommissionSideChainFeed.Subscribe(ch))
+}
+
 func (bc *BlockChain) SubscribeStartMiningEvent(ch chan<- StartMiningEvent) event.Subscription {
 	return bc.scope.Track(bc.startMiningFeed.Subscribe(ch))
 }
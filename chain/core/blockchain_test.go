@@ -944,14 +944,17 @@ func TestCanonicalBlockRetrieval(t *testing.T) {
 					continue
 				}
 				if ch != block.Hash() {
-					t.Fatalf("unknown canonical hash, want %s, got %s", block.Hash().Hex(), ch.Hex())
+					t.Errorf("unknown canonical hash, want %s, got %s", block.Hash().Hex(), ch.Hex())
+					return
 				}
 				fb := rawdb.ReadBlock(blockchain.db, ch, block.NumberU64())
 				if fb == nil {
-					t.Fatalf("unable to retrieve block %d for canonical hash: %s", block.NumberU64(), ch.Hex())
+					t.Errorf("unable to retrieve block %d for canonical hash: %s", block.NumberU64(), ch.Hex())
+					return
 				}
 				if fb.Hash() != block.Hash() {
-					t.Fatalf("invalid block hash for block %d, want %s, got %s", block.NumberU64(), block.Hash().Hex(), fb.Hash().Hex())
+					t.Errorf("invalid block hash for block %d, want %s, got %s", block.NumberU64(), block.Hash().Hex(), fb.Hash().Hex())
+					return
 				}
 				return
 			}
@@ -1133,7 +1136,7 @@ func TestEIP161AccountRemoval(t *testing.T) {
 
 func TestBlockchainHeaderchainReorgConsistency(t *testing.T) {
 
-	engine := nil
+	var engine consensus.Engine
 
 	db := rawdb.NewMemoryDatabase()
 	genesis := new(Genesis).MustCommit(db)
@@ -1174,7 +1177,7 @@ func TestBlockchainHeaderchainReorgConsistency(t *testing.T) {
 
 func TestTrieForkGC(t *testing.T) {
 
-	engine := nil
+	var engine consensus.Engine
 
 	db := rawdb.NewMemoryDatabase()
 	genesis := new(Genesis).MustCommit(db)
@@ -1217,7 +1220,7 @@ func TestTrieForkGC(t *testing.T) {
 
 func TestLargeReorgTrieGC(t *testing.T) {
 
-	engine := nil
+	var engine consensus.Engine
 
 	db := rawdb.NewMemoryDatabase()
 	genesis := new(Genesis).MustCommit(db)
@@ -5,6 +5,7 @@ import (
 	"math/big"
 
 	"github.com/neatio-net/neatio/chain/consensus"
+	"github.com/neatio-net/neatio/chain/consensus/misc"
 	"github.com/neatio-net/neatio/chain/core/state"
 	"github.com/neatio-net/neatio/chain/core/types"
 	"github.com/neatio-net/neatio/chain/core/vm"
@@ -156,7 +157,7 @@ func makeHeader(chain consensus.ChainReader, parent *types.Block, state *state.S
 		time = new(big.Int).SetUint64(parent.Time() + 10)
 	}
 
-	return &types.Header{
+	header := &types.Header{
 		Root:       state.IntermediateRoot(chain.Config().IsEIP158(parent.Number())),
 		ParentHash: parent.Hash(),
 		Coinbase:   parent.Coinbase(),
@@ -170,6 +171,10 @@ func makeHeader(chain consensus.ChainReader, parent *types.Block, state *state.S
 		Number:   new(big.Int).Add(parent.Number(), common.Big1),
 		Time:     time,
 	}
+	if chain.Config().IsLondon(header.Number) {
+		header.BaseFee = misc.CalcBaseFee(chain.Config(), parent.Header())
+	}
+	return header
 }
 
 func makeHeaderChain(parent *types.Header, n int, engine consensus.Engine, db neatdb.Database, seed int) []*types.Header {
@@ -125,7 +125,10 @@ func SaveChainInfo(db dbm.DB, ci *ChainInfo) error {
 		}
 	}
 
-	saveId(db, ci.ChainId)
+	// a decommissioned chain never goes back to the side chain ids
+	if len(db.Get(calcDecommissionedChainKey(ci.ChainId))) == 0 {
+		saveId(db, ci.ChainId)
+	}
 
 	return nil
 }
@@ -255,6 +258,29 @@ func saveId(db dbm.DB, chainId string) {
 	}
 }
 
+func removeId(db dbm.DB, chainId string) {
+
+	buf := db.Get(allChainKey)
+	if len(buf) == 0 {
+		return
+	}
+
+	strIdArr := strings.Split(string(buf), specialSep)
+	newIdArr := strIdArr[:0]
+	for _, id := range strIdArr {
+		if id != chainId {
+			newIdArr = append(newIdArr, id)
+		}
+	}
+
+	if len(newIdArr) != len(strIdArr) {
+		strIds := strings.Join(newIdArr, specialSep)
+		db.SetSync(allChainKey, []byte(strIds))
+
+		log.Debugf("ChainInfo RemoveId(), strIds is: %s", strIds)
+	}
+}
+
 func GetSideChainIds(db dbm.DB) []string {
 	mtx.RLock()
 	defer mtx.RUnlock()
@@ -326,6 +352,24 @@ func GetPendingSideChainData(db dbm.DB, chainId string) *CoreChainInfo {
 	return nil
 }
 
+// GetPendingSideChainIds returns the side chains created but not launched yet
+func GetPendingSideChainIds(db dbm.DB) []string {
+	pendingChainMtx.Lock()
+	defer pendingChainMtx.Unlock()
+
+	var idx []pendingIdxData
+	pendingIdxByteSlice := db.Get(pendingChainIndexKey)
+	if pendingIdxByteSlice != nil {
+		wire.ReadBinaryBytes(pendingIdxByteSlice, &idx)
+	}
+
+	ids := make([]string, 0, len(idx))
+	for _, v := range idx {
+		ids = append(ids, v.ChainID)
+	}
+	return ids
+}
+
 func CreatePendingSideChainData(db dbm.DB, cci *CoreChainInfo) {
 	storePendingSideChainData(db, cci, true)
 }
@@ -366,7 +410,7 @@ func DeletePendingSideChainData(db dbm.DB, chainId string) {
 	db.DeleteSync(calcPendingChainInfoKey(chainId))
 }
 
-func GetSideChainForLaunch(db dbm.DB, height *big.Int, stateDB *state.StateDB) (readyForLaunch []string, newPendingIdxBytes []byte, deleteSideChainIds []string) {
+func GetSideChainForLaunch(db dbm.DB, height *big.Int, stateDB *state.StateDB, neatFork bool) (readyForLaunch []string, newPendingIdxBytes []byte, deleteSideChainIds []string) {
 	pendingChainMtx.Lock()
 	defer pendingChainMtx.Unlock()
 
@@ -414,6 +458,15 @@ func GetSideChainForLaunch(db dbm.DB, height *big.Int, stateDB *state.StateDB) (
 					stateDB.AddChainBalance(cci.Owner, jv.DepositAmount)
 				}
 
+				if neatFork && cci.EndBlock != nil {
+					// the deposits are refunded from the state once the side chain is decommissioned
+					sc := &state.RunningSideChain{ChainId: v.ChainID, Owner: cci.Owner, EndBlock: new(big.Int).Set(cci.EndBlock)}
+					for _, jv := range cci.JoinedValidators {
+						sc.Deposits = append(sc.Deposits, &state.SideChainDeposit{Address: jv.Address, Amount: new(big.Int).Set(jv.DepositAmount)})
+					}
+					stateDB.AddRunningSideChain(sc)
+				}
+
 				readyForLaunch = append(readyForLaunch, v.ChainID)
 			} else {
 				newPendingIdx = append(newPendingIdx, v)
@@ -442,3 +495,128 @@ func ProcessPostPendingData(db dbm.DB, newPendingIdxBytes []byte, deleteSideChai
 		db.SetSync(pendingChainIndexKey, newPendingIdxBytes)
 	}
 }
+
+// SideChainState is a block of the side chain recorded on the main chain
+type SideChainState struct {
+	Height    uint64
+	BlockHash common.Hash
+	StateRoot common.Hash
+}
+
+// DecommissionedChain is kept on the main chain once the side chain is stopped
+type DecommissionedChain struct {
+	MainChainHeight *big.Int
+	FinalState      SideChainState
+}
+
+func calcSideChainAnchorKey(chainId string) []byte {
+	return []byte("SIDE_ANCHOR:" + chainId)
+}
+
+func calcDecommissionedChainKey(chainId string) []byte {
+	return []byte("DECOMMISSIONED:" + chainId)
+}
+
+// SaveSideChainAnchor records the latest verified block of the side chain
+func SaveSideChainAnchor(db dbm.DB, chainId string, st *SideChainState) {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if anchor := loadSideChainState(db, calcSideChainAnchorKey(chainId)); anchor != nil && anchor.Height >= st.Height {
+		return
+	}
+	db.SetSync(calcSideChainAnchorKey(chainId), wire.BinaryBytes(*st))
+}
+
+func GetSideChainAnchor(db dbm.DB, chainId string) *SideChainState {
+	mtx.RLock()
+	defer mtx.RUnlock()
+
+	return loadSideChainState(db, calcSideChainAnchorKey(chainId))
+}
+
+func loadSideChainState(db dbm.DB, key []byte) *SideChainState {
+	buf := db.Get(key)
+	if len(buf) == 0 {
+		return nil
+	}
+
+	var st SideChainState
+	if err := wire.ReadBinaryBytes(buf, &st); err != nil {
+		log.Errorf("Load side chain state failed: %v", err)
+		return nil
+	}
+	return &st
+}
+
+// SaveDecommissionedChain keeps the final state of the side chain and removes it from the side chain ids
+func SaveDecommissionedChain(db dbm.DB, chainId string, dc *DecommissionedChain) {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	db.SetSync(calcDecommissionedChainKey(chainId), wire.BinaryBytes(*dc))
+	removeId(db, chainId)
+}
+
+func GetDecommissionedChain(db dbm.DB, chainId string) *DecommissionedChain {
+	mtx.RLock()
+	defer mtx.RUnlock()
+
+	buf := db.Get(calcDecommissionedChainKey(chainId))
+	if len(buf) == 0 {
+		return nil
+	}
+
+	var dc DecommissionedChain
+	if err := wire.ReadBinaryBytes(buf, &dc); err != nil {
+		log.Errorf("Load decommissioned chain failed: %v", err)
+		return nil
+	}
+	return &dc
+}
+
+// GetSideChainForDecommission returns the running side chains whose end block is reached
+func GetSideChainForDecommission(height *big.Int, stateDB *state.StateDB) (readyForDecommission []string) {
+	for _, sc := range stateDB.GetRunningSideChains() {
+		if sc.EndBlock.Cmp(height) <= 0 {
+			readyForDecommission = append(readyForDecommission, sc.ChainId)
+		}
+	}
+	return
+}
+
+// SettleSideChainDeposits refunds the validator deposits and the startup cost
+// kept in the chain balance of the owner since the side chain launched, it
+// returns false if the side chain is not running in the state
+func SettleSideChainDeposits(chainId string, stateDB *state.StateDB) bool {
+	sc := stateDB.RemoveRunningSideChain(chainId)
+	if sc == nil {
+		return false
+	}
+
+	for _, d := range sc.Deposits {
+		stateDB.SubChainBalance(sc.Owner, d.Amount)
+		stateDB.AddBalance(d.Address, d.Amount)
+	}
+	settleStartupCost(sc.Owner, stateDB)
+	return true
+}
+
+// SettleLegacySideChainDeposits refunds the deposits of the side chain launched
+// before NeatFork, which is not recorded in the state
+func SettleLegacySideChainDeposits(cci *CoreChainInfo, stateDB *state.StateDB) {
+	for _, jv := range cci.JoinedValidators {
+		stateDB.SubChainBalance(cci.Owner, jv.DepositAmount)
+		stateDB.AddBalance(jv.Address, jv.DepositAmount)
+	}
+	settleStartupCost(cci.Owner, stateDB)
+}
+
+func settleStartupCost(owner common.Address, stateDB *state.StateDB) {
+	officialMinimumDeposit := math.MustParseBig256(OFFICIAL_MINIMUM_DEPOSIT)
+	stateDB.SubChainBalance(owner, officialMinimumDeposit)
+	stateDB.AddBalance(owner, officialMinimumDeposit)
+	if stateDB.GetChainBalance(owner).Sign() < 0 {
+		log.Error("the chain balance is below 0 after the side chain decommissioned, watch out!!!")
+	}
+}
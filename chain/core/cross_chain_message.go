@@ -0,0 +1,64 @@
+package core
+
+import (
+	"math/big"
+
+	ncTypes "github.com/neatio-net/neatio/chain/consensus/neatcon/types"
+	"github.com/neatio-net/neatio/chain/core/types"
+	"github.com/neatio-net/neatio/chain/core/vm"
+	"github.com/neatio-net/neatio/chain/log"
+	neatAbi "github.com/neatio-net/neatio/neatabi/abi"
+	"github.com/neatio-net/neatio/utilities/rlp"
+)
+
+// DecodeCrossChainMessage unpacks the proof data carried by the
+// DeliverCrossChainMessage tx and returns the message it proves
+func DecodeCrossChainMessage(tx *types.Transaction) (*types.CrossChainMessageProofData, *types.CrossChainMessage, error) {
+
+	var args neatAbi.DeliverCrossChainMessageArgs
+	data := tx.Data()
+	if err := neatAbi.ChainABI.UnpackMethodInputs(&args, neatAbi.DeliverCrossChainMessage.String(), data[4:]); err != nil {
+		return nil, nil, err
+	}
+
+	var proofData types.CrossChainMessageProofData
+	if err := rlp.DecodeBytes(args.ProofData, &proofData); err != nil {
+		return nil, nil, err
+	}
+
+	msg, err := proofData.Message()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &proofData, msg, nil
+}
+
+// callCrossChainMessageTarget delivers the message to the target contract with
+// the gas left by the tx, the call comes from the neatio contract address so
+// the target can trust the source chain and the sender passed to it
+func callCrossChainMessageTarget(vmenv *vm.EVM, tx *types.Transaction, gas uint64) (usedGas uint64, failed bool) {
+
+	proofData, msg, err := DecodeCrossChainMessage(tx)
+	if err != nil {
+		return 0, true
+	}
+	ncExtra, err := ncTypes.ExtractNeatConExtra(proofData.Header)
+	if err != nil {
+		return 0, true
+	}
+
+	input, err := neatAbi.CrossChainReceiverABI.Pack("receiveCrossChainMessage", ncExtra.ChainID, msg.Sender, msg.Payload)
+	if err != nil {
+		return 0, true
+	}
+
+	if rules := vmenv.ChainConfig().Rules(vmenv.BlockNumber); rules.IsBerlin {
+		vmenv.StateDB.PrepareAccessList(neatAbi.NeatioSmartContractAddress, &msg.Target, vm.ActivePrecompiles(rules), nil)
+	}
+	_, leftOverGas, err := vmenv.Call(vm.AccountRef(neatAbi.NeatioSmartContractAddress), msg.Target, input, gas, big.NewInt(0))
+	if err != nil {
+		log.Debugf("cross chain message %x delivered to %x failed: %v", proofData.MessageId(), msg.Target, err)
+		return gas - leftOverGas, true
+	}
+	return gas - leftOverGas, false
+}
@@ -33,6 +33,18 @@ var (
 	// next one expected based on the local chain.
 	ErrNonceTooHigh = errors.New("nonce too high")
 
+	// ErrFeeCapTooLow is returned if the transaction fee cap is less than the
+	// base fee of the block.
+	ErrFeeCapTooLow = errors.New("max fee per gas less than block base fee")
+
+	// ErrTipAboveFeeCap is returned if the transaction tip is higher than its
+	// fee cap.
+	ErrTipAboveFeeCap = errors.New("max priority fee per gas higher than max fee per gas")
+
+	// ErrMaxInitCodeSizeExceeded is returned if the init code of a contract
+	// creation transaction exceeds the limit of EIP-3860.
+	ErrMaxInitCodeSizeExceeded = errors.New("max initcode size exceeded")
+
 	// ErrInvalidTx4 is returned if the tx4 has been checked during execution
 	ErrInvalidTx4 = errors.New("invalid Tx4")
 
@@ -69,6 +81,39 @@ var (
 
 	ErrBannedUnRegister = errors.New("banned candidate can not unregister")
 
+	// ErrAlreadyBanned is returned if the reported validator has already been banned
+	ErrAlreadyBanned = errors.New("address already banned")
+
+	// ErrEvidenceTooOld is returned if the double sign evidence is older than the previous epoch
+	ErrEvidenceTooOld = errors.New("double sign evidence too old")
+
+	// ErrNotBanned is returned if the request address is not banned
+	ErrNotBanned = errors.New("address not banned")
+
+	// ErrTombstoned is returned if the banned address double signed, it can never be unbanned
+	ErrTombstoned = errors.New("double signing validator can not be unbanned")
+
+	// ErrJailPeriod is returned if the downtime ban of the address has not lasted the minimum jail period yet
+	ErrJailPeriod = errors.New("banned address can not be unbanned before the end of the jail period")
+
+	// ErrBannedStillValidator is returned if a banned validator has not been removed from the validator set yet
+	ErrBannedStillValidator = errors.New("banned validator can not be unbanned before leaving the validator set")
+
+	// ErrRedelegateSameCandidate is returned if the delegation is redelegated to the same candidate
+	ErrRedelegateSameCandidate = errors.New("can not redelegate to the same candidate")
+
+	// ErrRedelegateHop is returned if the delegation redelegated to the candidate in this epoch is redelegated again
+	ErrRedelegateHop = errors.New("delegation redelegated to the candidate can not be redelegated again before the next epoch")
+
+	// ErrExceedRedelegationLimit is returned if the delegator has too many redelegations pending for the next epoch
+	ErrExceedRedelegationLimit = errors.New("exceed the pending redelegation limit")
+
+	// ErrAutoCompoundSelf is returned if the candidate sets the auto compound of its own rewards
+	ErrAutoCompoundSelf = errors.New("candidate can not compound the rewards to itself")
+
+	// ErrNoDelegation is returned if the address has no delegation to the candidate
+	ErrNoDelegation = errors.New("address has no delegation to the candidate")
+
 	//ErrExceedDelegationAddressLimit is returned if delegated address number exceed the limit
 	ErrExceedDelegationAddressLimit = errors.New("exceed the delegation address limit")
 
@@ -85,12 +130,33 @@ var (
 	// ErrVoteAmountTooHight is returned if the vote amount greater than proxied amount + self amount
 	ErrVoteAmountTooHight = errors.New("vote amount too high")
 
+	// ErrNotInHashVoteStage is returned if the vote hash is sent after the hash vote stage of the epoch
+	ErrNotInHashVoteStage = errors.New("not in the hash vote stage of the epoch")
+
+	// ErrNotInRevealVoteStage is returned if the vote is revealed outside the reveal vote stage of the epoch
+	ErrNotInRevealVoteStage = errors.New("not in the reveal vote stage of the epoch")
+
+	// ErrVoteNotFound is returned if the address did not send a vote hash for the next epoch
+	ErrVoteNotFound = errors.New("no vote of the address for the next epoch")
+
+	// ErrVoteHashMismatch is returned if the revealed vote does not match the vote hash
+	ErrVoteHashMismatch = errors.New("revealed vote does not match the vote hash")
+
 	// ErrNotOwner is returned if the Address not owner
 	ErrNotOwner = errors.New("address not owner")
 
 	// ErrNotAllowedInMainChain is returned if the transaction with main flag = false be sent to main chain
 	ErrNotAllowedInMainChain = errors.New("transaction not allowed in main chain")
 
+	// ErrCrossChainMessageDelivered is returned if the cross chain message has been delivered on this chain
+	ErrCrossChainMessageDelivered = errors.New("cross chain message already delivered")
+
+	// ErrCrossChainMessageWrongChain is returned if the cross chain message is delivered to another chain than its target
+	ErrCrossChainMessageWrongChain = errors.New("cross chain message not sent to this chain")
+
+	// ErrSideChainTransferSettled is returned if the side chain transfer has been settled or credited on this chain
+	ErrSideChainTransferSettled = errors.New("side chain transfer already settled")
+
 	// ErrNotAllowedInSideChain is returned if the transaction with side flag = false be sent to side chain
 	ErrNotAllowedInSideChain = errors.New("transaction not allowed in side chain")
 )
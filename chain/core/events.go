@@ -61,6 +61,11 @@ type CreateSideChainEvent struct {
 	ChainId string
 }
 
+// Decommission Side Chain Event
+type DecommissionSideChainEvent struct {
+	ChainId string
+}
+
 // Start Mining Event
 type StartMiningEvent struct{}
 
@@ -20,6 +20,7 @@ import (
 	"math/big"
 
 	"github.com/neatio-net/neatio/chain/consensus"
+	ncTypes "github.com/neatio-net/neatio/chain/consensus/neatcon/types"
 	"github.com/neatio-net/neatio/chain/core/types"
 	"github.com/neatio-net/neatio/chain/core/vm"
 	"github.com/neatio-net/neatio/utilities/common"
@@ -44,18 +45,33 @@ func NewEVMContext(msg Message, header *types.Header, chain ChainContext, author
 	} else {
 		beneficiary = *author
 	}
+	var baseFee *big.Int
+	if header.BaseFee != nil {
+		baseFee = new(big.Int).Set(header.BaseFee)
+	}
 	return vm.Context{
-		CanTransfer: CanTransfer,
-		Transfer:    Transfer,
-		GetHash:     GetHashFn(header, chain),
-		Origin:      msg.From(),
-		Coinbase:    beneficiary,
-		BlockNumber: new(big.Int).Set(header.Number),
-		Time:        new(big.Int).Set(header.Time),
-		Difficulty:  new(big.Int).Set(header.Difficulty),
-		GasLimit:    header.GasLimit,
-		GasPrice:    new(big.Int).Set(msg.GasPrice()),
+		CanTransfer:   CanTransfer,
+		Transfer:      Transfer,
+		GetHash:       GetHashFn(header, chain),
+		HeaderChainId: HeaderChainId,
+		Origin:        msg.From(),
+		Coinbase:      beneficiary,
+		BlockNumber:   new(big.Int).Set(header.Number),
+		Time:          new(big.Int).Set(header.Time),
+		Difficulty:    new(big.Int).Set(header.Difficulty),
+		GasLimit:      header.GasLimit,
+		GasPrice:      new(big.Int).Set(msg.GasPrice()),
+		BaseFee:       baseFee,
+	}
+}
+
+// HeaderChainId returns the chain id in the NeatCon extra data of the header
+func HeaderChainId(header *types.Header) (string, error) {
+	ncExtra, err := ncTypes.ExtractNeatConExtra(header)
+	if err != nil {
+		return "", err
 	}
+	return ncExtra.ChainID, nil
 }
 
 // GetHashFn returns a GetHashFunc which retrieves header hashes by number
@@ -259,7 +259,9 @@ func (g *Genesis) ToBlock(db neatdb.Database) *types.Block {
 	for addr, account := range g.Alloc {
 		statedb.AddBalance(addr, account.Balance)
 		// Deposit Balance for POS
-		statedb.AddDepositBalance(addr, account.Amount)
+		if account.Amount != nil {
+			statedb.AddDepositBalance(addr, account.Amount)
+		}
 
 		// Delegate Balance
 		if account.DelegateBalance != nil {
@@ -305,6 +307,9 @@ func (g *Genesis) ToBlock(db neatdb.Database) *types.Block {
 	if g.Difficulty == nil {
 		head.Difficulty = params.GenesisDifficulty
 	}
+	if g.Config != nil && g.Config.IsLondon(head.Number) {
+		head.BaseFee = new(big.Int).SetUint64(params.InitialBaseFee)
+	}
 	statedb.Commit(false)
 	statedb.Database().TrieDB().Commit(root, true)
 
@@ -26,6 +26,12 @@ func ApplyOp(op types.PendingOp, bc *BlockChain, cch CrossChainHelper) error {
 			cch.ProcessPostPendingData(op.NewPendingIdx, op.DeleteSideChainIds)
 		}
 		return nil
+	case *types.DecommissionSideChainOp:
+		if err := cch.DecommissionSideChain(op.ChainId, bc.CurrentBlock().Number(), op.ProofData); err != nil {
+			return err
+		}
+		bc.PostChainEvents([]interface{}{DecommissionSideChainEvent{ChainId: op.ChainId}}, nil)
+		return nil
 	case *types.VoteNextEpochOp:
 		ep := bc.engine.(consensus.NeatCon).GetEpoch()
 		ep = ep.GetEpochByBlockNumber(bc.CurrentBlock().NumberU64())
@@ -42,7 +48,7 @@ func ApplyOp(op types.PendingOp, bc *BlockChain, cch CrossChainHelper) error {
 		return cch.SaveSideChainProofDataToMainChain(op.Data)
 	case *ncTypes.SwitchEpochOp:
 		eng := bc.engine.(consensus.NeatCon)
-		nextEp, err := eng.GetEpoch().EnterNewEpoch(op.NewValidators)
+		nextEp, err := eng.GetEpoch().EnterNewEpoch(op.NewValidators, op.Changes)
 		if err == nil {
 
 			if !op.NewValidators.HasAddress(eng.PrivateValidator().Bytes()) && eng.IsStarted() {
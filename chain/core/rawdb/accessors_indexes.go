@@ -117,3 +117,18 @@ func WriteBloomBits(db neatdb.Writer, bit uint, section uint64, head common.Hash
 		log.Crit("Failed to store bloom bits", "err", err)
 	}
 }
+
+// ReadSigningStats retrieves the validator signing stats of the epoch gathered
+// from the given section.
+func ReadSigningStats(db neatdb.Reader, epoch uint64, section uint64) []byte {
+	data, _ := db.Get(signingStatsKey(epoch, section))
+	return data
+}
+
+// WriteSigningStats stores the validator signing stats of the epoch gathered
+// from the given section.
+func WriteSigningStats(db neatdb.Writer, epoch uint64, section uint64, stats []byte) {
+	if err := db.Put(signingStatsKey(epoch, section), stats); err != nil {
+		log.Crit("Failed to store signing stats", "err", err)
+	}
+}
@@ -50,14 +50,16 @@ var (
 	blockBodyPrefix     = []byte("b") // blockBodyPrefix + num (uint64 big endian) + hash -> block body
 	blockReceiptsPrefix = []byte("r") // blockReceiptsPrefix + num (uint64 big endian) + hash -> block receipts
 
-	txLookupPrefix  = []byte("l") // txLookupPrefix + hash -> transaction/receipt lookup metadata
-	bloomBitsPrefix = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
+	txLookupPrefix     = []byte("l") // txLookupPrefix + hash -> transaction/receipt lookup metadata
+	bloomBitsPrefix    = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
+	signingStatsPrefix = []byte("V") // signingStatsPrefix + epoch (uint64 big endian) + section (uint64 big endian) -> validator signing stats
 
 	preimagePrefix = []byte("secure-key-")      // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-") // config prefix for the db
 
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
-	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
+	BloomBitsIndexPrefix    = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
+	SigningStatsIndexPrefix = []byte("iV") // SigningStatsIndexPrefix is the data table of the signing stats indexer to track its progress
 
 	preimageCounter    = metrics.NewRegisteredCounter("db/preimage/total", nil)
 	preimageHitCounter = metrics.NewRegisteredCounter("db/preimage/hits", nil)
@@ -128,6 +130,17 @@ func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	return key
 }
 
+// signingStatsKey = signingStatsPrefix + epoch (uint64 big endian) + section (uint64 big endian)
+func signingStatsKey(epoch uint64, section uint64) []byte {
+	key := make([]byte, len(signingStatsPrefix)+16)
+	copy(key, signingStatsPrefix)
+
+	binary.BigEndian.PutUint64(key[1:], epoch)
+	binary.BigEndian.PutUint64(key[9:], section)
+
+	return key
+}
+
 // preimageKey = preimagePrefix + hash
 func preimageKey(hash common.Hash) []byte {
 	return append(preimagePrefix, hash.Bytes()...)
@@ -78,6 +78,9 @@ func GetTX3ProofData(db neatdb.Reader, chainId string, txHash common.Hash) *type
 	}
 	ret.TxIndexs[0] = proofData.TxIndexs[i]
 	ret.TxProofs[0] = proofData.TxProofs[i]
+	if i < len(proofData.ReceiptProofs) {
+		ret.ReceiptProofs = []*types.BSKeyValueSet{proofData.ReceiptProofs[i]}
+	}
 
 	return &ret
 }
@@ -162,6 +165,19 @@ func WriteTX3ProofData(db neatdb.Database, proofData *types.TX3ProofData) error
 					return err
 				}
 
+				// keep the receipt proofs at the index of their txs, an empty proof
+				// stands for the receipt not sent
+				if len(proofData.ReceiptProofs) > 0 || len(existProofData.ReceiptProofs) > 0 {
+					for len(existProofData.ReceiptProofs) < len(existProofData.TxIndexs) {
+						existProofData.ReceiptProofs = append(existProofData.ReceiptProofs, types.MakeBSKeyValueSet())
+					}
+					receiptProof := types.MakeBSKeyValueSet()
+					if i < len(proofData.ReceiptProofs) {
+						receiptProof = proofData.ReceiptProofs[i]
+					}
+					existProofData.ReceiptProofs = append(existProofData.ReceiptProofs, receiptProof)
+				}
+
 				existProofData.TxIndexs = append(existProofData.TxIndexs, txIndex)
 				existProofData.TxProofs = append(existProofData.TxProofs, proofData.TxProofs[i])
 				update = true
@@ -197,7 +213,7 @@ func WriteTX3(db neatdb.Writer, chainId string, header *types.Header, txIndex ui
 	}
 
 	var tx types.Transaction
-	err = rlp.DecodeBytes(val, &tx)
+	err = tx.UnmarshalBinary(val)
 	if err != nil {
 		return err
 	}
@@ -209,7 +225,7 @@ func WriteTX3(db neatdb.Writer, chainId string, header *types.Header, txIndex ui
 			return err
 		}
 
-		if function == neatAbi.WithdrawFromSideChain {
+		if function == neatAbi.WithdrawFromSideChain || function == neatAbi.TransferToSideChain {
 			txHash := tx.Hash()
 			key1 := append(tx3Prefix, append([]byte(chainId), txHash.Bytes()...)...)
 			bs, _ := rlp.EncodeToBytes(&tx)
@@ -272,6 +288,9 @@ func DeleteTX3(db neatdb.Database, chainId string, txHash common.Hash) {
 
 	proofData.TxIndexs = append(proofData.TxIndexs[:i], proofData.TxIndexs[i+1:]...)
 	proofData.TxProofs = append(proofData.TxProofs[:i], proofData.TxProofs[i+1:]...)
+	if i < len(proofData.ReceiptProofs) {
+		proofData.ReceiptProofs = append(proofData.ReceiptProofs[:i], proofData.ReceiptProofs[i+1:]...)
+	}
 	if len(proofData.TxIndexs) == 0 {
 		// delete the whole proof data
 		db.Delete(key3)
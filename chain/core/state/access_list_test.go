@@ -0,0 +1,51 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/neatio-net/neatio/chain/core/types"
+	"github.com/neatio-net/neatio/neatdb/memorydb"
+	"github.com/neatio-net/neatio/utilities/common"
+)
+
+func TestAccessListRevert(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(memorydb.New()))
+
+	sender := common.BytesToAddress([]byte{1})
+	dest := common.BytesToAddress([]byte{2})
+	listed := common.BytesToAddress([]byte{3})
+	state.PrepareAccessList(sender, &dest, nil, types.AccessList{
+		{Address: listed, StorageKeys: []common.Hash{{1}}},
+	})
+	for _, addr := range []common.Address{sender, dest, listed} {
+		if !state.AddressInAccessList(addr) {
+			t.Fatalf("address %x missing from the access list", addr)
+		}
+	}
+	if _, slotOk := state.SlotInAccessList(listed, common.Hash{1}); !slotOk {
+		t.Fatalf("slot of the tx access list missing")
+	}
+
+	snapshot := state.Snapshot()
+	other := common.BytesToAddress([]byte{4})
+	state.AddSlotToAccessList(other, common.Hash{2})
+	state.AddSlotToAccessList(listed, common.Hash{3})
+	if addrOk, slotOk := state.SlotInAccessList(other, common.Hash{2}); !addrOk || !slotOk {
+		t.Fatalf("added slot missing from the access list")
+	}
+
+	copied := state.Copy()
+	state.RevertToSnapshot(snapshot)
+	if state.AddressInAccessList(other) {
+		t.Errorf("address still in the access list after revert")
+	}
+	if _, slotOk := state.SlotInAccessList(listed, common.Hash{3}); slotOk {
+		t.Errorf("slot still in the access list after revert")
+	}
+	if _, slotOk := state.SlotInAccessList(listed, common.Hash{1}); !slotOk {
+		t.Errorf("slot added before the snapshot was reverted")
+	}
+	if _, slotOk := copied.SlotInAccessList(other, common.Hash{2}); !slotOk {
+		t.Errorf("copy changed with the reverted original")
+	}
+}
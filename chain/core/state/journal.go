@@ -105,6 +105,14 @@ type (
 		prev    uint8
 	}
 
+	accessListAddAccountChange struct {
+		address *common.Address
+	}
+	accessListAddSlotChange struct {
+		address *common.Address
+		slot    *common.Hash
+	}
+
 	bannedChange struct {
 		account *common.Address
 		prev    bool
@@ -133,6 +141,12 @@ type (
 	refundChange struct {
 		prev uint64
 	}
+	delegateRefundSetChange struct {
+		account   *common.Address
+		prevDirty bool
+	}
+	stakingCandidateChange struct{}
+
 	addLogChange struct {
 		txhash common.Hash
 	}
@@ -275,6 +289,15 @@ func (ch refundChange) undo(s *StateDB) {
 	s.refund = ch.prev
 }
 
+func (ch delegateRefundSetChange) undo(s *StateDB) {
+	delete(s.delegateRefundSet, *ch.account)
+	s.delegateRefundSetDirty = ch.prevDirty
+}
+
+func (ch stakingCandidateChange) undo(s *StateDB) {
+	s.stakingCandidates = s.stakingCandidates[:len(s.stakingCandidates)-1]
+}
+
 func (ch addLogChange) undo(s *StateDB) {
 	logs := s.logs[ch.txhash]
 	if len(logs) == 1 {
@@ -285,6 +308,21 @@ func (ch addLogChange) undo(s *StateDB) {
 	s.logSize--
 }
 
+func (ch accessListAddAccountChange) undo(s *StateDB) {
+	// One important invariant here, is that whenever a (addr, slot) is added, if the
+	// addr is not already present, the add causes two journal entries:
+	// - one for the address,
+	// - one for the (address,slot)
+	// Therefore, when unrolling the change, we can always blindly delete the
+	// (addr) at this point, since no storage adds can remain when come upon
+	// a single (addr) change.
+	s.accessList.DeleteAddress(*ch.address)
+}
+
+func (ch accessListAddSlotChange) undo(s *StateDB) {
+	s.accessList.DeleteSlot(*ch.address, *ch.slot)
+}
+
 func (ch addPreimageChange) undo(s *StateDB) {
 	delete(s.preimages, ch.hash)
 }
@@ -19,14 +19,14 @@ package state
 import (
 	"testing"
 
-	"github.com/neatio-net/neatio/chain/core/rawdb"
+	"github.com/neatio-net/neatio/neatdb/memorydb"
 	"github.com/neatio-net/neatio/utilities/common"
 )
 
 var addr = common.BytesToAddress([]byte("test"))
 
 func create() (*ManagedState, *account) {
-	db := rawdb.NewMemoryDatabase()
+	db := memorydb.New()
 	statedb, _ := New(common.Hash{}, NewDatabase(db))
 	ms := ManageState(statedb)
 	ms.StateDB.SetNonce(addr, 100)
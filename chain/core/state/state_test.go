@@ -21,8 +21,8 @@ import (
 	"math/big"
 	"testing"
 
-	"github.com/neatio-net/neatio/chain/core/rawdb"
 	"github.com/neatio-net/neatio/neatdb"
+	"github.com/neatio-net/neatio/neatdb/memorydb"
 	"github.com/neatio-net/neatio/utilities/common"
 	"github.com/neatio-net/neatio/utilities/crypto"
 	checker "gopkg.in/check.v1"
@@ -88,7 +88,7 @@ func (s *StateSuite) TestDump(c *checker.C) {
 }
 
 func (s *StateSuite) SetUpTest(c *checker.C) {
-	s.db = rawdb.NewMemoryDatabase()
+	s.db = memorydb.New()
 	s.state, _ = New(common.Hash{}, NewDatabase(s.db))
 }
 
@@ -134,7 +134,7 @@ func (s *StateSuite) TestSnapshotEmpty(c *checker.C) {
 // use testing instead of checker because checker does not support
 // printing/logging in tests (-check.vv does not work)
 func TestSnapshot2(t *testing.T) {
-	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()))
+	state, _ := New(common.Hash{}, NewDatabase(memorydb.New()))
 
 	stateobjaddr0 := toAddr([]byte("so0"))
 	stateobjaddr1 := toAddr([]byte("so1"))
@@ -72,6 +72,42 @@ type StateDB struct {
 	bannedSet      BannedSet
 	bannedSetDirty bool
 
+	// ban heights and double sign tombstones of the banned addresses
+	jailSet      JailSet
+	jailSetDirty bool
+
+	// missed blocks of the validators in current epoch
+	missedBlocks      MissedBlocksSet
+	missedBlocksDirty bool
+
+	// redelegations applied when entering the next epoch
+	redelegations      []*Redelegation
+	redelegationsDirty bool
+
+	// delegations compounding the rewards when entering the next epoch
+	autoCompoundSet      []*AutoCompound
+	autoCompoundSetDirty bool
+
+	// cross chain messages delivered in this state
+	deliveredMessages      map[common.Hash]struct{}
+	deliveredMessagesDirty bool
+
+	// side chain blocks saved to the main chain in this state
+	sideChainAnchors      map[string]sideChainAnchor
+	sideChainAnchorsDirty bool
+
+	// side chains launched and not decommissioned yet
+	runningSideChains      []*RunningSideChain
+	runningSideChainsDirty bool
+
+	// descriptions of the candidates changed in this state
+	candidateDescriptions      map[common.Address]*CandidateDescription
+	candidateDescriptionsDirty bool
+
+	// validator votes for the side chain block reward
+	blockRewardVotes      []*BlockRewardVote
+	blockRewardVotesDirty bool
+
 	// Cache of Side Chain Reward Per Block
 	sideChainRewardPerBlock      *big.Int
 	sideChainRewardPerBlockDirty bool
@@ -93,6 +129,12 @@ type StateDB struct {
 
 	preimages map[common.Hash][]byte
 
+	// Per-transaction access list
+	accessList *accessList
+
+	// Per-transaction candidates touched by the staking precompile
+	stakingCandidates []common.Address
+
 	// Journal of state modifications. This is the backbone of
 	// Snapshot and RevertToSnapshot.
 	journal        journal
@@ -120,10 +162,29 @@ func New(root common.Hash, db Database) (*StateDB, error) {
 		candidateSetDirty:            false,
 		bannedSet:                    make(BannedSet),
 		bannedSetDirty:               false,
+		jailSet:                      make(JailSet),
+		jailSetDirty:                 false,
+		missedBlocks:                 make(MissedBlocksSet),
+		missedBlocksDirty:            false,
+		redelegations:                nil,
+		redelegationsDirty:           false,
+		autoCompoundSet:              nil,
+		autoCompoundSetDirty:         false,
+		deliveredMessages:            make(map[common.Hash]struct{}),
+		deliveredMessagesDirty:       false,
+		sideChainAnchors:             make(map[string]sideChainAnchor),
+		sideChainAnchorsDirty:        false,
+		runningSideChains:            nil,
+		runningSideChainsDirty:       false,
+		candidateDescriptions:        make(map[common.Address]*CandidateDescription),
+		candidateDescriptionsDirty:   false,
+		blockRewardVotes:             nil,
+		blockRewardVotesDirty:        false,
 		sideChainRewardPerBlock:      nil,
 		sideChainRewardPerBlockDirty: false,
 		logs:                         make(map[common.Hash][]*types.Log),
 		preimages:                    make(map[common.Hash][]byte),
+		accessList:                   newAccessList(),
 	}, nil
 }
 
@@ -152,6 +213,15 @@ func (self *StateDB) Reset(root common.Hash) error {
 	self.rewardSet = make(RewardSet)
 	self.candidateSet = make(CandidateSet)
 	self.bannedSet = make(BannedSet)
+	self.jailSet = make(JailSet)
+	self.missedBlocks = make(MissedBlocksSet)
+	self.redelegations = nil
+	self.autoCompoundSet = nil
+	self.deliveredMessages = make(map[common.Hash]struct{})
+	self.sideChainAnchors = make(map[string]sideChainAnchor)
+	self.runningSideChains = nil
+	self.candidateDescriptions = make(map[common.Address]*CandidateDescription)
+	self.blockRewardVotes = nil
 	self.sideChainRewardPerBlock = nil
 	self.thash = common.Hash{}
 	self.bhash = common.Hash{}
@@ -159,6 +229,7 @@ func (self *StateDB) Reset(root common.Hash) error {
 	self.logs = make(map[common.Hash][]*types.Log)
 	self.logSize = 0
 	self.preimages = make(map[common.Hash][]byte)
+	self.accessList = newAccessList()
 	self.clearJournalAndRefund()
 	return nil
 }
@@ -644,11 +715,31 @@ func (self *StateDB) Copy() *StateDB {
 		candidateSetDirty:            self.candidateSetDirty,
 		bannedSet:                    make(BannedSet, len(self.bannedSet)),
 		bannedSetDirty:               self.bannedSetDirty,
+		jailSet:                      make(JailSet, len(self.jailSet)),
+		jailSetDirty:                 self.jailSetDirty,
+		missedBlocks:                 make(MissedBlocksSet, len(self.missedBlocks)),
+		missedBlocksDirty:            self.missedBlocksDirty,
+		redelegations:                make([]*Redelegation, 0, len(self.redelegations)),
+		redelegationsDirty:           self.redelegationsDirty,
+		autoCompoundSet:              make([]*AutoCompound, 0, len(self.autoCompoundSet)),
+		autoCompoundSetDirty:         self.autoCompoundSetDirty,
+		deliveredMessages:            make(map[common.Hash]struct{}, len(self.deliveredMessages)),
+		deliveredMessagesDirty:       self.deliveredMessagesDirty,
+		sideChainAnchors:             make(map[string]sideChainAnchor, len(self.sideChainAnchors)),
+		sideChainAnchorsDirty:        self.sideChainAnchorsDirty,
+		runningSideChains:            make([]*RunningSideChain, 0, len(self.runningSideChains)),
+		runningSideChainsDirty:       self.runningSideChainsDirty,
+		candidateDescriptions:        make(map[common.Address]*CandidateDescription, len(self.candidateDescriptions)),
+		candidateDescriptionsDirty:   self.candidateDescriptionsDirty,
+		blockRewardVotes:             make([]*BlockRewardVote, 0, len(self.blockRewardVotes)),
+		blockRewardVotesDirty:        self.blockRewardVotesDirty,
 		sideChainRewardPerBlockDirty: self.sideChainRewardPerBlockDirty,
 		refund:                       self.refund,
 		logs:                         make(map[common.Hash][]*types.Log, len(self.logs)),
 		logSize:                      self.logSize,
 		preimages:                    make(map[common.Hash][]byte, len(self.preimages)),
+		accessList:                   self.accessList.Copy(),
+		stakingCandidates:            append([]common.Address(nil), self.stakingCandidates...),
 	}
 	// Copy the dirty states, logs, and preimages
 	for addr := range self.stateObjectsDirty {
@@ -670,6 +761,65 @@ func (self *StateDB) Copy() *StateDB {
 		state.bannedSet[addr] = struct{}{}
 	}
 
+	for addr, entry := range self.jailSet {
+		state.jailSet[addr] = entry
+	}
+
+	for addr, count := range self.missedBlocks {
+		state.missedBlocks[addr] = count
+	}
+
+	for _, r := range self.redelegations {
+		state.redelegations = append(state.redelegations, &Redelegation{
+			Delegator: r.Delegator,
+			From:      r.From,
+			To:        r.To,
+			Amount:    new(big.Int).Set(r.Amount),
+		})
+	}
+
+	for _, c := range self.autoCompoundSet {
+		state.autoCompoundSet = append(state.autoCompoundSet, &AutoCompound{
+			Delegator: c.Delegator,
+			Candidate: c.Candidate,
+		})
+	}
+
+	for id := range self.deliveredMessages {
+		state.deliveredMessages[id] = struct{}{}
+	}
+
+	for key, anchor := range self.sideChainAnchors {
+		state.sideChainAnchors[key] = anchor
+	}
+
+	for _, sc := range self.runningSideChains {
+		cpy := &RunningSideChain{
+			ChainId:  sc.ChainId,
+			Owner:    sc.Owner,
+			EndBlock: new(big.Int).Set(sc.EndBlock),
+		}
+		for _, d := range sc.Deposits {
+			cpy.Deposits = append(cpy.Deposits, &SideChainDeposit{Address: d.Address, Amount: new(big.Int).Set(d.Amount)})
+		}
+		state.runningSideChains = append(state.runningSideChains, cpy)
+	}
+
+	for addr, desc := range self.candidateDescriptions {
+		if desc != nil {
+			cpy := *desc
+			desc = &cpy
+		}
+		state.candidateDescriptions[addr] = desc
+	}
+
+	for _, v := range self.blockRewardVotes {
+		state.blockRewardVotes = append(state.blockRewardVotes, &BlockRewardVote{
+			Validator: v.Validator,
+			Reward:    new(big.Int).Set(v.Reward),
+		})
+	}
+
 	if self.sideChainRewardPerBlock != nil {
 		state.sideChainRewardPerBlock = new(big.Int).Set(self.sideChainRewardPerBlock)
 	}
@@ -746,7 +896,6 @@ func (s *StateDB) Finalise(deleteEmptyObjects bool) {
 	}
 
 	if s.candidateSetDirty {
-		fmt.Printf("candidate set bug, candidate set dirty commit candidate set")
 		s.commitCandidateSet()
 	}
 
@@ -754,6 +903,42 @@ func (s *StateDB) Finalise(deleteEmptyObjects bool) {
 		s.commitBannedSet()
 	}
 
+	if s.jailSetDirty {
+		s.commitJailSet()
+	}
+
+	if s.missedBlocksDirty {
+		s.commitMissedBlocks()
+	}
+
+	if s.redelegationsDirty {
+		s.commitPendingRedelegations()
+	}
+
+	if s.autoCompoundSetDirty {
+		s.commitAutoCompoundSet()
+	}
+
+	if s.deliveredMessagesDirty {
+		s.commitDeliveredMessages()
+	}
+
+	if s.sideChainAnchorsDirty {
+		s.commitSideChainAnchors()
+	}
+
+	if s.runningSideChainsDirty {
+		s.commitRunningSideChains()
+	}
+
+	if s.candidateDescriptionsDirty {
+		s.commitCandidateDescriptions()
+	}
+
+	if s.blockRewardVotesDirty {
+		s.commitBlockRewardVotes()
+	}
+
 	// Update Side Chain Reward per Block if something changed
 	if s.sideChainRewardPerBlockDirty {
 		s.commitSideChainRewardPerBlock()
@@ -780,6 +965,65 @@ func (self *StateDB) Prepare(thash, bhash common.Hash, ti int) {
 	self.txIndex = ti
 }
 
+// PrepareAccessList handles the preparatory steps for executing a state
+// transition with regards to EIP-2929 and EIP-2930: the sender, the
+// destination, the precompiles and the optional tx access list are added
+// to a fresh access list. This should only be called if Berlin is active.
+func (self *StateDB) PrepareAccessList(sender common.Address, dst *common.Address, precompiles []common.Address, list types.AccessList) {
+	// Clear out any leftover from previous executions
+	self.accessList = newAccessList()
+
+	self.AddAddressToAccessList(sender)
+	if dst != nil {
+		self.AddAddressToAccessList(*dst)
+		// If it's a create-tx, the destination will be added inside evm.create
+	}
+	for _, addr := range precompiles {
+		self.AddAddressToAccessList(addr)
+	}
+	for _, el := range list {
+		self.AddAddressToAccessList(el.Address)
+		for _, key := range el.StorageKeys {
+			self.AddSlotToAccessList(el.Address, key)
+		}
+	}
+}
+
+// AddAddressToAccessList adds the given address to the access list
+func (self *StateDB) AddAddressToAccessList(addr common.Address) {
+	if self.accessList.AddAddress(addr) {
+		self.journal = append(self.journal, accessListAddAccountChange{&addr})
+	}
+}
+
+// AddSlotToAccessList adds the given (address, slot)-tuple to the access list
+func (self *StateDB) AddSlotToAccessList(addr common.Address, slot common.Hash) {
+	addrMod, slotMod := self.accessList.AddSlot(addr, slot)
+	if addrMod {
+		// In practice, this should not happen, since there is no way to enter the
+		// scope of 'address' without having the 'address' become already added
+		// to the access list (via call-variant, create, etc).
+		// Better safe than sorry, though
+		self.journal = append(self.journal, accessListAddAccountChange{&addr})
+	}
+	if slotMod {
+		self.journal = append(self.journal, accessListAddSlotChange{
+			address: &addr,
+			slot:    &slot,
+		})
+	}
+}
+
+// AddressInAccessList returns true if the given address is in the access list.
+func (self *StateDB) AddressInAccessList(addr common.Address) bool {
+	return self.accessList.ContainsAddress(addr)
+}
+
+// SlotInAccessList returns true if the given (address, slot)-tuple is in the access list.
+func (self *StateDB) SlotInAccessList(addr common.Address, slot common.Hash) (addressPresent bool, slotPresent bool) {
+	return self.accessList.Contains(addr, slot)
+}
+
 // DeleteSuicides flags the suicided objects for deletion so that it
 // won't be referenced again when called / queried up on.
 //
@@ -805,6 +1049,7 @@ func (s *StateDB) clearJournalAndRefund() {
 	s.journal = nil
 	s.validRevisions = s.validRevisions[:0]
 	s.refund = 0
+	s.stakingCandidates = nil
 }
 
 // Commit writes the state to the underlying in-memory trie database.
@@ -873,6 +1118,51 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (root common.Hash, err error)
 		s.bannedSetDirty = false
 	}
 
+	if s.jailSetDirty {
+		s.commitJailSet()
+		s.jailSetDirty = false
+	}
+
+	if s.missedBlocksDirty {
+		s.commitMissedBlocks()
+		s.missedBlocksDirty = false
+	}
+
+	if s.redelegationsDirty {
+		s.commitPendingRedelegations()
+		s.redelegationsDirty = false
+	}
+
+	if s.autoCompoundSetDirty {
+		s.commitAutoCompoundSet()
+		s.autoCompoundSetDirty = false
+	}
+
+	if s.deliveredMessagesDirty {
+		s.commitDeliveredMessages()
+		s.deliveredMessagesDirty = false
+	}
+
+	if s.sideChainAnchorsDirty {
+		s.commitSideChainAnchors()
+		s.sideChainAnchorsDirty = false
+	}
+
+	if s.runningSideChainsDirty {
+		s.commitRunningSideChains()
+		s.runningSideChainsDirty = false
+	}
+
+	if s.candidateDescriptionsDirty {
+		s.commitCandidateDescriptions()
+		s.candidateDescriptionsDirty = false
+	}
+
+	if s.blockRewardVotesDirty {
+		s.commitBlockRewardVotes()
+		s.blockRewardVotesDirty = false
+	}
+
 	// Commit Reward Per Block to the trie
 	if s.sideChainRewardPerBlockDirty {
 		s.commitSideChainRewardPerBlock()
@@ -0,0 +1,66 @@
+package state
+
+import (
+	"encoding/binary"
+
+	"github.com/neatio-net/neatio/utilities/common"
+)
+
+// ----- side chain anchors
+
+// sideChainAnchor is a side chain block saved to the main chain, the receipt
+// root is kept for the receipts proved against it
+type sideChainAnchor struct {
+	Hash        common.Hash
+	ReceiptHash common.Hash
+}
+
+// SetSideChainAnchor records the hash and the receipt root of the side chain
+// block saved to the main chain, contracts verify the side chain headers and
+// receipts against it
+func (self *StateDB) SetSideChainAnchor(chainId string, number uint64, hash, receiptHash common.Hash) {
+	self.sideChainAnchors[string(sideChainAnchorKey(chainId, number))] = sideChainAnchor{Hash: hash, ReceiptHash: receiptHash}
+	self.sideChainAnchorsDirty = true
+}
+
+func (self *StateDB) GetSideChainAnchor(chainId string, number uint64) common.Hash {
+	return self.getSideChainAnchor(chainId, number).Hash
+}
+
+func (self *StateDB) GetSideChainReceiptHash(chainId string, number uint64) common.Hash {
+	return self.getSideChainAnchor(chainId, number).ReceiptHash
+}
+
+func (self *StateDB) getSideChainAnchor(chainId string, number uint64) sideChainAnchor {
+	key := sideChainAnchorKey(chainId, number)
+	if anchor, ok := self.sideChainAnchors[string(key)]; ok {
+		return anchor
+	}
+	// Try to get from Trie
+	enc, err := self.trie.TryGet(key)
+	if err != nil {
+		self.setError(err)
+		return sideChainAnchor{}
+	}
+	if len(enc) != 2*common.HashLength {
+		return sideChainAnchor{}
+	}
+	return sideChainAnchor{Hash: common.BytesToHash(enc[:common.HashLength]), ReceiptHash: common.BytesToHash(enc[common.HashLength:])}
+}
+
+func (self *StateDB) commitSideChainAnchors() {
+	for key, anchor := range self.sideChainAnchors {
+		self.setError(self.trie.TryUpdate([]byte(key), append(anchor.Hash.Bytes(), anchor.ReceiptHash.Bytes()...)))
+	}
+}
+
+// Store the Side Chain Anchors
+
+var sideChainAnchorPrefix = []byte("SideChainAnchor")
+
+func sideChainAnchorKey(chainId string, number uint64) []byte {
+	key := make([]byte, 0, len(sideChainAnchorPrefix)+len(chainId)+8)
+	key = append(key, sideChainAnchorPrefix...)
+	key = append(key, chainId...)
+	return binary.BigEndian.AppendUint64(key, number)
+}
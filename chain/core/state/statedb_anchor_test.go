@@ -0,0 +1,34 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/neatio-net/neatio/neatdb/memorydb"
+	"github.com/neatio-net/neatio/utilities/common"
+)
+
+func TestSideChainAnchors(t *testing.T) {
+	sdb := NewDatabase(memorydb.New())
+	state, _ := New(common.Hash{}, sdb)
+
+	hash, receiptHash := common.BytesToHash([]byte{1}), common.BytesToHash([]byte{2})
+	state.SetSideChainAnchor("sidetest", 10, hash, receiptHash)
+	if state.GetSideChainAnchor("sidetest", 10) != hash {
+		t.Fatalf("anchor not set before commit")
+	}
+	root, err := state.Commit(false)
+	if err != nil {
+		t.Fatalf("commit error %v", err)
+	}
+
+	state, _ = New(root, sdb)
+	if state.GetSideChainAnchor("sidetest", 10) != hash {
+		t.Errorf("anchor mismatch after commit")
+	}
+	if state.GetSideChainReceiptHash("sidetest", 10) != receiptHash {
+		t.Errorf("receipt root mismatch after commit")
+	}
+	if state.GetSideChainAnchor("sidetest", 11) != (common.Hash{}) || state.GetSideChainAnchor("sidetes", 10) != (common.Hash{}) {
+		t.Errorf("unexpected anchor of another block")
+	}
+}
@@ -4,12 +4,12 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/neatio-net/neatio/chain/core/rawdb"
+	"github.com/neatio-net/neatio/neatdb/memorydb"
 	"github.com/neatio-net/neatio/utilities/common"
 )
 
 func TestUpdateCandidateSet(t *testing.T) {
-	db := rawdb.NewMemoryDatabase()
+	db := memorydb.New()
 	state, _ := New(common.Hash{}, NewDatabase(db))
 
 	for i := byte(0); i < 255; i++ {
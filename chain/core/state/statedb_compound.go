@@ -0,0 +1,88 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/rlp"
+)
+
+// ----- auto compound
+
+// AutoCompound flags the delegation of Delegator to Candidate, the rewards of
+// the delegation are delegated again to the candidate when entering the next epoch
+type AutoCompound struct {
+	Delegator common.Address
+	Candidate common.Address
+}
+
+// SetAutoCompound turns the auto compound of the delegation on or off
+func (self *StateDB) SetAutoCompound(delegator, candidate common.Address, enable bool) {
+	set := self.GetAutoCompoundSet()
+	for i, c := range set {
+		if c.Delegator == delegator && c.Candidate == candidate {
+			if !enable {
+				self.autoCompoundSet = append(set[:i:i], set[i+1:]...)
+				self.autoCompoundSetDirty = true
+			}
+			return
+		}
+	}
+	if enable {
+		self.autoCompoundSet = append(set, &AutoCompound{Delegator: delegator, Candidate: candidate})
+		self.autoCompoundSetDirty = true
+	}
+}
+
+func (self *StateDB) IsAutoCompound(delegator, candidate common.Address) bool {
+	for _, c := range self.GetAutoCompoundSet() {
+		if c.Delegator == delegator && c.Candidate == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAutoCompoundByUser returns the candidates the delegator compounds the rewards to
+func (self *StateDB) GetAutoCompoundByUser(delegator common.Address) []common.Address {
+	candidates := make([]common.Address, 0)
+	for _, c := range self.GetAutoCompoundSet() {
+		if c.Delegator == delegator {
+			candidates = append(candidates, c.Candidate)
+		}
+	}
+	return candidates
+}
+
+func (self *StateDB) GetAutoCompoundSet() []*AutoCompound {
+	if len(self.autoCompoundSet) != 0 || self.autoCompoundSetDirty {
+		return self.autoCompoundSet
+	}
+	// Try to get from Trie
+	enc, err := self.trie.TryGet(autoCompoundSetKey)
+	if err != nil {
+		self.setError(err)
+		return nil
+	}
+	var value []*AutoCompound
+	if len(enc) > 0 {
+		err := rlp.DecodeBytes(enc, &value)
+		if err != nil {
+			self.setError(err)
+		}
+		self.autoCompoundSet = value
+	}
+	return value
+}
+
+func (self *StateDB) commitAutoCompoundSet() {
+	data, err := rlp.EncodeToBytes(self.autoCompoundSet)
+	if err != nil {
+		panic(fmt.Errorf("can't encode auto compound set : %v", err))
+	}
+	self.setError(self.trie.TryUpdate(autoCompoundSetKey, data))
+}
+
+// Store the Auto Compound Set
+
+var autoCompoundSetKey = []byte("AutoCompoundSet")
@@ -0,0 +1,37 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/neatio-net/neatio/neatdb/memorydb"
+	"github.com/neatio-net/neatio/utilities/common"
+)
+
+func TestAutoCompoundSet(t *testing.T) {
+	sdb := NewDatabase(memorydb.New())
+	state, _ := New(common.Hash{}, sdb)
+
+	user := common.BytesToAddress([]byte{1})
+	candA := common.BytesToAddress([]byte{2})
+	candB := common.BytesToAddress([]byte{3})
+
+	state.SetAutoCompound(user, candA, true)
+	state.SetAutoCompound(user, candA, true)
+	state.SetAutoCompound(user, candB, true)
+	root, err := state.Commit(false)
+	if err != nil {
+		t.Fatalf("commit error %v", err)
+	}
+
+	state, _ = New(root, sdb)
+	if got := state.GetAutoCompoundByUser(user); len(got) != 2 {
+		t.Fatalf("expected 2 flagged candidates, got %v", got)
+	}
+
+	state.SetAutoCompound(user, candA, false)
+	root, _ = state.Commit(false)
+	state, _ = New(root, sdb)
+	if state.IsAutoCompound(user, candA) || !state.IsAutoCompound(user, candB) {
+		t.Errorf("auto compound flags mismatch, got %v", state.GetAutoCompoundByUser(user))
+	}
+}
@@ -391,6 +391,10 @@ func (self *StateDB) ClearCommission(addr common.Address) {
 // MarkDelegateAddressRefund adds the specified object to the dirty map to avoid
 func (self *StateDB) MarkDelegateAddressRefund(addr common.Address) {
 	if _, exist := self.GetDelegateAddressRefundSet()[addr]; !exist {
+		self.journal = append(self.journal, delegateRefundSetChange{
+			account:   &addr,
+			prevDirty: self.delegateRefundSetDirty,
+		})
 		self.delegateRefundSet[addr] = struct{}{}
 		self.delegateRefundSetDirty = true
 	}
@@ -431,6 +435,26 @@ func (self *StateDB) ClearDelegateRefundSet() {
 	self.delegateRefundSetDirty = false
 }
 
+// ----- Staking Candidates
+
+// TouchStakingCandidate records the candidate whose voting power is changed by
+// the staking precompile in the current transaction
+func (self *StateDB) TouchStakingCandidate(addr common.Address) {
+	for _, c := range self.stakingCandidates {
+		if c == addr {
+			return
+		}
+	}
+	self.journal = append(self.journal, stakingCandidateChange{})
+	self.stakingCandidates = append(self.stakingCandidates, addr)
+}
+
+// StakingCandidates returns the candidates touched in the current transaction
+// and not reverted
+func (self *StateDB) StakingCandidates() []common.Address {
+	return self.stakingCandidates
+}
+
 // Store the Delegate Refund Set
 
 var refundSetKey = []byte("DelegateRefundSet")
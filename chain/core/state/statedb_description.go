@@ -0,0 +1,68 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/rlp"
+)
+
+// ----- candidate description
+
+// CandidateDescription is what the candidate tells about itself with EditValidator
+type CandidateDescription struct {
+	Moniker  string
+	Website  string
+	Identity string
+	Details  string
+}
+
+// SetCandidateDescription replaces the description of the candidate, a nil
+// description removes it
+func (self *StateDB) SetCandidateDescription(addr common.Address, desc *CandidateDescription) {
+	self.candidateDescriptions[addr] = desc
+	self.candidateDescriptionsDirty = true
+}
+
+func (self *StateDB) GetCandidateDescription(addr common.Address) *CandidateDescription {
+	if desc, ok := self.candidateDescriptions[addr]; ok {
+		return desc
+	}
+	// Try to get from Trie
+	enc, err := self.trie.TryGet(candidateDescriptionKey(addr))
+	if err != nil {
+		self.setError(err)
+		return nil
+	}
+	if len(enc) == 0 {
+		return nil
+	}
+	var desc CandidateDescription
+	if err := rlp.DecodeBytes(enc, &desc); err != nil {
+		self.setError(err)
+		return nil
+	}
+	return &desc
+}
+
+func (self *StateDB) commitCandidateDescriptions() {
+	for addr, desc := range self.candidateDescriptions {
+		if desc == nil {
+			self.setError(self.trie.TryDelete(candidateDescriptionKey(addr)))
+			continue
+		}
+		data, err := rlp.EncodeToBytes(desc)
+		if err != nil {
+			panic(fmt.Errorf("can't encode candidate description : %v", err))
+		}
+		self.setError(self.trie.TryUpdate(candidateDescriptionKey(addr), data))
+	}
+}
+
+// Store the Candidate Descriptions
+
+var candidateDescriptionPrefix = []byte("CandidateDescription")
+
+func candidateDescriptionKey(addr common.Address) []byte {
+	return append(candidateDescriptionPrefix, addr.Bytes()...)
+}
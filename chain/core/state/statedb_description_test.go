@@ -0,0 +1,46 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/neatio-net/neatio/neatdb/memorydb"
+	"github.com/neatio-net/neatio/utilities/common"
+)
+
+func TestCandidateDescription(t *testing.T) {
+	sdb := NewDatabase(memorydb.New())
+	state, _ := New(common.Hash{}, sdb)
+
+	addr := common.BytesToAddress([]byte{1})
+	desc := &CandidateDescription{Moniker: "neat", Website: "https://neatio.net", Identity: "id", Details: "details"}
+
+	state.MarkAddressCandidate(addr)
+	state.SetCandidateDescription(addr, desc)
+	root, err := state.Commit(false)
+	if err != nil {
+		t.Fatalf("commit error %v", err)
+	}
+
+	state, _ = New(root, sdb)
+	if got := state.GetCandidateDescription(addr); got == nil || *got != *desc {
+		t.Errorf("description mismatch after commit, got %v want %v", got, desc)
+	}
+	if _, ok := state.GetCandidateSet()[addr]; !ok {
+		t.Errorf("candidate not in the candidate set")
+	}
+
+	state.ClearCandidateSetByAddress(addr)
+	state.SetCandidateDescription(addr, nil)
+	root, err = state.Commit(false)
+	if err != nil {
+		t.Fatalf("commit error %v", err)
+	}
+
+	state, _ = New(root, sdb)
+	if got := state.GetCandidateDescription(addr); got != nil {
+		t.Errorf("description kept after unregister, got %v", got)
+	}
+	if _, ok := state.GetCandidateSet()[addr]; ok {
+		t.Errorf("candidate still in the candidate set")
+	}
+}
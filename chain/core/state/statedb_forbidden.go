@@ -15,13 +15,16 @@ import (
 // MarkAddressBanned adds the specified object to the dirty map
 func (self *StateDB) MarkAddressBanned(addr common.Address) {
 	if _, exist := self.GetBannedSet()[addr]; !exist {
+		if self.bannedSet == nil {
+			self.bannedSet = make(BannedSet)
+		}
 		self.bannedSet[addr] = struct{}{}
 		self.bannedSetDirty = true
 	}
 }
 
 func (self *StateDB) GetBannedSet() BannedSet {
-	if len(self.bannedSet) != 0 {
+	if len(self.bannedSet) != 0 || self.bannedSetDirty {
 		return self.bannedSet
 	}
 	// Try to get from Trie
@@ -50,6 +53,8 @@ func (self *StateDB) commitBannedSet() {
 }
 
 func (self *StateDB) ClearBannedSetByAddress(addr common.Address) {
+	// load the set first, otherwise the other banned addresses are lost on commit
+	self.GetBannedSet()
 	delete(self.bannedSet, addr)
 	self.bannedSetDirty = true
 }
@@ -83,3 +88,98 @@ func (set *BannedSet) DecodeRLP(s *rlp.Stream) error {
 	*set = bannedSet
 	return nil
 }
+
+// ----- jail Set
+
+// MarkAddressJailed records the height the address was banned at, a tombstoned
+// address stays tombstoned and can never be unbanned
+func (self *StateDB) MarkAddressJailed(addr common.Address, height uint64, tombstone bool) {
+	if self.GetJailSet() == nil {
+		self.jailSet = make(JailSet)
+	}
+	entry := self.jailSet[addr]
+	entry.Height = height
+	entry.Tombstoned = entry.Tombstoned || tombstone
+	self.jailSet[addr] = entry
+	self.jailSetDirty = true
+}
+
+// GetJailHeight returns the height the address was last banned at
+func (self *StateDB) GetJailHeight(addr common.Address) uint64 {
+	return self.GetJailSet()[addr].Height
+}
+
+// IsTombstoned reports whether the address was banned for double signing
+func (self *StateDB) IsTombstoned(addr common.Address) bool {
+	return self.GetJailSet()[addr].Tombstoned
+}
+
+func (self *StateDB) GetJailSet() JailSet {
+	if len(self.jailSet) != 0 || self.jailSetDirty {
+		return self.jailSet
+	}
+	// Try to get from Trie
+	enc, err := self.trie.TryGet(jailSetKey)
+	if err != nil {
+		self.setError(err)
+		return nil
+	}
+	var value JailSet
+	if len(enc) > 0 {
+		err := rlp.DecodeBytes(enc, &value)
+		if err != nil {
+			self.setError(err)
+		}
+		self.jailSet = value
+	}
+	return value
+}
+
+func (self *StateDB) commitJailSet() {
+	data, err := rlp.EncodeToBytes(self.jailSet)
+	if err != nil {
+		panic(fmt.Errorf("can't encode jail set : %v", err))
+	}
+	self.setError(self.trie.TryUpdate(jailSetKey, data))
+}
+
+// Store the Jail Set
+
+var jailSetKey = []byte("JailSet")
+
+type JailEntry struct {
+	Height     uint64
+	Tombstoned bool
+}
+
+type JailSet map[common.Address]JailEntry
+
+type jailSetEntry struct {
+	Address    common.Address
+	Height     uint64
+	Tombstoned bool
+}
+
+func (set JailSet) EncodeRLP(w io.Writer) error {
+	var list []jailSetEntry
+	for addr, entry := range set {
+		list = append(list, jailSetEntry{addr, entry.Height, entry.Tombstoned})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(list[i].Address.Bytes(), list[j].Address.Bytes()) == 1
+	})
+	return rlp.Encode(w, list)
+}
+
+func (set *JailSet) DecodeRLP(s *rlp.Stream) error {
+	var list []jailSetEntry
+	if err := s.Decode(&list); err != nil {
+		return err
+	}
+	jailSet := make(JailSet, len(list))
+	for _, entry := range list {
+		jailSet[entry.Address] = JailEntry{entry.Height, entry.Tombstoned}
+	}
+	*set = jailSet
+	return nil
+}
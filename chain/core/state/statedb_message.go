@@ -0,0 +1,41 @@
+package state
+
+import (
+	"github.com/neatio-net/neatio/utilities/common"
+)
+
+// ----- cross chain messages
+
+// MarkCrossChainMessageDelivered records the message as delivered on this chain,
+// the same message can not be delivered again
+func (self *StateDB) MarkCrossChainMessageDelivered(id common.Hash) {
+	self.deliveredMessages[id] = struct{}{}
+	self.deliveredMessagesDirty = true
+}
+
+func (self *StateDB) IsCrossChainMessageDelivered(id common.Hash) bool {
+	if _, ok := self.deliveredMessages[id]; ok {
+		return true
+	}
+	// Try to get from Trie
+	enc, err := self.trie.TryGet(deliveredMessageKey(id))
+	if err != nil {
+		self.setError(err)
+		return false
+	}
+	return len(enc) > 0
+}
+
+func (self *StateDB) commitDeliveredMessages() {
+	for id := range self.deliveredMessages {
+		self.setError(self.trie.TryUpdate(deliveredMessageKey(id), []byte{1}))
+	}
+}
+
+// Store the Delivered Cross Chain Messages
+
+var deliveredMessagePrefix = []byte("CrossChainMessage")
+
+func deliveredMessageKey(id common.Hash) []byte {
+	return append(deliveredMessagePrefix, id.Bytes()...)
+}
@@ -0,0 +1,30 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/neatio-net/neatio/neatdb/memorydb"
+	"github.com/neatio-net/neatio/utilities/common"
+)
+
+func TestDeliveredCrossChainMessages(t *testing.T) {
+	sdb := NewDatabase(memorydb.New())
+	state, _ := New(common.Hash{}, sdb)
+
+	id := common.BytesToHash([]byte{1})
+	other := common.BytesToHash([]byte{2})
+
+	state.MarkCrossChainMessageDelivered(id)
+	if !state.IsCrossChainMessageDelivered(id) {
+		t.Fatalf("message not delivered before commit")
+	}
+	root, err := state.Commit(false)
+	if err != nil {
+		t.Fatalf("commit error %v", err)
+	}
+
+	state, _ = New(root, sdb)
+	if !state.IsCrossChainMessageDelivered(id) || state.IsCrossChainMessageDelivered(other) {
+		t.Errorf("delivered messages mismatch after commit")
+	}
+}
@@ -0,0 +1,99 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/rlp"
+)
+
+// ----- missed blocks
+
+// GetMissedBlocks returns how many commits the validator missed in the current epoch
+func (self *StateDB) GetMissedBlocks(addr common.Address) uint64 {
+	return self.GetMissedBlocksSet()[addr]
+}
+
+// MarkMissedBlock increases the missed commit counter of the validator and returns the new value
+func (self *StateDB) MarkMissedBlock(addr common.Address) uint64 {
+	if self.GetMissedBlocksSet() == nil {
+		self.missedBlocks = make(MissedBlocksSet)
+	}
+	self.missedBlocks[addr]++
+	self.missedBlocksDirty = true
+	return self.missedBlocks[addr]
+}
+
+func (self *StateDB) GetMissedBlocksSet() MissedBlocksSet {
+	if len(self.missedBlocks) != 0 || self.missedBlocksDirty {
+		return self.missedBlocks
+	}
+	// Try to get from Trie
+	enc, err := self.trie.TryGet(missedBlocksKey)
+	if err != nil {
+		self.setError(err)
+		return nil
+	}
+	var value MissedBlocksSet
+	if len(enc) > 0 {
+		err := rlp.DecodeBytes(enc, &value)
+		if err != nil {
+			self.setError(err)
+		}
+		self.missedBlocks = value
+	}
+	return value
+}
+
+func (self *StateDB) commitMissedBlocks() {
+	data, err := rlp.EncodeToBytes(self.missedBlocks)
+	if err != nil {
+		panic(fmt.Errorf("can't encode missed blocks : %v", err))
+	}
+	self.setError(self.trie.TryUpdate(missedBlocksKey, data))
+}
+
+// ClearMissedBlocks resets the counters of all validators, called when entering a new epoch
+func (self *StateDB) ClearMissedBlocks() {
+	self.setError(self.trie.TryDelete(missedBlocksKey))
+	self.missedBlocks = make(MissedBlocksSet)
+	self.missedBlocksDirty = false
+}
+
+// Store the Missed Blocks Set
+
+var missedBlocksKey = []byte("MissedBlocks")
+
+type MissedBlocksSet map[common.Address]uint64
+
+type missedBlocksEntry struct {
+	Address common.Address
+	Count   uint64
+}
+
+func (set MissedBlocksSet) EncodeRLP(w io.Writer) error {
+	var list []missedBlocksEntry
+	for addr, count := range set {
+		list = append(list, missedBlocksEntry{addr, count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(list[i].Address.Bytes(), list[j].Address.Bytes()) == 1
+	})
+	return rlp.Encode(w, list)
+}
+
+func (set *MissedBlocksSet) DecodeRLP(s *rlp.Stream) error {
+	var list []missedBlocksEntry
+	if err := s.Decode(&list); err != nil {
+		return err
+	}
+	missedBlocks := make(MissedBlocksSet, len(list))
+	for _, entry := range list {
+		missedBlocks[entry.Address] = entry.Count
+	}
+	*set = missedBlocks
+	return nil
+}
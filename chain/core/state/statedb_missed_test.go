@@ -0,0 +1,106 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/neatio-net/neatio/neatdb/memorydb"
+	"github.com/neatio-net/neatio/utilities/common"
+)
+
+func TestMissedBlocksAndBannedSet(t *testing.T) {
+	sdb := NewDatabase(memorydb.New())
+	state, _ := New(common.Hash{}, sdb)
+
+	addrA := common.BytesToAddress([]byte{1})
+	addrB := common.BytesToAddress([]byte{2})
+
+	state.MarkMissedBlock(addrA)
+	state.MarkMissedBlock(addrA)
+	state.MarkMissedBlock(addrB)
+	state.MarkAddressBanned(addrA)
+	state.MarkAddressBanned(addrB)
+
+	root, err := state.Commit(false)
+	if err != nil {
+		t.Fatalf("commit error %v", err)
+	}
+
+	state, _ = New(root, sdb)
+	if got := state.GetMissedBlocks(addrA); got != 2 {
+		t.Errorf("missed blocks of %x, got %v want 2", addrA, got)
+	}
+	if got := state.GetMissedBlocks(addrB); got != 1 {
+		t.Errorf("missed blocks of %x, got %v want 1", addrB, got)
+	}
+
+	// unban one address without loading the set first
+	state.ClearBannedSetByAddress(addrA)
+	state.ClearMissedBlocks()
+	root, _ = state.Commit(false)
+
+	state, _ = New(root, sdb)
+	if _, banned := state.GetBannedSet()[addrA]; banned {
+		t.Errorf("%x should be unbanned", addrA)
+	}
+	if _, banned := state.GetBannedSet()[addrB]; !banned {
+		t.Errorf("%x should still be banned", addrB)
+	}
+	if got := state.GetMissedBlocks(addrA); got != 0 {
+		t.Errorf("missed blocks not cleared, got %v", got)
+	}
+
+	// removing the last banned address must stick
+	state.ClearBannedSetByAddress(addrB)
+	if len(state.GetBannedSet()) != 0 {
+		t.Errorf("banned set reloaded from trie after clearing the last address")
+	}
+}
+
+func TestClearEmptyMissedBlocks(t *testing.T) {
+	sdb := NewDatabase(memorydb.New())
+	state, _ := New(common.Hash{}, sdb)
+
+	state.AddBalance(common.BytesToAddress([]byte{1}), common.Big1)
+	root, _ := state.Commit(false)
+
+	state, _ = New(root, sdb)
+	state.ClearMissedBlocks()
+	if got, _ := state.Commit(false); got != root {
+		t.Errorf("clearing an empty missed blocks set changed the root, got %x want %x", got, root)
+	}
+}
+
+func TestJailSet(t *testing.T) {
+	sdb := NewDatabase(memorydb.New())
+	state, _ := New(common.Hash{}, sdb)
+
+	addrA := common.BytesToAddress([]byte{1})
+	addrB := common.BytesToAddress([]byte{2})
+
+	state.MarkAddressJailed(addrA, 100, false)
+	state.MarkAddressJailed(addrB, 200, true)
+	root, _ := state.Commit(false)
+
+	state, _ = New(root, sdb)
+	if got := state.GetJailHeight(addrA); got != 100 {
+		t.Errorf("jail height of %x, got %v want 100", addrA, got)
+	}
+	if state.IsTombstoned(addrA) {
+		t.Errorf("%x should not be tombstoned", addrA)
+	}
+	if !state.IsTombstoned(addrB) {
+		t.Errorf("%x should be tombstoned", addrB)
+	}
+
+	// a later downtime ban keeps the tombstone
+	state.MarkAddressJailed(addrB, 300, false)
+	root, _ = state.Commit(false)
+
+	state, _ = New(root, sdb)
+	if got := state.GetJailHeight(addrB); got != 300 {
+		t.Errorf("jail height of %x, got %v want 300", addrB, got)
+	}
+	if !state.IsTombstoned(addrB) {
+		t.Errorf("tombstone of %x lost", addrB)
+	}
+}
@@ -0,0 +1,119 @@
+package state
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/rlp"
+)
+
+// ----- pending redelegations
+
+// Redelegation moves the bonded delegation of Delegator from candidate From to
+// candidate To, it is applied when entering the next epoch
+type Redelegation struct {
+	Delegator common.Address
+	From      common.Address
+	To        common.Address
+	Amount    *big.Int
+}
+
+// AddPendingRedelegation records a redelegation, the one of the same delegator
+// between the same candidates is increased instead
+func (self *StateDB) AddPendingRedelegation(delegator, from, to common.Address, amount *big.Int) {
+	redelegations := self.GetPendingRedelegations()
+	for _, r := range redelegations {
+		if r.Delegator == delegator && r.From == from && r.To == to {
+			r.Amount = new(big.Int).Add(r.Amount, amount)
+			self.redelegationsDirty = true
+			return
+		}
+	}
+	self.redelegations = append(redelegations, &Redelegation{
+		Delegator: delegator,
+		From:      from,
+		To:        to,
+		Amount:    new(big.Int).Set(amount),
+	})
+	self.redelegationsDirty = true
+}
+
+func (self *StateDB) GetPendingRedelegations() []*Redelegation {
+	if len(self.redelegations) != 0 || self.redelegationsDirty {
+		return self.redelegations
+	}
+	// Try to get from Trie
+	enc, err := self.trie.TryGet(redelegationsKey)
+	if err != nil {
+		self.setError(err)
+		return nil
+	}
+	var value []*Redelegation
+	if len(enc) > 0 {
+		err := rlp.DecodeBytes(enc, &value)
+		if err != nil {
+			self.setError(err)
+		}
+		self.redelegations = value
+	}
+	return value
+}
+
+// GetPendingRedelegationsByUser returns the number of pending redelegations of the delegator
+func (self *StateDB) GetPendingRedelegationsByUser(user common.Address) int {
+	count := 0
+	for _, r := range self.GetPendingRedelegations() {
+		if r.Delegator == user {
+			count++
+		}
+	}
+	return count
+}
+
+// GetPendingRedelegateOutByUser returns the delegation of the user leaving the candidate at the next epoch
+func (self *StateDB) GetPendingRedelegateOutByUser(candidate, user common.Address) *big.Int {
+	return self.sumPendingRedelegations(func(r *Redelegation) bool { return r.From == candidate && r.Delegator == user })
+}
+
+// GetPendingRedelegateInByUser returns the delegation of the user coming to the candidate at the next epoch
+func (self *StateDB) GetPendingRedelegateInByUser(candidate, user common.Address) *big.Int {
+	return self.sumPendingRedelegations(func(r *Redelegation) bool { return r.To == candidate && r.Delegator == user })
+}
+
+func (self *StateDB) GetTotalPendingRedelegateOut(candidate common.Address) *big.Int {
+	return self.sumPendingRedelegations(func(r *Redelegation) bool { return r.From == candidate })
+}
+
+func (self *StateDB) GetTotalPendingRedelegateIn(candidate common.Address) *big.Int {
+	return self.sumPendingRedelegations(func(r *Redelegation) bool { return r.To == candidate })
+}
+
+func (self *StateDB) sumPendingRedelegations(match func(r *Redelegation) bool) *big.Int {
+	total := new(big.Int)
+	for _, r := range self.GetPendingRedelegations() {
+		if match(r) {
+			total.Add(total, r.Amount)
+		}
+	}
+	return total
+}
+
+func (self *StateDB) commitPendingRedelegations() {
+	data, err := rlp.EncodeToBytes(self.redelegations)
+	if err != nil {
+		panic(fmt.Errorf("can't encode pending redelegations : %v", err))
+	}
+	self.setError(self.trie.TryUpdate(redelegationsKey, data))
+}
+
+// ClearPendingRedelegations is called once the redelegations are applied when entering a new epoch
+func (self *StateDB) ClearPendingRedelegations() {
+	self.setError(self.trie.TryDelete(redelegationsKey))
+	self.redelegations = nil
+	self.redelegationsDirty = false
+}
+
+// Store the Pending Redelegations
+
+var redelegationsKey = []byte("PendingRedelegations")
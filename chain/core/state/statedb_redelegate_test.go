@@ -0,0 +1,49 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/neatio-net/neatio/neatdb/memorydb"
+	"github.com/neatio-net/neatio/utilities/common"
+)
+
+func TestPendingRedelegations(t *testing.T) {
+	sdb := NewDatabase(memorydb.New())
+	state, _ := New(common.Hash{}, sdb)
+
+	user := common.BytesToAddress([]byte{1})
+	candA := common.BytesToAddress([]byte{2})
+	candB := common.BytesToAddress([]byte{3})
+
+	state.AddPendingRedelegation(user, candA, candB, big.NewInt(10))
+	state.AddPendingRedelegation(user, candA, candB, big.NewInt(5))
+	root, err := state.Commit(false)
+	if err != nil {
+		t.Fatalf("commit error %v", err)
+	}
+
+	state, _ = New(root, sdb)
+	if n := state.GetPendingRedelegationsByUser(user); n != 1 {
+		t.Fatalf("redelegations between the same candidates should be merged, got %v", n)
+	}
+	if out := state.GetPendingRedelegateOutByUser(candA, user); out.Cmp(big.NewInt(15)) != 0 {
+		t.Errorf("redelegate out of %x, got %v want 15", candA, out)
+	}
+	if in := state.GetTotalPendingRedelegateIn(candB); in.Cmp(big.NewInt(15)) != 0 {
+		t.Errorf("redelegate in of %x, got %v want 15", candB, in)
+	}
+
+	state.ClearPendingRedelegations()
+	root, _ = state.Commit(false)
+	state, _ = New(root, sdb)
+	if len(state.GetPendingRedelegations()) != 0 {
+		t.Errorf("redelegations not cleared")
+	}
+
+	// an epoch without redelegations keeps the root
+	state.ClearPendingRedelegations()
+	if got, _ := state.Commit(false); got != root {
+		t.Errorf("clearing no redelegations changed the root, got %x want %x", got, root)
+	}
+}
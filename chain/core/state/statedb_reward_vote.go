@@ -0,0 +1,70 @@
+package state
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/rlp"
+)
+
+// ----- side chain block reward votes
+
+// BlockRewardVote is the side chain block reward the validator votes for
+type BlockRewardVote struct {
+	Validator common.Address
+	Reward    *big.Int
+}
+
+// SetBlockRewardVote replaces the vote of the validator
+func (self *StateDB) SetBlockRewardVote(validator common.Address, reward *big.Int) {
+	votes := self.GetBlockRewardVotes()
+	for _, v := range votes {
+		if v.Validator == validator {
+			v.Reward = new(big.Int).Set(reward)
+			self.blockRewardVotesDirty = true
+			return
+		}
+	}
+	self.blockRewardVotes = append(votes, &BlockRewardVote{Validator: validator, Reward: new(big.Int).Set(reward)})
+	self.blockRewardVotesDirty = true
+}
+
+// ClearBlockRewardVotes drops the votes once the block reward is set
+func (self *StateDB) ClearBlockRewardVotes() {
+	self.blockRewardVotes = nil
+	self.blockRewardVotesDirty = true
+}
+
+func (self *StateDB) GetBlockRewardVotes() []*BlockRewardVote {
+	if len(self.blockRewardVotes) != 0 || self.blockRewardVotesDirty {
+		return self.blockRewardVotes
+	}
+	// Try to get from Trie
+	enc, err := self.trie.TryGet(blockRewardVotesKey)
+	if err != nil {
+		self.setError(err)
+		return nil
+	}
+	var value []*BlockRewardVote
+	if len(enc) > 0 {
+		err := rlp.DecodeBytes(enc, &value)
+		if err != nil {
+			self.setError(err)
+		}
+		self.blockRewardVotes = value
+	}
+	return value
+}
+
+func (self *StateDB) commitBlockRewardVotes() {
+	data, err := rlp.EncodeToBytes(self.blockRewardVotes)
+	if err != nil {
+		panic(fmt.Errorf("can't encode block reward votes : %v", err))
+	}
+	self.setError(self.trie.TryUpdate(blockRewardVotesKey, data))
+}
+
+// Store the Block Reward Votes
+
+var blockRewardVotesKey = []byte("BlockRewardVotes")
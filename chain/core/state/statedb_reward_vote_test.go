@@ -0,0 +1,51 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/neatio-net/neatio/neatdb/memorydb"
+	"github.com/neatio-net/neatio/utilities/common"
+)
+
+func TestBlockRewardVotes(t *testing.T) {
+	sdb := NewDatabase(memorydb.New())
+	state, _ := New(common.Hash{}, sdb)
+
+	val1 := common.BytesToAddress([]byte{1})
+	val2 := common.BytesToAddress([]byte{2})
+
+	state.SetBlockRewardVote(val1, big.NewInt(100))
+	state.SetBlockRewardVote(val2, big.NewInt(200))
+	state.SetBlockRewardVote(val1, big.NewInt(200))
+	root, err := state.Commit(false)
+	if err != nil {
+		t.Fatalf("commit error %v", err)
+	}
+
+	state, _ = New(root, sdb)
+	votes := state.GetBlockRewardVotes()
+	if len(votes) != 2 {
+		t.Fatalf("vote count mismatch, got %v want 2", len(votes))
+	}
+	for _, v := range votes {
+		if v.Reward.Cmp(big.NewInt(200)) != 0 {
+			t.Errorf("vote of %x mismatch, got %v want 200", v.Validator, v.Reward)
+		}
+	}
+
+	copied := state.Copy()
+	state.ClearBlockRewardVotes()
+	if len(copied.GetBlockRewardVotes()) != 2 {
+		t.Errorf("votes of the copy changed with the original")
+	}
+	root, err = state.Commit(false)
+	if err != nil {
+		t.Fatalf("commit error %v", err)
+	}
+
+	state, _ = New(root, sdb)
+	if votes := state.GetBlockRewardVotes(); len(votes) != 0 {
+		t.Errorf("votes kept after clear, got %v", votes)
+	}
+}
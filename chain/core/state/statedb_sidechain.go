@@ -0,0 +1,84 @@
+package state
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/rlp"
+)
+
+// ----- running side chains
+
+// RunningSideChain keeps the deposits locked in the chain balance of the owner
+// when the side chain launched, they are refunded once it is decommissioned
+type RunningSideChain struct {
+	ChainId  string
+	Owner    common.Address
+	EndBlock *big.Int
+	Deposits []*SideChainDeposit
+}
+
+type SideChainDeposit struct {
+	Address common.Address
+	Amount  *big.Int
+}
+
+func (self *StateDB) AddRunningSideChain(sc *RunningSideChain) {
+	self.runningSideChains = append(self.GetRunningSideChains(), sc)
+	self.runningSideChainsDirty = true
+}
+
+func (self *StateDB) GetRunningSideChains() []*RunningSideChain {
+	if len(self.runningSideChains) != 0 || self.runningSideChainsDirty {
+		return self.runningSideChains
+	}
+	// Try to get from Trie
+	enc, err := self.trie.TryGet(runningSideChainsKey)
+	if err != nil {
+		self.setError(err)
+		return nil
+	}
+	var value []*RunningSideChain
+	if len(enc) > 0 {
+		err := rlp.DecodeBytes(enc, &value)
+		if err != nil {
+			self.setError(err)
+		}
+		self.runningSideChains = value
+	}
+	return value
+}
+
+// RemoveRunningSideChain returns the removed side chain, nil if it is not running
+func (self *StateDB) RemoveRunningSideChain(chainId string) *RunningSideChain {
+	running := self.GetRunningSideChains()
+	for i, sc := range running {
+		if sc.ChainId != chainId {
+			continue
+		}
+		self.runningSideChains = append(running[:i:i], running[i+1:]...)
+		if len(self.runningSideChains) == 0 {
+			// nothing left to commit, leave no empty list in the trie
+			self.setError(self.trie.TryDelete(runningSideChainsKey))
+			self.runningSideChains = nil
+			self.runningSideChainsDirty = false
+		} else {
+			self.runningSideChainsDirty = true
+		}
+		return sc
+	}
+	return nil
+}
+
+func (self *StateDB) commitRunningSideChains() {
+	data, err := rlp.EncodeToBytes(self.runningSideChains)
+	if err != nil {
+		panic(fmt.Errorf("can't encode running side chains : %v", err))
+	}
+	self.setError(self.trie.TryUpdate(runningSideChainsKey, data))
+}
+
+// Store the Running Side Chains
+
+var runningSideChainsKey = []byte("RunningSideChains")
@@ -0,0 +1,48 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/neatio-net/neatio/neatdb/memorydb"
+	"github.com/neatio-net/neatio/utilities/common"
+)
+
+func TestRunningSideChains(t *testing.T) {
+	sdb := NewDatabase(memorydb.New())
+	state, _ := New(common.Hash{}, sdb)
+	emptyRoot, _ := state.Commit(false)
+
+	owner := common.BytesToAddress([]byte{1})
+	validator := common.BytesToAddress([]byte{2})
+
+	state.AddRunningSideChain(&RunningSideChain{
+		ChainId:  "side_0",
+		Owner:    owner,
+		EndBlock: big.NewInt(100),
+		Deposits: []*SideChainDeposit{{Address: validator, Amount: big.NewInt(10)}},
+	})
+	root, err := state.Commit(false)
+	if err != nil {
+		t.Fatalf("commit error %v", err)
+	}
+
+	state, _ = New(root, sdb)
+	running := state.GetRunningSideChains()
+	if len(running) != 1 || running[0].ChainId != "side_0" || running[0].EndBlock.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("running side chains not restored, got %v", running)
+	}
+	if d := running[0].Deposits; len(d) != 1 || d[0].Address != validator || d[0].Amount.Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("deposits not restored, got %v", d)
+	}
+
+	if state.RemoveRunningSideChain("side_1") != nil {
+		t.Errorf("removed a side chain which is not running")
+	}
+	if sc := state.RemoveRunningSideChain("side_0"); sc == nil || sc.Owner != owner {
+		t.Fatalf("side chain not removed, got %v", sc)
+	}
+	if got, _ := state.Commit(false); got != emptyRoot {
+		t.Errorf("removing the last side chain left a value in the trie, got %x want %x", got, emptyRoot)
+	}
+}
@@ -0,0 +1,39 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/neatio-net/neatio/neatdb/memorydb"
+	"github.com/neatio-net/neatio/utilities/common"
+)
+
+func TestStakingRevert(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(memorydb.New()))
+
+	first := common.BytesToAddress([]byte{1})
+	second := common.BytesToAddress([]byte{2})
+
+	state.TouchStakingCandidate(first)
+	snapshot := state.Snapshot()
+
+	state.TouchStakingCandidate(second)
+	state.TouchStakingCandidate(first)
+	state.MarkDelegateAddressRefund(second)
+	if len(state.StakingCandidates()) != 2 || !state.delegateRefundSetDirty {
+		t.Fatalf("staking changes not applied, candidates %v", state.StakingCandidates())
+	}
+
+	state.RevertToSnapshot(snapshot)
+	if c := state.StakingCandidates(); len(c) != 1 || c[0] != first {
+		t.Errorf("staking candidates after revert, got %v", c)
+	}
+	if _, ok := state.GetDelegateAddressRefundSet()[second]; ok || state.delegateRefundSetDirty {
+		t.Errorf("refund set not reverted")
+	}
+
+	// the touches belong to the transaction
+	state.Finalise(true)
+	if c := state.StakingCandidates(); len(c) != 0 {
+		t.Errorf("staking candidates after finalise, got %v", c)
+	}
+}
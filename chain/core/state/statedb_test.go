@@ -15,14 +15,14 @@ import (
 
 	"gopkg.in/check.v1"
 
-	"github.com/neatio-net/neatio/chain/core/rawdb"
 	"github.com/neatio-net/neatio/chain/core/types"
+	"github.com/neatio-net/neatio/neatdb/memorydb"
 	"github.com/neatio-net/neatio/utilities/common"
 )
 
 func TestUpdateLeaks(t *testing.T) {
 
-	db := rawdb.NewMemoryDatabase()
+	db := memorydb.New()
 	state, _ := New(common.Hash{}, NewDatabase(db))
 
 	for i := byte(0); i < 255; i++ {
@@ -47,8 +47,8 @@ func TestUpdateLeaks(t *testing.T) {
 
 func TestIntermediateLeaks(t *testing.T) {
 
-	transDb := rawdb.NewMemoryDatabase()
-	finalDb := rawdb.NewMemoryDatabase()
+	transDb := memorydb.New()
+	finalDb := memorydb.New()
 	transState, _ := New(common.Hash{}, NewDatabase(transDb))
 	finalState, _ := New(common.Hash{}, NewDatabase(finalDb))
 
@@ -101,7 +101,7 @@ func TestIntermediateLeaks(t *testing.T) {
 
 func TestCopy(t *testing.T) {
 
-	orig, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()))
+	orig, _ := New(common.Hash{}, NewDatabase(memorydb.New()))
 
 	for i := byte(0); i < 255; i++ {
 		obj := orig.GetOrNewStateObject(common.BytesToAddress([]byte{i}))
@@ -297,7 +297,7 @@ func (test *snapshotTest) String() string {
 func (test *snapshotTest) run() bool {
 
 	var (
-		state, _     = New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()))
+		state, _     = New(common.Hash{}, NewDatabase(memorydb.New()))
 		snapshotRevs = make([]int, len(test.snapshots))
 		sindex       = 0
 	)
@@ -384,7 +384,7 @@ func (s *StateSuite) TestTouchDelete(c *check.C) {
 }
 
 func TestCopyOfCopy(t *testing.T) {
-	sdb, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()))
+	sdb, _ := New(common.Hash{}, NewDatabase(memorydb.New()))
 	addr := common.HexToAddress("aaaa")
 	sdb.SetBalance(addr, big.NewInt(42))
 
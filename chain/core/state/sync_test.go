@@ -21,9 +21,9 @@ import (
 	"math/big"
 	"testing"
 
-	"github.com/neatio-net/neatio/chain/core/rawdb"
 	"github.com/neatio-net/neatio/chain/trie"
 	"github.com/neatio-net/neatio/neatdb"
+	"github.com/neatio-net/neatio/neatdb/memorydb"
 	"github.com/neatio-net/neatio/utilities/common"
 	"github.com/neatio-net/neatio/utilities/crypto"
 )
@@ -39,7 +39,7 @@ type testAccount struct {
 // makeTestState create a sample test state to test node-wise reconstruction.
 func makeTestState() (Database, common.Hash, []*testAccount) {
 	// Create an empty state
-	diskdb := rawdb.NewMemoryDatabase()
+	diskdb := memorydb.New()
 	db := NewDatabase(diskdb)
 	state, _ := New(common.Hash{}, db)
 
@@ -126,7 +126,7 @@ func checkStateConsistency(db neatdb.Database, root common.Hash) error {
 // Tests that an empty state is not scheduled for syncing.
 func TestEmptyStateSync(t *testing.T) {
 	empty := common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
-	db := rawdb.NewMemoryDatabase()
+	db := memorydb.New()
 	if req := NewStateSync(empty, db).Missing(1); len(req) != 0 {
 		t.Errorf("content requested for empty state: %v", req)
 	}
@@ -142,7 +142,7 @@ func testIterativeStateSync(t *testing.T, batch int) {
 	srcDb, srcRoot, srcAccounts := makeTestState()
 
 	// Create a destination state and sync with the scheduler
-	dstDb := rawdb.NewMemoryDatabase()
+	dstDb := memorydb.New()
 	sched := NewStateSync(srcRoot, dstDb)
 
 	queue := append([]common.Hash{}, sched.Missing(batch)...)
@@ -174,7 +174,7 @@ func TestIterativeDelayedStateSync(t *testing.T) {
 	srcDb, srcRoot, srcAccounts := makeTestState()
 
 	// Create a destination state and sync with the scheduler
-	dstDb := rawdb.NewMemoryDatabase()
+	dstDb := memorydb.New()
 	sched := NewStateSync(srcRoot, dstDb)
 
 	queue := append([]common.Hash{}, sched.Missing(0)...)
@@ -211,7 +211,7 @@ func testIterativeRandomStateSync(t *testing.T, batch int) {
 	srcDb, srcRoot, srcAccounts := makeTestState()
 
 	// Create a destination state and sync with the scheduler
-	dstDb := rawdb.NewMemoryDatabase()
+	dstDb := memorydb.New()
 	sched := NewStateSync(srcRoot, dstDb)
 
 	queue := make(map[common.Hash]struct{})
@@ -251,7 +251,7 @@ func TestIterativeRandomDelayedStateSync(t *testing.T) {
 	srcDb, srcRoot, srcAccounts := makeTestState()
 
 	// Create a destination state and sync with the scheduler
-	dstDb := rawdb.NewMemoryDatabase()
+	dstDb := memorydb.New()
 	sched := NewStateSync(srcRoot, dstDb)
 
 	queue := make(map[common.Hash]struct{})
@@ -298,7 +298,7 @@ func TestIncompleteStateSync(t *testing.T) {
 	checkTrieConsistency(srcDb.TrieDB().DiskDB().(neatdb.Database), srcRoot)
 
 	// Create a destination state and sync with the scheduler
-	dstDb := rawdb.NewMemoryDatabase()
+	dstDb := memorydb.New()
 	sched := NewStateSync(srcRoot, dstDb)
 
 	added := []common.Hash{}
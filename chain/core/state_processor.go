@@ -65,7 +65,7 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 }
 
 func ApplyTransaction(config *params.ChainConfig, bc *BlockChain, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config) (*types.Receipt, error) {
-	msg, err := tx.AsMessage(types.MakeSigner(config, header.Number))
+	msg, err := tx.AsMessage(types.MakeSigner(config, header.Number), header.BaseFee)
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +88,7 @@ func ApplyTransaction(config *params.ChainConfig, bc *BlockChain, author *common
 	*usedGas += result.UsedGas
 
 	receipt := types.NewReceipt(root, result.Failed(), *usedGas)
+	receipt.Type = tx.Type()
 	receipt.TxHash = tx.Hash()
 	receipt.GasUsed = result.UsedGas
 
@@ -105,7 +106,7 @@ func ApplyTransactionEx(config *params.ChainConfig, bc *BlockChain, author *comm
 	header *types.Header, tx *types.Transaction, usedGas *uint64, totalUsedMoney *big.Int, cfg vm.Config, cch CrossChainHelper, mining bool) (*types.Receipt, error) {
 
 	signer := types.MakeSigner(config, header.Number)
-	msg, err := tx.AsMessage(signer)
+	msg, err := tx.AsMessage(signer, header.BaseFee)
 	if err != nil {
 		return nil, err
 	}
@@ -113,6 +114,10 @@ func ApplyTransactionEx(config *params.ChainConfig, bc *BlockChain, author *comm
 	if !neatAbi.IsNeatChainContractAddr(tx.To()) {
 
 		context := NewEVMContext(msg, header, bc, author)
+		staking := NewStakingHandler(statedb, bc)
+		if staking != nil {
+			context.Staking = staking
+		}
 
 		vmenv := vm.NewEVM(context, statedb, config, cfg)
 
@@ -121,6 +126,12 @@ func ApplyTransactionEx(config *params.ChainConfig, bc *BlockChain, author *comm
 			return nil, err
 		}
 
+		if staking != nil && !result.Failed() {
+			if err := staking.UpdateNextEpochVoteSet(tx, ops); err != nil {
+				return nil, err
+			}
+		}
+
 		var root []byte
 		if config.IsByzantium(header.Number) {
 
@@ -134,6 +145,7 @@ func ApplyTransactionEx(config *params.ChainConfig, bc *BlockChain, author *comm
 
 		receipt := types.NewReceipt(root, result.Failed(), *usedGas)
 		log.Debugf("ApplyTransactionEx，new receipt with (root,failed,*usedGas) = (%v,%v,%v)\n", root, result.Failed(), *usedGas)
+		receipt.Type = tx.Type()
 		receipt.TxHash = tx.Hash()
 
 		receipt.GasUsed = result.UsedGas
@@ -179,8 +191,13 @@ func ApplyTransactionEx(config *params.ChainConfig, bc *BlockChain, author *comm
 			}
 		}
 
+		if header.BaseFee != nil && msg.GasFeeCap().Cmp(header.BaseFee) < 0 {
+			return nil, fmt.Errorf("%w: address %v, maxFeePerGas: %s baseFee: %s", ErrFeeCapTooLow,
+				from.Hex(), msg.GasFeeCap(), header.BaseFee)
+		}
+
 		gasLimit := tx.Gas()
-		gasValue := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), tx.GasPrice())
+		gasValue := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), msg.GasPrice())
 		if statedb.GetBalance(from).Cmp(gasValue) < 0 {
 			return nil, fmt.Errorf("insufficient NIO for gas (%x). Req %v, has %v", from.Bytes()[:4], gasValue, statedb.GetBalance(from))
 		}
@@ -198,7 +215,7 @@ func ApplyTransactionEx(config *params.ChainConfig, bc *BlockChain, author *comm
 			return nil, fmt.Errorf("insufficient NIO for tx amount (%x). Req %v, has %v", from.Bytes()[:4], tx.Value(), statedb.GetBalance(from))
 		}
 
-		if applyCb := GetApplyCb(function); applyCb != nil {
+		if applyCb := GetApplyCbAt(config, header.Number, function); applyCb != nil {
 			if function.IsCrossChainType() {
 				if fn, ok := applyCb.(CrossChainApplyCb); ok {
 					cch.GetMutex().Lock()
@@ -222,13 +239,28 @@ func ApplyTransactionEx(config *params.ChainConfig, bc *BlockChain, author *comm
 			}
 		}
 
+		// the message is marked delivered even if the target fails, the
+		// failure is recorded in the receipt
+		failed := false
+		if function == neatAbi.DeliverCrossChainMessage {
+			context := NewEVMContext(msg, header, bc, author)
+			if staking := NewStakingHandler(statedb, bc); staking != nil {
+				context.Staking = staking
+			}
+			vmenv := vm.NewEVM(context, statedb, config, cfg)
+
+			callGas, callFailed := callCrossChainMessageTarget(vmenv, tx, gasLimit-gas)
+			gas += callGas
+			failed = callFailed
+		}
+
 		remainingGas := gasLimit - gas
-		remaining := new(big.Int).Mul(new(big.Int).SetUint64(remainingGas), tx.GasPrice())
+		remaining := new(big.Int).Mul(new(big.Int).SetUint64(remainingGas), msg.GasPrice())
 		statedb.AddBalance(from, remaining)
 		gp.AddGas(remainingGas)
 
 		*usedGas += gas
-		totalUsedMoney.Add(totalUsedMoney, new(big.Int).Mul(new(big.Int).SetUint64(gas), tx.GasPrice()))
+		totalUsedMoney.Add(totalUsedMoney, new(big.Int).Mul(new(big.Int).SetUint64(gas), effectiveTip(msg.GasPrice(), header.BaseFee)))
 		log.Infof("ApplyTransactionEx() 2, totalUsedMoney is %v\n", totalUsedMoney)
 
 		var root []byte
@@ -238,7 +270,8 @@ func ApplyTransactionEx(config *params.ChainConfig, bc *BlockChain, author *comm
 			root = statedb.IntermediateRoot(config.IsEIP158(header.Number)).Bytes()
 		}
 
-		receipt := types.NewReceipt(root, false, *usedGas)
+		receipt := types.NewReceipt(root, failed, *usedGas)
+		receipt.Type = tx.Type()
 		receipt.TxHash = tx.Hash()
 		receipt.GasUsed = gas
 
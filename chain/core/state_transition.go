@@ -2,9 +2,11 @@ package core
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 
+	"github.com/neatio-net/neatio/chain/core/types"
 	"github.com/neatio-net/neatio/chain/core/vm"
 	"github.com/neatio-net/neatio/params"
 	"github.com/neatio-net/neatio/utilities/common"
@@ -32,12 +34,15 @@ type Message interface {
 	To() *common.Address
 
 	GasPrice() *big.Int
+	GasFeeCap() *big.Int
+	GasTipCap() *big.Int
 	Gas() uint64
 	Value() *big.Int
 
 	Nonce() uint64
 	CheckNonce() bool
 	Data() []byte
+	AccessList() types.AccessList
 }
 
 type ExecutionResult struct {
@@ -66,7 +71,7 @@ func (result *ExecutionResult) Revert() []byte {
 	return common.CopyBytes(result.ReturnData)
 }
 
-func IntrinsicGas(data []byte, contractCreation, homestead bool) (uint64, error) {
+func IntrinsicGas(data []byte, accessList types.AccessList, contractCreation, homestead, shanghai bool) (uint64, error) {
 
 	var gas uint64
 	if contractCreation && homestead {
@@ -95,6 +100,18 @@ func IntrinsicGas(data []byte, contractCreation, homestead bool) (uint64, error)
 			return 0, vm.ErrOutOfGas
 		}
 		gas += z * params.TxDataZeroGas
+
+		if contractCreation && shanghai {
+			lenWords := toWordSize(uint64(len(data)))
+			if (math.MaxUint64-gas)/params.InitCodeWordGas < lenWords {
+				return 0, vm.ErrOutOfGas
+			}
+			gas += lenWords * params.InitCodeWordGas
+		}
+	}
+	if accessList != nil {
+		gas += uint64(len(accessList)) * params.TxAccessListAddressGas
+		gas += uint64(accessList.StorageKeys()) * params.TxAccessListStorageKeyGas
 	}
 	return gas, nil
 }
@@ -154,7 +171,11 @@ func (st *StateTransition) buyGas() error {
 		sender = st.from()
 	)
 	mgval := new(big.Int).Mul(new(big.Int).SetUint64(st.msg.Gas()), st.gasPrice)
-	if state.GetBalance(sender.Address()).Cmp(mgval) < 0 {
+	balanceCheck := mgval
+	if feeCap := st.msg.GasFeeCap(); feeCap != nil {
+		balanceCheck = new(big.Int).Mul(new(big.Int).SetUint64(st.msg.Gas()), feeCap)
+	}
+	if state.GetBalance(sender.Address()).Cmp(balanceCheck) < 0 {
 		return errInsufficientBalanceForGas
 	}
 	if err := st.gp.SubGas(st.msg.Gas()); err != nil {
@@ -179,6 +200,17 @@ func (st *StateTransition) preCheck() error {
 			return ErrNonceTooLow
 		}
 	}
+	// Skip the fee checks when the base fee is disabled for calls
+	if baseFee := st.evm.Context.BaseFee; baseFee != nil && !st.evm.VMConfig().NoBaseFee {
+		if msg.GasFeeCap().Cmp(msg.GasTipCap()) < 0 {
+			return fmt.Errorf("%w: address %v, maxPriorityFeePerGas: %s, maxFeePerGas: %s", ErrTipAboveFeeCap,
+				msg.From().Hex(), msg.GasTipCap(), msg.GasFeeCap())
+		}
+		if msg.GasFeeCap().Cmp(baseFee) < 0 {
+			return fmt.Errorf("%w: address %v, maxFeePerGas: %s baseFee: %s", ErrFeeCapTooLow,
+				msg.From().Hex(), msg.GasFeeCap(), baseFee)
+		}
+	}
 	return st.buyGas()
 }
 
@@ -189,10 +221,10 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	msg := st.msg
 	sender := st.from()
 
-	homestead := st.evm.ChainConfig().IsHomestead(st.evm.BlockNumber)
+	rules := st.evm.ChainConfig().Rules(st.evm.BlockNumber)
 	contractCreation := msg.To() == nil
 
-	gas, err := IntrinsicGas(st.data, contractCreation, homestead)
+	gas, err := IntrinsicGas(st.data, msg.AccessList(), contractCreation, rules.IsHomestead, rules.IsShanghai)
 	if err != nil {
 		return nil, err
 	}
@@ -200,6 +232,14 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 		return nil, err
 	}
 
+	if rules.IsShanghai && contractCreation && len(st.data) > params.MaxInitCodeSize {
+		return nil, fmt.Errorf("%w: code size %v limit %v", ErrMaxInitCodeSizeExceeded, len(st.data), params.MaxInitCodeSize)
+	}
+
+	if rules.IsBerlin {
+		st.state.PrepareAccessList(msg.From(), msg.To(), vm.ActivePrecompiles(rules), msg.AccessList())
+	}
+
 	var (
 		evm = st.evm
 
@@ -214,8 +254,12 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 		ret, st.gas, vmerr = evm.Call(sender, st.to().Address(), st.data, st.gas, st.value)
 	}
 
-	st.refundGas()
-	st.state.AddBalance(st.evm.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice))
+	if rules.IsLondon {
+		st.refundGas(params.RefundQuotientEIP3529)
+	} else {
+		st.refundGas(params.RefundQuotient)
+	}
+	st.state.AddBalance(st.evm.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), effectiveTip(st.gasPrice, st.evm.Context.BaseFee)))
 
 	return &ExecutionResult{
 		UsedGas:    st.gasUsed(),
@@ -224,9 +268,9 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	}, nil
 }
 
-func (st *StateTransition) refundGas() {
+func (st *StateTransition) refundGas(refundQuotient uint64) {
 
-	refund := st.gasUsed() / 2
+	refund := st.gasUsed() / refundQuotient
 	if refund > st.state.GetRefund() {
 		refund = st.state.GetRefund()
 	}
@@ -241,10 +285,31 @@ func (st *StateTransition) refundGas() {
 	st.gp.AddGas(st.gas)
 }
 
+// toWordSize returns the ceiled word size required for init code payment calculation.
+func toWordSize(size uint64) uint64 {
+	if size > math.MaxUint64-31 {
+		return math.MaxUint64/32 + 1
+	}
+	return (size + 31) / 32
+}
+
 func (st *StateTransition) gasUsed() uint64 {
 	return st.initialGas - st.gas
 }
 
+// effectiveTip returns the part of the gas price above the base fee, the
+// whole gas price is returned before the base fee is active.
+func effectiveTip(gasPrice, baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return gasPrice
+	}
+	tip := new(big.Int).Sub(gasPrice, baseFee)
+	if tip.Sign() < 0 {
+		return new(big.Int)
+	}
+	return tip
+}
+
 func ApplyMessageEx(evm *vm.EVM, msg Message, gp *GasPool) (*ExecutionResult, *big.Int, error) {
 	return NewStateTransition(evm, msg, gp).TransitionDbEx()
 }
@@ -257,10 +322,10 @@ func (st *StateTransition) TransitionDbEx() (*ExecutionResult, *big.Int, error)
 	msg := st.msg
 	sender := st.from()
 
-	homestead := st.evm.ChainConfig().IsHomestead(st.evm.BlockNumber)
+	rules := st.evm.ChainConfig().Rules(st.evm.BlockNumber)
 	contractCreation := msg.To() == nil
 
-	gas, err := IntrinsicGas(st.data, contractCreation, homestead)
+	gas, err := IntrinsicGas(st.data, msg.AccessList(), contractCreation, rules.IsHomestead, rules.IsShanghai)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -268,6 +333,14 @@ func (st *StateTransition) TransitionDbEx() (*ExecutionResult, *big.Int, error)
 		return nil, nil, err
 	}
 
+	if rules.IsShanghai && contractCreation && len(st.data) > params.MaxInitCodeSize {
+		return nil, nil, fmt.Errorf("%w: code size %v limit %v", ErrMaxInitCodeSizeExceeded, len(st.data), params.MaxInitCodeSize)
+	}
+
+	if rules.IsBerlin {
+		st.state.PrepareAccessList(msg.From(), msg.To(), vm.ActivePrecompiles(rules), msg.AccessList())
+	}
+
 	var (
 		evm = st.evm
 
@@ -284,9 +357,15 @@ func (st *StateTransition) TransitionDbEx() (*ExecutionResult, *big.Int, error)
 
 	}
 
-	st.refundGas()
+	if rules.IsLondon {
+		st.refundGas(params.RefundQuotientEIP3529)
+	} else {
+		st.refundGas(params.RefundQuotient)
+	}
 
-	usedMoney := new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice)
+	// The base fee part of the gas is routed by the consensus engine, only
+	// the tip is counted as the used money of the block
+	usedMoney := new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), effectiveTip(st.gasPrice, st.evm.Context.BaseFee))
 
 	return &ExecutionResult{
 		UsedGas:    st.gasUsed(),
@@ -10,8 +10,10 @@ import (
 	"github.com/neatio-net/neatio/chain/consensus/neatcon/epoch"
 	"github.com/neatio-net/neatio/chain/core/state"
 	"github.com/neatio-net/neatio/chain/core/types"
+	"github.com/neatio-net/neatio/chain/core/vm"
 	neatAbi "github.com/neatio-net/neatio/neatabi/abi"
 	"github.com/neatio-net/neatio/neatcli"
+	"github.com/neatio-net/neatio/params"
 	"github.com/neatio-net/neatio/utilities/common"
 )
 
@@ -30,19 +32,24 @@ type CrossChainHelper interface {
 	GetClient() *neatcli.Client
 	GetMainChainId() string
 	GetChainInfoDB() dbm.DB
+	CheckSideChainRunning(chainId string) bool
 
 	CanCreateSideChain(from common.Address, chainId string, minValidators uint16, minDepositAmount, startupCost *big.Int, startBlock, endBlock *big.Int) error
 	CreateSideChain(from common.Address, chainId string, minValidators uint16, minDepositAmount *big.Int, startBlock, endBlock *big.Int) error
 	ValidateJoinSideChain(from common.Address, pubkey []byte, chainId string, depositAmount *big.Int, signature []byte) error
 	JoinSideChain(from common.Address, pubkey crypto.PubKey, chainId string, depositAmount *big.Int) error
-	ReadyForLaunchSideChain(height *big.Int, stateDB *state.StateDB) ([]string, []byte, []string)
+	ReadyForLaunchSideChain(height *big.Int, stateDB *state.StateDB, neatFork bool) ([]string, []byte, []string)
 	ProcessPostPendingData(newPendingIdxBytes []byte, deleteSideChainIds []string)
+	ValidateDecommissionSideChain(from common.Address, chainId string, proofData []byte) error
+	ReadyForDecommissionSideChain(height *big.Int, stateDB *state.StateDB) []string
+	SettleSideChainDeposits(chainId string, stateDB *state.StateDB) error
+	DecommissionSideChain(chainId string, mainChainHeight *big.Int, proofData []byte) error
 
 	VoteNextEpoch(ep *epoch.Epoch, from common.Address, voteHash common.Hash, txHash common.Hash) error
 	RevealVote(ep *epoch.Epoch, from common.Address, pubkey crypto.PubKey, depositAmount *big.Int, salt string, txHash common.Hash) error
 	UpdateNextEpoch(ep *epoch.Epoch, from common.Address, pubkey crypto.PubKey, depositAmount *big.Int, salt string, txHash common.Hash) error
 
-	GetHeightFromMainChain() *big.Int
+	GetHeightFromMainChain() (*big.Int, error)
 	GetEpochFromMainChain() (string, *epoch.Epoch)
 	GetTxFromMainChain(txHash common.Hash) *types.Transaction
 
@@ -52,6 +59,9 @@ type CrossChainHelper interface {
 	VerifySideChainProofData(bs []byte) error
 	SaveSideChainProofDataToMainChain(bs []byte) error
 
+	// for cross chain messages, returns the chain the header comes from
+	VerifyCrossChainMessageHeader(header *types.Header) (string, error)
+
 	TX3LocalCache
 	ValidateTX3ProofData(proofData *types.TX3ProofData) error
 	ValidateTX4WithInMemTX3ProofData(tx4 *types.Transaction, tx3ProofData *types.TX3ProofData) error
@@ -71,9 +81,21 @@ type NonCrossChainApplyCb = func(tx *types.Transaction, state *state.StateDB, bc
 
 type EtdInsertBlockCb func(bc *BlockChain, block *types.Block)
 
+// StakingHandler backs the staking precompile in the state of a transaction,
+// the next epoch vote set is updated for the candidates it touched once the
+// transaction succeeded
+type StakingHandler interface {
+	vm.StakingHandler
+	UpdateNextEpochVoteSet(tx *types.Transaction, ops *types.PendingOps) error
+}
+
+type StakingHandlerCb = func(state *state.StateDB, bc *BlockChain) StakingHandler
+
 var validateCbMap = make(map[neatAbi.FunctionType]interface{})
 var applyCbMap = make(map[neatAbi.FunctionType]interface{})
+var forkApplyCbMap = make(map[neatAbi.FunctionType]interface{})
 var insertBlockCbMap = make(map[string]EtdInsertBlockCb)
+var stakingHandlerCb StakingHandlerCb
 
 func RegisterValidateCb(function neatAbi.FunctionType, validateCb interface{}) error {
 
@@ -118,6 +140,55 @@ func GetApplyCb(function neatAbi.FunctionType) interface{} {
 	return nil
 }
 
+// RegisterForkApplyCb registers the apply callback of the function from the
+// NeatFork block on. The blocks before the fork keep the callback registered
+// by RegisterApplyCb, or no state change at all if there is none
+func RegisterForkApplyCb(function neatAbi.FunctionType, applyCb interface{}) error {
+
+	_, ok := forkApplyCbMap[function]
+	if ok {
+		return errors.New("the name has registered in forkApplyCbMap")
+	}
+
+	forkApplyCbMap[function] = applyCb
+
+	return nil
+}
+
+// GetApplyCbAt returns the apply callback of the function in the block
+func GetApplyCbAt(config *params.ChainConfig, num *big.Int, function neatAbi.FunctionType) interface{} {
+
+	if config.IsNeatFork(num) {
+		if cb, ok := forkApplyCbMap[function]; ok {
+			return cb
+		}
+	}
+
+	return GetApplyCb(function)
+}
+
+func RegisterStakingHandlerCb(handlerCb StakingHandlerCb) error {
+
+	if stakingHandlerCb != nil {
+		return errors.New("the staking handler has registered")
+	}
+
+	stakingHandlerCb = handlerCb
+
+	return nil
+}
+
+// NewStakingHandler returns the handler of the staking precompile for the
+// state, nil without a registered handler or a blockchain
+func NewStakingHandler(state *state.StateDB, bc *BlockChain) StakingHandler {
+
+	if stakingHandlerCb == nil || bc == nil {
+		return nil
+	}
+
+	return stakingHandlerCb(state, bc)
+}
+
 func RegisterInsertBlockCb(name string, insertBlockCb EtdInsertBlockCb) error {
 
 	_, ok := insertBlockCbMap[name]
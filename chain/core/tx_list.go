@@ -399,15 +399,22 @@ func newTxPricedList(all *map[common.Hash]*types.Transaction) *txPricedList {
 	}
 }
 
-// Put inserts a new transaction into the heap.
+// Put inserts a new transaction into the heap. The special transactions have
+// reserved slots in the pool and are never priced out, they are not tracked.
 func (l *txPricedList) Put(tx *types.Transaction) {
+	if isSpecialTx(tx) {
+		return
+	}
 	heap.Push(l.items, tx)
 }
 
 // Removed notifies the prices transaction list that an old transaction dropped
 // from the pool. The list will just keep a counter of stale objects and update
 // the heap if a large enough ratio of transactions go stale.
-func (l *txPricedList) Removed() {
+func (l *txPricedList) Removed(tx *types.Transaction) {
+	if isSpecialTx(tx) {
+		return
+	}
 	// Bump the stale counter, but exit if still too low (< 25%)
 	l.stales++
 	if l.stales <= len(*l.items)/4 {
@@ -418,7 +425,9 @@ func (l *txPricedList) Removed() {
 
 	l.stales, l.items = 0, &reheap
 	for _, tx := range *l.all {
-		*l.items = append(*l.items, tx)
+		if !isSpecialTx(tx) {
+			*l.items = append(*l.items, tx)
+		}
 	}
 	heap.Init(l.items)
 }
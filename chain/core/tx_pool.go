@@ -44,6 +44,14 @@ var (
 	ErrNegativeValue = errors.New("negative value")
 
 	ErrOversizedData = errors.New("oversized data")
+
+	ErrSpecialLaneFull = errors.New("special transaction lane is full")
+
+	ErrSpecialAccountLimit = errors.New("special transaction limit of the account reached")
+
+	ErrSpecialTypeLimit = errors.New("special transaction of the same type already pooled")
+
+	ErrNoBlockChain = errors.New("no block chain to validate the special transaction")
 )
 
 var (
@@ -97,6 +105,11 @@ type TxPoolConfig struct {
 	GlobalQueue  uint64
 
 	Lifetime time.Duration
+
+	// The special neatabi transactions have their own slots, they are neither
+	// counted in the global slots nor priced out by the ordinary transactions
+	SpecialSlots        uint64
+	SpecialAccountSlots uint64
 }
 
 var DefaultTxPoolConfig = TxPoolConfig{
@@ -112,6 +125,26 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	GlobalQueue:  1024,
 
 	Lifetime: 3 * time.Hour,
+
+	SpecialSlots:        512,
+	SpecialAccountSlots: 4,
+}
+
+// specialTxTypeSlots limits the transactions of a type an account may have in
+// the special lane, a vote or a setting of the candidate only takes effect once
+// per account. The other types are limited by SpecialAccountSlots only
+var specialTxTypeSlots = map[neatAbi.FunctionType]int{
+	neatAbi.VoteNextEpoch:   1,
+	neatAbi.RevealVote:      1,
+	neatAbi.Register:        1,
+	neatAbi.UnRegister:      1,
+	neatAbi.EditValidator:   1,
+	neatAbi.SetCommission:   1,
+	neatAbi.SetAddress:      1,
+	neatAbi.UnBanned:        1,
+	neatAbi.SetBlockReward:  1,
+	neatAbi.JoinSideChain:   1,
+	neatAbi.CreateSideChain: 1,
 }
 
 func (config *TxPoolConfig) sanitize() TxPoolConfig {
@@ -128,6 +161,14 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool price bump", "provided", conf.PriceBump, "updated", DefaultTxPoolConfig.PriceBump)
 		conf.PriceBump = DefaultTxPoolConfig.PriceBump
 	}
+	if conf.SpecialSlots < 1 {
+		log.Warn("Sanitizing invalid txpool special slots", "provided", conf.SpecialSlots, "updated", DefaultTxPoolConfig.SpecialSlots)
+		conf.SpecialSlots = DefaultTxPoolConfig.SpecialSlots
+	}
+	if conf.SpecialAccountSlots < 1 {
+		log.Warn("Sanitizing invalid txpool special account slots", "provided", conf.SpecialAccountSlots, "updated", DefaultTxPoolConfig.SpecialAccountSlots)
+		conf.SpecialAccountSlots = DefaultTxPoolConfig.SpecialAccountSlots
+	}
 	return conf
 }
 
@@ -147,6 +188,10 @@ type TxPool struct {
 	pendingState  *state.ManagedState
 	currentMaxGas uint64
 
+	eip2718  bool // Fork indicator whether typed transactions are accepted
+	eip1559  bool // Fork indicator whether dynamic fee transactions are accepted
+	shanghai bool // Fork indicator whether the init code is limited and metered
+
 	locals  *accountSet
 	journal *txJournal
 
@@ -156,6 +201,8 @@ type TxPool struct {
 	all     map[common.Hash]*types.Transaction
 	priced  *txPricedList
 
+	specials int // Number of the special transactions in all
+
 	wg sync.WaitGroup
 
 	cch CrossChainHelper
@@ -171,7 +218,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		config:      config,
 		chainconfig: chainconfig,
 		chain:       chain,
-		signer:      types.NewEIP155Signer(chainconfig.ChainId),
+		signer:      types.LatestSigner(chainconfig),
 		pending:     make(map[common.Address]*txList),
 		queue:       make(map[common.Address]*txList),
 		beats:       make(map[common.Address]time.Time),
@@ -343,10 +390,16 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.pendingState = state.ManageState(statedb)
 	pool.currentMaxGas = newHead.GasLimit
 
+	next := new(big.Int).Add(newHead.Number, big.NewInt(1))
+	pool.eip2718 = pool.chainconfig.IsBerlin(next)
+	pool.eip1559 = pool.chainconfig.IsLondon(next)
+	pool.shanghai = pool.chainconfig.IsShanghai(next)
+
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
 	pool.addTxsLocked(reinject, false)
 
 	pool.demoteUnexecutables()
+	pool.revalidateSpecialTxs()
 
 	for addr, list := range pool.pending {
 		txs := list.Flatten()
@@ -458,10 +511,21 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 
 func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 
+	if !pool.eip2718 && tx.Type() != types.LegacyTxType {
+		return types.ErrTxTypeNotSupported
+	}
+	if !pool.eip1559 && tx.Type() == types.DynamicFeeTxType {
+		return types.ErrTxTypeNotSupported
+	}
+
 	if tx.Size() > 32*1024 {
 		return ErrOversizedData
 	}
 
+	if pool.shanghai && tx.To() == nil && len(tx.Data()) > params.MaxInitCodeSize {
+		return fmt.Errorf("%w: code size %v limit %v", ErrMaxInitCodeSizeExceeded, len(tx.Data()), params.MaxInitCodeSize)
+	}
+
 	if tx.Value().Sign() < 0 {
 		return ErrNegativeValue
 	}
@@ -470,13 +534,17 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		return ErrGasLimit
 	}
 
+	if tx.GasFeeCap().Cmp(tx.GasTipCap()) < 0 {
+		return ErrTipAboveFeeCap
+	}
+
 	from, err := types.Sender(pool.signer, tx)
 	if err != nil {
 		return ErrInvalidSender
 	}
 
 	local = local || pool.locals.contains(from)
-	if !local && pool.gasPrice.Cmp(tx.GasPrice()) > 0 {
+	if !local && pool.gasPrice.Cmp(tx.GasTipCap()) > 0 {
 		return ErrUnderpriced
 	}
 
@@ -489,7 +557,7 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	}
 
 	if !neatAbi.IsNeatChainContractAddr(tx.To()) {
-		intrGas, err := IntrinsicGas(tx.Data(), tx.To() == nil, true)
+		intrGas, err := IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, true, pool.shanghai)
 		if err != nil {
 			return err
 		}
@@ -498,8 +566,7 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		}
 	} else {
 
-		data := tx.Data()
-		function, err := neatAbi.FunctionTypeFromId(data[:4])
+		function, err := neatAbi.FunctionTypeFromId(tx.Data())
 		if err != nil {
 			return err
 		}
@@ -510,34 +577,130 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 			return ErrNotAllowedInSideChain
 		}
 
-		log.Infof("validateTx Chain Function %v", function.String())
-		if validateCb := GetValidateCb(function); validateCb != nil {
-			if function.IsCrossChainType() {
-				if fn, ok := validateCb.(CrossChainValidateCb); ok {
-					pool.cch.GetMutex().Lock()
-					err := fn(tx, pool.currentState, pool.cch)
-					pool.cch.GetMutex().Unlock()
-					if err != nil {
-						return err
-					}
-				} else {
-					panic("callback func is wrong, this should not happened, please check the code")
+		if tx.Gas() < function.RequiredGas() {
+			return ErrIntrinsicGas
+		}
+
+		log.Debugf("validateTx Chain Function %v", function.String())
+		if err := pool.validateSpecialTx(function, tx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateSpecialTx runs the validate callback of the function against the
+// current state
+func (pool *TxPool) validateSpecialTx(function neatAbi.FunctionType, tx *types.Transaction) error {
+	if validateCb := GetValidateCb(function); validateCb != nil {
+		if function.IsCrossChainType() {
+			if fn, ok := validateCb.(CrossChainValidateCb); ok {
+				pool.cch.GetMutex().Lock()
+				err := fn(tx, pool.currentState, pool.cch)
+				pool.cch.GetMutex().Unlock()
+				if err != nil {
+					return err
 				}
 			} else {
-				if fn, ok := validateCb.(NonCrossChainValidateCb); ok {
-					if err := fn(tx, pool.currentState, pool.chain.(*BlockChain)); err != nil {
-						return err
-					}
-				} else {
-					panic("callback func is wrong, this should not happened, please check the code")
+				panic("callback func is wrong, this should not happened, please check the code")
+			}
+		} else {
+			if fn, ok := validateCb.(NonCrossChainValidateCb); ok {
+				bc, ok := pool.chain.(*BlockChain)
+				if !ok {
+					return ErrNoBlockChain
+				}
+				if err := fn(tx, pool.currentState, bc); err != nil {
+					return err
 				}
+			} else {
+				panic("callback func is wrong, this should not happened, please check the code")
+			}
+		}
+	}
+	return nil
+}
+
+// admitSpecialTx applies the limits of the special lane to the transaction, a
+// transaction replacing a special one of the account takes over its slot
+func (pool *TxPool) admitSpecialTx(from common.Address, tx *types.Transaction, local bool) error {
+	function, err := neatAbi.FunctionTypeFromId(tx.Data())
+	if err != nil {
+		return err
+	}
+
+	var (
+		accountTxs, typeTxs int
+		replace             bool
+	)
+	for _, list := range []*txList{pool.pending[from], pool.queue[from]} {
+		if list == nil {
+			continue
+		}
+		for _, pooled := range list.Flatten() {
+			if !isSpecialTx(pooled) {
+				continue
+			}
+			if pooled.Nonce() == tx.Nonce() {
+				replace = true
+				continue
+			}
+			accountTxs++
+			if f, _ := neatAbi.FunctionTypeFromId(pooled.Data()); f == function {
+				typeTxs++
 			}
 		}
 	}
 
+	if limit, ok := specialTxTypeSlots[function]; ok && typeTxs >= limit {
+		return ErrSpecialTypeLimit
+	}
+	if !local && uint64(accountTxs) >= pool.config.SpecialAccountSlots {
+		return ErrSpecialAccountLimit
+	}
+	if !replace && uint64(pool.specials) >= pool.config.SpecialSlots {
+		return ErrSpecialLaneFull
+	}
 	return nil
 }
 
+// revalidateSpecialTxs drops the special transactions which are no longer
+// valid against the state of the new head
+func (pool *TxPool) revalidateSpecialTxs() {
+	for hash, tx := range pool.all {
+		if !isSpecialTx(tx) {
+			continue
+		}
+		function, err := neatAbi.FunctionTypeFromId(tx.Data())
+		if err == nil {
+			err = pool.validateSpecialTx(function, tx)
+		}
+		if err != nil {
+			log.Debug("Removed invalidated special transaction", "hash", hash, "err", err)
+			pool.removeTx(hash)
+		}
+	}
+}
+
+// allAdd and allRemove keep the count of the special transactions along with
+// the transactions in all
+func (pool *TxPool) allAdd(hash common.Hash, tx *types.Transaction) {
+	if _, ok := pool.all[hash]; !ok && isSpecialTx(tx) {
+		pool.specials++
+	}
+	pool.all[hash] = tx
+}
+
+func (pool *TxPool) allRemove(hash common.Hash) {
+	if tx, ok := pool.all[hash]; ok {
+		if isSpecialTx(tx) {
+			pool.specials--
+		}
+		delete(pool.all, hash)
+	}
+}
+
 func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 
 	hash := tx.Hash()
@@ -552,24 +715,33 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		return false, err
 	}
 
-	if !params.GenCfg.PerfTest &&
-		uint64(len(pool.all)) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
+	from, _ := types.Sender(pool.signer, tx)
 
-		if pool.priced.Underpriced(tx, pool.locals) {
-			log.Trace("Discarding underpriced transaction", "hash", hash, "price", tx.GasPrice())
-			underpricedTxCounter.Inc(1)
-			return false, ErrUnderpriced
+	if isSpecialTx(tx) {
+		if err := pool.admitSpecialTx(from, tx, local || pool.locals.contains(from)); err != nil {
+			log.Trace("Discarding special transaction", "hash", hash, "err", err)
+			return false, err
 		}
+	} else if !params.GenCfg.PerfTest &&
+		uint64(len(pool.all)) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
+
+		// Only the ordinary transactions fill the global slots
+		if ordinary := len(pool.all) - pool.specials; uint64(ordinary) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
+
+			if pool.priced.Underpriced(tx, pool.locals) {
+				log.Trace("Discarding underpriced transaction", "hash", hash, "price", tx.GasPrice())
+				underpricedTxCounter.Inc(1)
+				return false, ErrUnderpriced
+			}
 
-		drop := pool.priced.Discard(len(pool.all)-int(pool.config.GlobalSlots+pool.config.GlobalQueue-1), pool.locals)
-		for _, tx := range drop {
-			log.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "price", tx.GasPrice())
-			underpricedTxCounter.Inc(1)
-			pool.removeTx(tx.Hash())
+			drop := pool.priced.Discard(ordinary-int(pool.config.GlobalSlots+pool.config.GlobalQueue-1), pool.locals)
+			for _, tx := range drop {
+				log.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "price", tx.GasPrice())
+				underpricedTxCounter.Inc(1)
+				pool.removeTx(tx.Hash())
+			}
 		}
 	}
-
-	from, _ := types.Sender(pool.signer, tx)
 	if list := pool.pending[from]; list != nil && list.Overlaps(tx) {
 
 		inserted, old := list.Add(tx, pool.config.PriceBump)
@@ -579,11 +751,11 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		}
 
 		if old != nil {
-			delete(pool.all, old.Hash())
-			pool.priced.Removed()
+			pool.allRemove(old.Hash())
+			pool.priced.Removed(old)
 			pendingReplaceCounter.Inc(1)
 		}
-		pool.all[tx.Hash()] = tx
+		pool.allAdd(tx.Hash(), tx)
 		pool.priced.Put(tx)
 		pool.journalTx(from, tx)
 
@@ -622,12 +794,14 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 	}
 
 	if old != nil {
-		delete(pool.all, old.Hash())
-		pool.priced.Removed()
+		pool.allRemove(old.Hash())
+		pool.priced.Removed(old)
 		queuedReplaceCounter.Inc(1)
 	}
-	pool.all[hash] = tx
-	pool.priced.Put(tx)
+	if pool.all[hash] == nil {
+		pool.allAdd(hash, tx)
+		pool.priced.Put(tx)
+	}
 	return old != nil, nil
 }
 
@@ -651,22 +825,22 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	inserted, old := list.Add(tx, pool.config.PriceBump)
 	if !inserted {
 
-		delete(pool.all, hash)
-		pool.priced.Removed()
+		pool.allRemove(hash)
+		pool.priced.Removed(tx)
 
 		pendingDiscardCounter.Inc(1)
 		return
 	}
 
 	if old != nil {
-		delete(pool.all, old.Hash())
-		pool.priced.Removed()
+		pool.allRemove(old.Hash())
+		pool.priced.Removed(old)
 
 		pendingReplaceCounter.Inc(1)
 	}
 
 	if pool.all[hash] == nil {
-		pool.all[hash] = tx
+		pool.allAdd(hash, tx)
 		pool.priced.Put(tx)
 	}
 
@@ -781,8 +955,8 @@ func (pool *TxPool) removeTx(hash common.Hash) {
 	}
 	addr, _ := types.Sender(pool.signer, tx)
 
-	delete(pool.all, hash)
-	pool.priced.Removed()
+	pool.allRemove(hash)
+	pool.priced.Removed(tx)
 
 	if pending := pool.pending[addr]; pending != nil {
 		if removed, invalids := pending.Remove(tx); removed {
@@ -829,16 +1003,16 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 		for _, tx := range list.Forward(pool.currentState.GetNonce(addr)) {
 			hash := tx.Hash()
 			log.Trace("Removed old queued transaction", "hash", hash)
-			delete(pool.all, hash)
-			pool.priced.Removed()
+			pool.allRemove(hash)
+			pool.priced.Removed(tx)
 		}
 
 		drops, _ := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			log.Trace("Removed unpayable queued transaction", "hash", hash)
-			delete(pool.all, hash)
-			pool.priced.Removed()
+			pool.allRemove(hash)
+			pool.priced.Removed(tx)
 			queuedNofundsCounter.Inc(1)
 		}
 
@@ -851,8 +1025,8 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 		if !pool.locals.contains(addr) {
 			for _, tx := range list.Cap(int(pool.config.AccountQueue)) {
 				hash := tx.Hash()
-				delete(pool.all, hash)
-				pool.priced.Removed()
+				pool.allRemove(hash)
+				pool.priced.Removed(tx)
 				queuedRateLimitCounter.Inc(1)
 				log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
 			}
@@ -865,7 +1039,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 
 	pending := uint64(0)
 	for _, list := range pool.pending {
-		pending += uint64(list.Len())
+		pending += uint64(ordinaryLen(list))
 	}
 	if pending > pool.config.GlobalSlots {
 		pendingBeforeCap := pending
@@ -873,8 +1047,8 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 		spammers := prque.New(nil)
 		for addr, list := range pool.pending {
 
-			if !pool.locals.contains(addr) && uint64(list.Len()) > pool.config.AccountSlots {
-				spammers.Push(addr, int64(list.Len()))
+			if n := ordinaryLen(list); !pool.locals.contains(addr) && uint64(n) > pool.config.AccountSlots {
+				spammers.Push(addr, int64(n))
 			}
 		}
 
@@ -886,44 +1060,34 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 
 			if len(offenders) > 1 {
 
-				threshold := pool.pending[offender.(common.Address)].Len()
+				threshold := ordinaryLen(pool.pending[offender.(common.Address)])
 
-				for pending > pool.config.GlobalSlots && pool.pending[offenders[len(offenders)-2]].Len() > threshold {
+				for pending > pool.config.GlobalSlots && ordinaryLen(pool.pending[offenders[len(offenders)-2]]) > threshold {
+					capped := false
 					for i := 0; i < len(offenders)-1; i++ {
-						list := pool.pending[offenders[i]]
-						for _, tx := range list.Cap(list.Len() - 1) {
-
-							hash := tx.Hash()
-							delete(pool.all, hash)
-							pool.priced.Removed()
-
-							if nonce := tx.Nonce(); pool.pendingState.GetNonce(offenders[i]) > nonce {
-								pool.pendingState.SetNonce(offenders[i], nonce)
-							}
-							log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
+						if ordinaryLen(pool.pending[offenders[i]]) > threshold && pool.capPending(offenders[i]) {
+							pending--
+							capped = true
 						}
-						pending--
+					}
+					if !capped {
+						break
 					}
 				}
 			}
 		}
 
 		if pending > pool.config.GlobalSlots && len(offenders) > 0 {
-			for pending > pool.config.GlobalSlots && uint64(pool.pending[offenders[len(offenders)-1]].Len()) > pool.config.AccountSlots {
+			for pending > pool.config.GlobalSlots && uint64(ordinaryLen(pool.pending[offenders[len(offenders)-1]])) > pool.config.AccountSlots {
+				capped := false
 				for _, addr := range offenders {
-					list := pool.pending[addr]
-					for _, tx := range list.Cap(list.Len() - 1) {
-
-						hash := tx.Hash()
-						delete(pool.all, hash)
-						pool.priced.Removed()
-
-						if nonce := tx.Nonce(); pool.pendingState.GetNonce(addr) > nonce {
-							pool.pendingState.SetNonce(addr, nonce)
-						}
-						log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
+					if uint64(ordinaryLen(pool.pending[addr])) > pool.config.AccountSlots && pool.capPending(addr) {
+						pending--
+						capped = true
 					}
-					pending--
+				}
+				if !capped {
+					break
 				}
 			}
 		}
@@ -932,7 +1096,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 
 	queued := uint64(0)
 	for _, list := range pool.queue {
-		queued += uint64(list.Len())
+		queued += uint64(ordinaryLen(list))
 	}
 	if queued > pool.config.GlobalQueue {
 
@@ -950,17 +1114,10 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 
 			addresses = addresses[:len(addresses)-1]
 
-			if size := uint64(list.Len()); size <= drop {
-				for _, tx := range list.Flatten() {
-					pool.removeTx(tx.Hash())
-				}
-				drop -= size
-				queuedRateLimitCounter.Inc(int64(size))
-				continue
-			}
-
+			// Drop from the highest nonce down to the first special transaction,
+			// the ones below it are kept to leave no nonce gap
 			txs := list.Flatten()
-			for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
+			for i := len(txs) - 1; i >= 0 && drop > 0 && !isSpecialTx(txs[i]); i-- {
 				pool.removeTx(txs[i].Hash())
 				drop--
 				queuedRateLimitCounter.Inc(1)
@@ -977,16 +1134,16 @@ func (pool *TxPool) demoteUnexecutables() {
 		for _, tx := range list.Forward(nonce) {
 			hash := tx.Hash()
 			log.Trace("Removed old pending transaction", "hash", hash)
-			delete(pool.all, hash)
-			pool.priced.Removed()
+			pool.allRemove(hash)
+			pool.priced.Removed(tx)
 		}
 
 		drops, invalids := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
-			delete(pool.all, hash)
-			pool.priced.Removed()
+			pool.allRemove(hash)
+			pool.priced.Removed(tx)
 			pendingNofundsCounter.Inc(1)
 		}
 		for _, tx := range invalids {
@@ -1010,6 +1167,44 @@ func (pool *TxPool) demoteUnexecutables() {
 	}
 }
 
+// capPending drops the highest nonce pending transaction of the account for the
+// fairness cap. A special transaction is never dropped, and neither are the
+// ordinary ones below it which would leave it behind a nonce gap
+func (pool *TxPool) capPending(addr common.Address) bool {
+	list := pool.pending[addr]
+	if txs := list.Flatten(); len(txs) == 0 || isSpecialTx(txs[len(txs)-1]) {
+		return false
+	}
+	for _, tx := range list.Cap(list.Len() - 1) {
+
+		hash := tx.Hash()
+		pool.allRemove(hash)
+		pool.priced.Removed(tx)
+
+		if nonce := tx.Nonce(); pool.pendingState.GetNonce(addr) > nonce {
+			pool.pendingState.SetNonce(addr, nonce)
+		}
+		log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
+	}
+	return true
+}
+
+// isSpecialTx reports whether the transaction is a special neatabi transaction
+func isSpecialTx(tx *types.Transaction) bool {
+	return neatAbi.IsNeatChainContractAddr(tx.To())
+}
+
+// ordinaryLen returns the number of the ordinary transactions in the list
+func ordinaryLen(list *txList) int {
+	count := list.Len()
+	for _, tx := range list.Flatten() {
+		if isSpecialTx(tx) {
+			count--
+		}
+	}
+	return count
+}
+
 type addressByHeartbeat struct {
 	address   common.Address
 	heartbeat time.Time
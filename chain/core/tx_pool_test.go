@@ -2,6 +2,7 @@ package core
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"math/big"
@@ -13,6 +14,9 @@ import (
 	"github.com/neatio-net/neatio/chain/core/rawdb"
 	"github.com/neatio-net/neatio/chain/core/state"
 	"github.com/neatio-net/neatio/chain/core/types"
+	"github.com/neatio-net/neatio/chain/core/vm"
+	"github.com/neatio-net/neatio/chain/log"
+	neatAbi "github.com/neatio-net/neatio/neatabi/abi"
 	"github.com/neatio-net/neatio/params"
 	"github.com/neatio-net/neatio/utilities/common"
 	"github.com/neatio-net/neatio/utilities/crypto"
@@ -78,8 +82,17 @@ func validateTxPoolInternals(pool *TxPool) error {
 	if total := len(pool.all); total != pending+queued {
 		return fmt.Errorf("total transaction count %d != %d pending + %d queued", total, pending, queued)
 	}
-	if priced := pool.priced.items.Len() - pool.priced.stales; priced != pending+queued {
-		return fmt.Errorf("total priced transaction count %d != %d pending + %d queued", priced, pending, queued)
+	special := 0
+	for _, tx := range pool.all {
+		if isSpecialTx(tx) {
+			special++
+		}
+	}
+	if special != pool.specials {
+		return fmt.Errorf("special transaction count %d != %d counted", pool.specials, special)
+	}
+	if priced := pool.priced.items.Len() - pool.priced.stales; priced != pending+queued-special {
+		return fmt.Errorf("total priced transaction count %d != %d pending + %d queued - %d special", priced, pending, queued, special)
 	}
 	for addr, txs := range pool.pending {
 		var last uint64
@@ -949,6 +962,275 @@ func TestTransactionPendingGlobalLimiting(t *testing.T) {
 	}
 }
 
+func specialTransaction(nonce uint64, gasprice *big.Int, key *ecdsa.PrivateKey, method string, args ...interface{}) *types.Transaction {
+	data, _ := neatAbi.ChainABI.Pack(method, args...)
+	tx, _ := types.SignTx(types.NewTransaction(nonce, neatAbi.NeatioSmartContractAddress, new(big.Int), 100000, gasprice, data), types.LatestSigner(params.TestChainConfig), key)
+	return tx
+}
+
+// Tests that the special transactions are neither priced out nor counted by
+// the ordinary transactions, and that the special lane enforces its limits.
+func TestSpecialTransactionLane(t *testing.T) {
+	t.Parallel()
+
+	db := rawdb.NewMemoryDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.GlobalSlots = 2
+	config.GlobalQueue = 2
+	config.SpecialSlots = 3
+	config.SpecialAccountSlots = 2
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain, nil)
+	defer pool.Stop()
+
+	keys := make([]*ecdsa.PrivateKey, 4)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		pool.currentState.AddBalance(crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000000))
+	}
+	candidate := common.HexToAddress("0xca11")
+
+	// A cheap delegation survives a full pool of better paying transfers
+	if err := pool.AddRemote(specialTransaction(0, big.NewInt(1), keys[1], "Delegate", candidate)); err != nil {
+		t.Fatalf("failed to add special transaction: %v", err)
+	}
+	signer := types.LatestSigner(params.TestChainConfig)
+	for i := uint64(0); i < 6; i++ {
+		tx, _ := types.SignTx(types.NewTransaction(i, common.Address{}, big.NewInt(100), 100000, big.NewInt(2), nil), signer, keys[0])
+		pool.AddRemote(tx)
+	}
+	if pending, queued := pool.Stats(); pending+queued != 5 {
+		t.Fatalf("pooled transaction count mismatch: have %d, want %d", pending+queued, 5)
+	}
+	if pool.pending[crypto.PubkeyToAddress(keys[1].PublicKey)] == nil {
+		t.Fatalf("special transaction priced out")
+	}
+
+	// Per type and per account limits
+	if err := pool.AddRemote(specialTransaction(1, big.NewInt(1), keys[1], "SetCommission", uint8(10))); err != nil {
+		t.Fatalf("failed to add special transaction: %v", err)
+	}
+	if err := pool.AddRemote(specialTransaction(2, big.NewInt(1), keys[1], "Delegate", candidate)); err != ErrSpecialAccountLimit {
+		t.Fatalf("account limit: have %v, want %v", err, ErrSpecialAccountLimit)
+	}
+	if err := pool.AddRemote(specialTransaction(0, big.NewInt(1), keys[2], "SetCommission", uint8(10))); err != nil {
+		t.Fatalf("failed to add special transaction: %v", err)
+	}
+	if err := pool.AddRemote(specialTransaction(1, big.NewInt(1), keys[2], "SetCommission", uint8(20))); err != ErrSpecialTypeLimit {
+		t.Fatalf("type limit: have %v, want %v", err, ErrSpecialTypeLimit)
+	}
+
+	// The lane is full, only replacements are accepted
+	if err := pool.AddRemote(specialTransaction(0, big.NewInt(1), keys[3], "Delegate", candidate)); err != ErrSpecialLaneFull {
+		t.Fatalf("full lane: have %v, want %v", err, ErrSpecialLaneFull)
+	}
+	if err := pool.AddRemote(specialTransaction(0, big.NewInt(2), keys[2], "SetCommission", uint8(20))); err != nil {
+		t.Fatalf("failed to replace special transaction: %v", err)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+var unBannedMarker = common.HexToHash("0xba")
+
+func init() {
+	// UnBanned is not valid any more once the marker is set in the state of
+	// the sender
+	RegisterValidateCb(neatAbi.UnBanned, NonCrossChainValidateCb(func(tx *types.Transaction, state *state.StateDB, bc *BlockChain) error {
+		from, _ := types.Sender(types.LatestSigner(params.TestChainConfig), tx)
+		if state.GetState(from, unBannedMarker) != (common.Hash{}) {
+			return errors.New("not banned")
+		}
+		return nil
+	}))
+}
+
+// Tests that the special transactions validated against the block chain are
+// rejected by a pool not running on one.
+func TestSpecialTransactionNoBlockChain(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	if err := pool.AddRemote(specialTransaction(0, big.NewInt(1), key, "UnBanned")); err != ErrNoBlockChain {
+		t.Errorf("special transaction without block chain: have %v, want %v", err, ErrNoBlockChain)
+	}
+}
+
+// Tests that the special transactions invalidated by the new head are dropped
+// on reset, and the ordinary ones of the account are demoted behind the gap.
+func TestSpecialTransactionRevalidation(t *testing.T) {
+	t.Parallel()
+
+	invalid, _ := crypto.GenerateKey()
+	valid, _ := crypto.GenerateKey()
+	invalidAddr, validAddr := crypto.PubkeyToAddress(invalid.PublicKey), crypto.PubkeyToAddress(valid.PublicKey)
+
+	// the validate callbacks of the special transactions run against the block chain
+	db := rawdb.NewMemoryDatabase()
+	config := *params.TestChainConfig
+	config.ChainLogger = log.Root()
+	gspec := &Genesis{
+		Config:   &config,
+		GasLimit: 1000000,
+		Alloc: GenesisAlloc{
+			invalidAddr: {Balance: big.NewInt(1000000000)},
+			validAddr:   {Balance: big.NewInt(1000000000)},
+		},
+	}
+	genesis := gspec.MustCommit(db)
+	blockchain, _ := NewBlockChain(db, nil, gspec.Config, nil, vm.Config{}, nil)
+	defer blockchain.Stop()
+
+	pool := NewTxPool(testTxPoolConfig, params.TestChainConfig, blockchain, nil)
+	defer pool.Stop()
+
+	signer := types.LatestSigner(params.TestChainConfig)
+	ordinary, _ := types.SignTx(types.NewTransaction(1, common.Address{}, big.NewInt(100), 100000, big.NewInt(1), nil), signer, invalid)
+	for _, tx := range []*types.Transaction{
+		specialTransaction(0, big.NewInt(1), invalid, "UnBanned"),
+		ordinary,
+		specialTransaction(0, big.NewInt(1), valid, "UnBanned"),
+	} {
+		if err := pool.AddRemote(tx); err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+	if pending, _ := pool.Stats(); pending != 3 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 3)
+	}
+
+	statedb, _ := blockchain.StateAt(genesis.Root())
+	statedb.SetState(invalidAddr, unBannedMarker, common.BytesToHash([]byte{1}))
+	root, _ := statedb.Commit(false)
+	pool.lockedReset(nil, &types.Header{Number: big.NewInt(1), GasLimit: 1000000, Root: root})
+
+	if pool.pending[invalidAddr] != nil {
+		t.Errorf("invalidated special transaction still pending")
+	}
+	if list := pool.queue[invalidAddr]; list == nil || list.txs.Get(1) == nil {
+		t.Errorf("ordinary transaction not demoted")
+	}
+	if list := pool.pending[validAddr]; list == nil || list.Len() != 1 {
+		t.Errorf("valid special transaction dropped")
+	}
+	if pool.specials != 1 {
+		t.Errorf("special transaction count mismatched: have %d, want %d", pool.specials, 1)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the fairness cap of the pending transactions counts the ordinary
+// transactions only, and drops neither a special transaction nor the ordinary
+// ones below it.
+func TestSpecialTransactionPendingCap(t *testing.T) {
+	t.Parallel()
+
+	db := rawdb.NewMemoryDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.AccountSlots = 1
+	config.GlobalSlots = 2
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain, nil)
+	defer pool.Stop()
+
+	keys := make([]*ecdsa.PrivateKey, 2)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		pool.currentState.AddBalance(crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000000))
+	}
+	candidate := common.HexToAddress("0xca11")
+	signer := types.LatestSigner(params.TestChainConfig)
+
+	// The first account ends its nonces with a special transaction, the
+	// second one has ordinary transactions only
+	var txs types.Transactions
+	for i := uint64(0); i < 3; i++ {
+		tx, _ := types.SignTx(types.NewTransaction(i, common.Address{}, big.NewInt(100), 100000, big.NewInt(1), nil), signer, keys[0])
+		txs = append(txs, tx)
+	}
+	txs = append(txs, specialTransaction(3, big.NewInt(1), keys[0], "Delegate", candidate))
+	for i := uint64(0); i < 3; i++ {
+		tx, _ := types.SignTx(types.NewTransaction(i, common.Address{}, big.NewInt(100), 100000, big.NewInt(1), nil), signer, keys[1])
+		txs = append(txs, tx)
+	}
+	pool.AddRemotes(txs)
+
+	first, second := pool.pending[crypto.PubkeyToAddress(keys[0].PublicKey)], pool.pending[crypto.PubkeyToAddress(keys[1].PublicKey)]
+	if first == nil || first.Len() != 4 {
+		t.Fatalf("special transaction or the ones below it dropped, pending %v", first)
+	}
+	if second == nil || second.Len() != int(config.AccountSlots) {
+		t.Fatalf("pending transactions of the second account mismatched: have %v, want %d", second, config.AccountSlots)
+	}
+	if pool.specials != 1 {
+		t.Errorf("special transaction count mismatched: have %d, want %d", pool.specials, 1)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the global queue limit drops the ordinary transactions above the
+// special ones only.
+func TestSpecialTransactionQueueCap(t *testing.T) {
+	t.Parallel()
+
+	db := rawdb.NewMemoryDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.GlobalQueue = 1
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain, nil)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	pool.currentState.AddBalance(addr, big.NewInt(1000000000))
+	candidate := common.HexToAddress("0xca11")
+	signer := types.LatestSigner(params.TestChainConfig)
+
+	// Queued behind the missing nonce 0: ordinary 1, special 2, ordinary 3 and 4
+	var txs types.Transactions
+	for _, nonce := range []uint64{1, 3, 4} {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(100), 100000, big.NewInt(1), nil), signer, key)
+		txs = append(txs, tx)
+	}
+	txs = append(txs, specialTransaction(2, big.NewInt(1), key, "Delegate", candidate))
+	pool.AddRemotes(txs)
+
+	list := pool.queue[addr]
+	if list == nil {
+		t.Fatalf("queued transactions dropped")
+	}
+	for _, nonce := range []uint64{1, 2} {
+		if list.txs.Get(nonce) == nil {
+			t.Errorf("queued transaction %d below the special one dropped", nonce)
+		}
+	}
+	for _, nonce := range []uint64{3, 4} {
+		if list.txs.Get(nonce) != nil {
+			t.Errorf("queued transaction %d above the special one kept", nonce)
+		}
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 func TestTransactionCapClearsFromAll(t *testing.T) {
 	t.Parallel()
 
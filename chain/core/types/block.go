@@ -63,6 +63,9 @@ type Header struct {
 	Nonce       BlockNonce     `json:"nonce"            gencodec:"required"`
 
 	MainChainNumber *big.Int `json:"mainNumber"           gencodec:"required"`
+
+	// BaseFee was added by EIP-1559 and is ignored in legacy headers.
+	BaseFee *big.Int `json:"baseFeePerGas" rlp:"optional"`
 }
 
 type headerMarshaling struct {
@@ -72,6 +75,7 @@ type headerMarshaling struct {
 	GasUsed    hexutil.Uint64
 	Time       *hexutil.Big
 	Extra      hexutil.Bytes
+	BaseFee    *hexutil.Big
 	Hash       common.Hash `json:"hash"`
 }
 
@@ -126,6 +130,16 @@ func rlpHash(x interface{}) (h common.Hash) {
 	return h
 }
 
+// prefixedRlpHash writes the prefix into the hasher before rlp-encoding x.
+// It's used for typed transactions.
+func prefixedRlpHash(prefix byte, x interface{}) (h common.Hash) {
+	hw := sha3.NewLegacyKeccak256()
+	hw.Write([]byte{prefix})
+	rlp.Encode(hw, x)
+	hw.Sum(h[:0])
+	return h
+}
+
 type Body struct {
 	Transactions []*Transaction
 	Uncles       []*Header
@@ -213,6 +227,9 @@ func CopyHeader(h *Header) *Header {
 		cpy.Extra = make([]byte, len(h.Extra))
 		copy(cpy.Extra, h.Extra)
 	}
+	if h.BaseFee != nil {
+		cpy.BaseFee = new(big.Int).Set(h.BaseFee)
+	}
 	return &cpy
 }
 
@@ -228,6 +245,9 @@ func CopyWithoutTimeHeader(h *Header) *Header {
 		cpy.Extra = make([]byte, len(h.Extra))
 		copy(cpy.Extra, h.Extra)
 	}
+	if h.BaseFee != nil {
+		cpy.BaseFee = new(big.Int).Set(h.BaseFee)
+	}
 	return &cpy
 }
 
@@ -289,6 +309,13 @@ func (b *Block) ReceiptHash() common.Hash { return b.header.ReceiptHash }
 func (b *Block) UncleHash() common.Hash   { return b.header.UncleHash }
 func (b *Block) Extra() []byte            { return common.CopyBytes(b.header.Extra) }
 
+func (b *Block) BaseFee() *big.Int {
+	if b.header.BaseFee == nil {
+		return nil
+	}
+	return new(big.Int).Set(b.header.BaseFee)
+}
+
 func (b *Block) Header() *Header { return CopyHeader(b.header) }
 
 func (b *Block) Body() *Body { return &Body{b.transactions, b.uncles} }
@@ -418,6 +445,10 @@ type TX3ProofData struct {
 
 	TxIndexs []uint
 	TxProofs []*BSKeyValueSet
+
+	// receipt proofs of the txs at the same index, a transfer to another side
+	// chain is settled only with the receipt of the successful burn
+	ReceiptProofs []*BSKeyValueSet `rlp:"optional"`
 }
 
 func NewSideChainProofData(block *Block) (*SideChainProofData, error) {
@@ -428,7 +459,9 @@ func NewSideChainProofData(block *Block) (*SideChainProofData, error) {
 	return ret, nil
 }
 
-func NewTX3ProofData(block *Block) (*TX3ProofData, error) {
+// NewTX3ProofData proves the withdrawals and the transfers of the block, their
+// receipts are proved too if the receipts of the block are given
+func NewTX3ProofData(block *Block, receipts Receipts) (*TX3ProofData, error) {
 	ret := &TX3ProofData{
 		Header: block.Header(),
 	}
@@ -436,11 +469,11 @@ func NewTX3ProofData(block *Block) (*TX3ProofData, error) {
 	txs := block.Transactions()
 
 	keybuf := new(bytes.Buffer)
-	trie := new(trie.Trie)
+	txTrie := new(trie.Trie)
 	for i := 0; i < txs.Len(); i++ {
 		keybuf.Reset()
 		rlp.Encode(keybuf, uint(i))
-		trie.Update(keybuf.Bytes(), txs.GetRlp(i))
+		txTrie.Update(keybuf.Bytes(), txs.GetRlp(i))
 	}
 
 	for i, tx := range txs {
@@ -451,11 +484,11 @@ func NewTX3ProofData(block *Block) (*TX3ProofData, error) {
 				continue
 			}
 
-			if function == neatAbi.WithdrawFromSideChain {
+			if function == neatAbi.WithdrawFromSideChain || function == neatAbi.TransferToSideChain {
 				kvSet := MakeBSKeyValueSet()
 				keybuf.Reset()
 				rlp.Encode(keybuf, uint(i))
-				if err := trie.Prove(keybuf.Bytes(), 0, kvSet); err != nil {
+				if err := txTrie.Prove(keybuf.Bytes(), 0, kvSet); err != nil {
 					return nil, err
 				}
 
@@ -465,6 +498,26 @@ func NewTX3ProofData(block *Block) (*TX3ProofData, error) {
 		}
 	}
 
+	if len(ret.TxIndexs) == 0 || receipts.Len() != txs.Len() {
+		return ret, nil
+	}
+
+	receiptTrie := new(trie.Trie)
+	for i := 0; i < receipts.Len(); i++ {
+		keybuf.Reset()
+		rlp.Encode(keybuf, uint(i))
+		receiptTrie.Update(keybuf.Bytes(), receipts.GetRlp(i))
+	}
+	for _, txIndex := range ret.TxIndexs {
+		kvSet := MakeBSKeyValueSet()
+		keybuf.Reset()
+		rlp.Encode(keybuf, txIndex)
+		if err := receiptTrie.Prove(keybuf.Bytes(), 0, kvSet); err != nil {
+			return nil, err
+		}
+		ret.ReceiptProofs = append(ret.ReceiptProofs, kvSet)
+	}
+
 	return ret, nil
 }
 
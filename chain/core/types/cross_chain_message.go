@@ -0,0 +1,121 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/neatio-net/neatio/chain/trie"
+	neatAbi "github.com/neatio-net/neatio/neatabi/abi"
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/crypto"
+	"github.com/neatio-net/neatio/utilities/rlp"
+)
+
+// CrossChainMessage is sent by a contract emitting the CrossChainMessage event,
+// the emitting contract is the sender
+type CrossChainMessage struct {
+	Sender    common.Address
+	ToChainId string
+	Target    common.Address
+	Payload   []byte
+}
+
+// CrossChainMessageProofData proves the message log is in a receipt of the
+// block of the source chain
+type CrossChainMessageProofData struct {
+	Header *Header
+
+	ReceiptIndex uint
+	ReceiptProof *BSKeyValueSet
+	LogIndex     uint
+}
+
+func NewCrossChainMessageProofData(header *Header, receipts Receipts, receiptIndex, logIndex uint) (*CrossChainMessageProofData, error) {
+	if int(receiptIndex) >= len(receipts) {
+		return nil, fmt.Errorf("receipt index %v out of range", receiptIndex)
+	}
+	if int(logIndex) >= len(receipts[receiptIndex].Logs) {
+		return nil, fmt.Errorf("log index %v out of range", logIndex)
+	}
+
+	keybuf := new(bytes.Buffer)
+	trie := new(trie.Trie)
+	for i := 0; i < receipts.Len(); i++ {
+		keybuf.Reset()
+		rlp.Encode(keybuf, uint(i))
+		trie.Update(keybuf.Bytes(), receipts.GetRlp(i))
+	}
+
+	kvSet := MakeBSKeyValueSet()
+	keybuf.Reset()
+	rlp.Encode(keybuf, receiptIndex)
+	if err := trie.Prove(keybuf.Bytes(), 0, kvSet); err != nil {
+		return nil, err
+	}
+
+	return &CrossChainMessageProofData{
+		Header:       header,
+		ReceiptIndex: receiptIndex,
+		ReceiptProof: kvSet,
+		LogIndex:     logIndex,
+	}, nil
+}
+
+// Message verifies the receipt against the receipt root of the header and
+// decodes the message from the log, the header itself is not verified here
+func (p *CrossChainMessageProofData) Message() (*CrossChainMessage, error) {
+	if p.Header == nil || p.ReceiptProof == nil {
+		return nil, errors.New("incomplete cross chain message proof data")
+	}
+
+	receipt, err := VerifyReceiptProof(p.Header.ReceiptHash, p.ReceiptIndex, p.ReceiptProof)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != ReceiptStatusSuccessful {
+		return nil, errors.New("the transaction sending the message failed")
+	}
+	if int(p.LogIndex) >= len(receipt.Logs) {
+		return nil, fmt.Errorf("log index %v out of range", p.LogIndex)
+	}
+
+	log := receipt.Logs[p.LogIndex]
+	if len(log.Topics) == 0 || log.Topics[0] != neatAbi.ChainABI.Events["CrossChainMessage"].ID() {
+		return nil, errors.New("not a cross chain message log")
+	}
+
+	var args neatAbi.CrossChainMessageArgs
+	if err := neatAbi.ChainABI.Unpack(&args, "CrossChainMessage", log.Data); err != nil {
+		return nil, err
+	}
+
+	return &CrossChainMessage{
+		Sender:    log.Address,
+		ToChainId: args.ToChainId,
+		Target:    args.Target,
+		Payload:   args.Payload,
+	}, nil
+}
+
+// VerifyReceiptProof returns the receipt at the index of the receipt trie with the root
+func VerifyReceiptProof(receiptHash common.Hash, receiptIndex uint, proof *BSKeyValueSet) (*Receipt, error) {
+	keybuf := new(bytes.Buffer)
+	rlp.Encode(keybuf, receiptIndex)
+	val, _, err := trie.VerifyProof(receiptHash, keybuf.Bytes(), proof)
+	if err != nil {
+		return nil, err
+	}
+
+	var receipt Receipt
+	if err := rlp.DecodeBytes(val, &receipt); err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// MessageId identifies the message among all the chains, it is delivered only once
+func (p *CrossChainMessageProofData) MessageId() common.Hash {
+	enc, _ := rlp.EncodeToBytes([]interface{}{p.Header.Hash(), p.ReceiptIndex, p.LogIndex})
+	return crypto.Keccak256Hash(enc)
+}
@@ -0,0 +1,67 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	neatAbi "github.com/neatio-net/neatio/neatabi/abi"
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/rlp"
+)
+
+func TestCrossChainMessageProofData(t *testing.T) {
+	event := neatAbi.ChainABI.Events["CrossChainMessage"]
+	target := common.HexToAddress("0x0000000000000000000000000000000000000bbb")
+	data, err := event.Inputs.Pack("sidetest", target, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sender := common.HexToAddress("0x0000000000000000000000000000000000000aaa")
+	receipts := Receipts{
+		&Receipt{Status: ReceiptStatusSuccessful, Logs: []*Log{}},
+		&Receipt{Status: ReceiptStatusSuccessful, Logs: []*Log{
+			{Address: common.HexToAddress("0x01"), Topics: []common.Hash{{}}},
+			{Address: sender, Topics: []common.Hash{event.ID()}, Data: data},
+		}},
+	}
+	header := &Header{Number: big.NewInt(1), ReceiptHash: DeriveSha(receipts)}
+
+	proofData, err := NewCrossChainMessageProofData(header, receipts, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := rlp.EncodeToBytes(proofData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded CrossChainMessageProofData
+	if err := rlp.DecodeBytes(enc, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := decoded.Message()
+	if err != nil {
+		t.Fatalf("verify message error %v", err)
+	}
+	if msg.Sender != sender || msg.ToChainId != "sidetest" || msg.Target != target || !bytes.Equal(msg.Payload, []byte("hello")) {
+		t.Errorf("message mismatch, got %+v", msg)
+	}
+	if decoded.MessageId() != proofData.MessageId() {
+		t.Errorf("message id changed by the encoding")
+	}
+
+	// the other log is not a message
+	decoded.LogIndex = 0
+	if _, err := decoded.Message(); err == nil {
+		t.Errorf("expected error for a log not sending a message")
+	}
+
+	// the proof does not match another receipt root
+	decoded.LogIndex = 1
+	decoded.Header = &Header{Number: big.NewInt(1), ReceiptHash: common.Hash{1}}
+	if _, err := decoded.Message(); err == nil {
+		t.Errorf("expected error for a tampered receipt root")
+	}
+}
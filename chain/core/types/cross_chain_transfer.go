@@ -0,0 +1,77 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/neatio-net/neatio/chain/trie"
+	neatAbi "github.com/neatio-net/neatio/neatabi/abi"
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/crypto"
+	"github.com/neatio-net/neatio/utilities/rlp"
+)
+
+// DecodeSideChainTransfer returns the burn proved by the tx3 proof data of a
+// TransferToSideChain tx of the main chain, the burn must have succeeded. The
+// header is not verified here
+func DecodeSideChainTransfer(bs []byte, txHash common.Hash) (*TX3ProofData, *Transaction, *neatAbi.TransferToSideChainArgs, error) {
+
+	var proofData TX3ProofData
+	if err := rlp.DecodeBytes(bs, &proofData); err != nil {
+		return nil, nil, nil, err
+	}
+	if proofData.Header == nil || len(proofData.TxIndexs) != 1 || len(proofData.TxProofs) != 1 || len(proofData.ReceiptProofs) != 1 {
+		return nil, nil, nil, errors.New("invalid tx3 proof data of the side chain transfer")
+	}
+
+	keybuf := new(bytes.Buffer)
+	rlp.Encode(keybuf, proofData.TxIndexs[0])
+	val, _, err := trie.VerifyProof(proofData.Header.TxHash, keybuf.Bytes(), proofData.TxProofs[0])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var tx Transaction
+	if err := tx.UnmarshalBinary(val); err != nil {
+		return nil, nil, nil, err
+	}
+	if tx.Hash() != txHash {
+		return nil, nil, nil, errors.New("tx3 proof data does not prove the side chain transfer")
+	}
+
+	if !neatAbi.IsNeatChainContractAddr(tx.To()) || len(tx.Data()) < 4 {
+		return nil, nil, nil, errors.New("not a side chain transfer")
+	}
+	function, err := neatAbi.FunctionTypeFromId(tx.Data()[:4])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if function != neatAbi.TransferToSideChain {
+		return nil, nil, nil, errors.New("not a side chain transfer")
+	}
+
+	var args neatAbi.TransferToSideChainArgs
+	if err := neatAbi.ChainABI.UnpackMethodInputs(&args, neatAbi.TransferToSideChain.String(), tx.Data()[4:]); err != nil {
+		return nil, nil, nil, err
+	}
+	if args.TxHash != (common.Hash{}) || len(args.ProofData) != 0 {
+		return nil, nil, nil, errors.New("not a side chain transfer burn")
+	}
+
+	receipt, err := VerifyReceiptProof(proofData.Header.ReceiptHash, proofData.TxIndexs[0], proofData.ReceiptProofs[0])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if receipt.Status != ReceiptStatusSuccessful {
+		return nil, nil, nil, errors.New("the side chain transfer burn failed")
+	}
+
+	return &proofData, &tx, &args, nil
+}
+
+// SideChainTransferId identifies the burn among all the chains, it is settled
+// on the main chain and credited on the target side chain only once
+func SideChainTransferId(fromChainId string, txHash common.Hash) common.Hash {
+	enc, _ := rlp.EncodeToBytes([]interface{}{"TransferToSideChain", fromChainId, txHash})
+	return crypto.Keccak256Hash(enc)
+}
@@ -0,0 +1,99 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	neatAbi "github.com/neatio-net/neatio/neatabi/abi"
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/crypto"
+	"github.com/neatio-net/neatio/utilities/rlp"
+)
+
+func TestDecodeSideChainTransfer(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := NewEIP155Signer(big.NewInt(1001))
+
+	burnData, err := neatAbi.ChainABI.Pack(neatAbi.TransferToSideChain.String(), "sideb", common.Hash{}, []byte{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claimData, err := neatAbi.ChainABI.Pack(neatAbi.TransferToSideChain.String(), "sideb", common.HexToHash("0x01"), []byte{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain, _ := SignTx(NewTransaction(0, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil), signer, key)
+	burn, _ := SignTx(NewTransaction(1, neatAbi.NeatioSmartContractAddress, big.NewInt(100), 42000, big.NewInt(1), burnData), signer, key)
+	claim, _ := SignTx(NewTransaction(2, neatAbi.NeatioSmartContractAddress, big.NewInt(0), 42000, big.NewInt(1), claimData), signer, key)
+
+	receipts := Receipts{
+		&Receipt{Status: ReceiptStatusSuccessful, Logs: []*Log{}},
+		&Receipt{Status: ReceiptStatusSuccessful, Logs: []*Log{}},
+		&Receipt{Status: ReceiptStatusFailed, Logs: []*Log{}},
+	}
+	block := NewBlock(&Header{Number: big.NewInt(1)}, []*Transaction{plain, burn, claim}, nil, receipts)
+	proofData, err := NewTX3ProofData(block, receipts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proofData.TxIndexs) != 2 || len(proofData.ReceiptProofs) != 2 {
+		t.Fatalf("tx3 proof data of the transfers, got %v txs %v receipts want 2", len(proofData.TxIndexs), len(proofData.ReceiptProofs))
+	}
+
+	single := func(i int) []byte {
+		bs, err := rlp.EncodeToBytes(&TX3ProofData{
+			Header:   proofData.Header,
+			TxIndexs: proofData.TxIndexs[i : i+1],
+			TxProofs: proofData.TxProofs[i : i+1],
+
+			ReceiptProofs: proofData.ReceiptProofs[i : i+1],
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bs
+	}
+
+	_, tx, args, err := DecodeSideChainTransfer(single(0), burn.Hash())
+	if err != nil {
+		t.Fatalf("decode burn error %v", err)
+	}
+	if tx.Hash() != burn.Hash() || tx.Value().Cmp(big.NewInt(100)) != 0 || args.ToChainId != "sideb" {
+		t.Errorf("burn mismatch, got tx %x value %v to %v", tx.Hash(), tx.Value(), args.ToChainId)
+	}
+
+	if _, _, _, err := DecodeSideChainTransfer(single(0), claim.Hash()); err == nil {
+		t.Errorf("expected error for the proof of another tx")
+	}
+	if _, _, _, err := DecodeSideChainTransfer(single(1), claim.Hash()); err == nil {
+		t.Errorf("expected error for the claim of a transfer")
+	}
+	if _, _, _, err := DecodeSideChainTransfer(single(0)[:10], burn.Hash()); err == nil {
+		t.Errorf("expected error for truncated proof data")
+	}
+
+	// the burn is only settled with its successful receipt
+	noReceipt, _ := rlp.EncodeToBytes(&TX3ProofData{Header: proofData.Header, TxIndexs: proofData.TxIndexs[:1], TxProofs: proofData.TxProofs[:1]})
+	if _, _, _, err := DecodeSideChainTransfer(noReceipt, burn.Hash()); err == nil {
+		t.Errorf("expected error for the proof data without the receipt")
+	}
+	receipts[1].Status = ReceiptStatusFailed
+	block = NewBlock(&Header{Number: big.NewInt(1)}, []*Transaction{plain, burn, claim}, nil, receipts)
+	if proofData, err = NewTX3ProofData(block, receipts); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := DecodeSideChainTransfer(single(0), burn.Hash()); err == nil {
+		t.Errorf("expected error for the failed burn")
+	}
+}
+
+func TestSideChainTransferId(t *testing.T) {
+	txHash := common.HexToHash("0x01")
+	if SideChainTransferId("sidea", txHash) == SideChainTransferId("sideb", txHash) {
+		t.Errorf("transfers of different side chains share the id")
+	}
+	if SideChainTransferId("sidea", txHash) == SideChainTransferId("sidea", common.HexToHash("0x02")) {
+		t.Errorf("transfers of different txs share the id")
+	}
+}
@@ -96,6 +96,23 @@ func (op *LaunchSideChainsOp) String() string {
 		op.SideChainIds, len(op.NewPendingIdx), op.DeleteSideChainIds)
 }
 
+// DecommissionSideChain op
+type DecommissionSideChainOp struct {
+	ChainId   string
+	ProofData []byte
+}
+
+func (op *DecommissionSideChainOp) Conflict(op1 PendingOp) bool {
+	if op1, ok := op1.(*DecommissionSideChainOp); ok {
+		return op.ChainId == op1.ChainId
+	}
+	return false
+}
+
+func (op *DecommissionSideChainOp) String() string {
+	return fmt.Sprintf("DecommissionSideChainOp - ChainId: %s, ProofData Length: %v", op.ChainId, len(op.ProofData))
+}
+
 // SaveBlockToMainChain op
 type SaveDataToMainChainOp struct {
 	Data []byte
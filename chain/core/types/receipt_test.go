@@ -0,0 +1,64 @@
+package types
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/rlp"
+)
+
+func TestTypedReceiptEncoding(t *testing.T) {
+	for _, txType := range []byte{AccessListTxType, DynamicFeeTxType} {
+		receipt := &Receipt{
+			Type:              txType,
+			Status:            ReceiptStatusSuccessful,
+			CumulativeGasUsed: 21000,
+			Logs: []*Log{
+				{Address: common.Address{0x11}, Topics: []common.Hash{{0x22}}, Data: []byte{0x01, 0x00, 0xff}},
+			},
+		}
+		receipt.Bloom = CreateBloom(Receipts{receipt})
+
+		// consensus encoding
+		bin, err := receipt.MarshalBinary()
+		if err != nil {
+			t.Fatalf("type %d: encode error: %v", txType, err)
+		}
+		if bin[0] != txType {
+			t.Fatalf("type %d: wrong type prefix %x", txType, bin[0])
+		}
+		enc, err := rlp.EncodeToBytes(receipt)
+		if err != nil {
+			t.Fatalf("type %d: rlp encode error: %v", txType, err)
+		}
+		var dec Receipt
+		if err := rlp.DecodeBytes(enc, &dec); err != nil {
+			t.Fatalf("type %d: rlp decode error: %v", txType, err)
+		}
+		if dec.Type != receipt.Type || dec.Status != receipt.Status || dec.CumulativeGasUsed != receipt.CumulativeGasUsed || len(dec.Logs) != 1 {
+			t.Errorf("type %d: receipt mismatch after rlp round trip, got %+v", txType, dec)
+		}
+
+		// storage encoding keeps the type
+		stored, err := rlp.EncodeToBytes((*ReceiptForStorage)(receipt))
+		if err != nil {
+			t.Fatalf("type %d: storage encode error: %v", txType, err)
+		}
+		var fromStorage ReceiptForStorage
+		if err := rlp.DecodeBytes(stored, &fromStorage); err != nil {
+			t.Fatalf("type %d: storage decode error: %v", txType, err)
+		}
+		if fromStorage.Type != receipt.Type || !bytes.Equal(fromStorage.Logs[0].Data, receipt.Logs[0].Data) {
+			t.Errorf("type %d: receipt mismatch after storage round trip, got %+v", txType, fromStorage)
+		}
+	}
+
+	// legacy receipts keep the untyped encoding
+	legacy := &Receipt{Status: ReceiptStatusFailed, CumulativeGasUsed: 1}
+	legacyBin, _ := legacy.MarshalBinary()
+	legacyEnc, _ := rlp.EncodeToBytes(legacy)
+	if !bytes.Equal(legacyBin, legacyEnc) {
+		t.Errorf("legacy receipt encodings differ: %x != %x", legacyBin, legacyEnc)
+	}
+}
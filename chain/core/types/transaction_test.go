@@ -259,19 +259,19 @@ func TestSignTx(t *testing.T) {
 	signer := NewEIP155Signer(common.Big2)
 	address := common.HexToAddress("0e0ffd4c684b325be82f120a7938c7d938ff3dca")
 
-	d := txdata{
-		AccountNonce: 0,
-		Recipient:    &address,
-		Payload:      []byte(""),
-		Amount:       big.NewInt(1),
-		GasLimit:     100000,
-		Price:        big.NewInt(10000000000),
-		V:            new(big.Int),
-		R:            new(big.Int),
-		S:            new(big.Int),
-	}
-
-	tx := &Transaction{data: d}
+	d := LegacyTx{
+		Nonce:    0,
+		To:       &address,
+		Data:     []byte(""),
+		Value:    big.NewInt(1),
+		Gas:      100000,
+		GasPrice: big.NewInt(10000000000),
+		V:        new(big.Int),
+		R:        new(big.Int),
+		S:        new(big.Int),
+	}
+
+	tx := &Transaction{inner: &d}
 	t.Logf("tx before sign  %v\n", tx)
 	signTx, err := SignTx(tx, signer, key)
 	if err != nil {
@@ -279,7 +279,7 @@ func TestSignTx(t *testing.T) {
 	}
 	t.Logf("tx after sign  %v\n", signTx)
 
-	enc, err := rlp.EncodeToBytes(&signTx.data)
+	enc, err := rlp.EncodeToBytes(signTx.inner)
 	if err != nil {
 		t.Logf("rlp encode to byte err %v\n", err)
 	}
@@ -302,20 +302,20 @@ func TestDelegateTx(t *testing.T) {
 		t.Errorf("could not pack data, err %v\n", err)
 	}
 
-	d := txdata{
-		AccountNonce: 1,
-		Recipient:    &address,
+	d := LegacyTx{
+		Nonce: 1,
+		To:    &address,
 
-		Payload:  input,
-		Amount:   big.NewInt(1000000000000000000),
-		GasLimit: 50000,
-		Price:    big.NewInt(10000000000),
+		Data:     input,
+		Value:    big.NewInt(1000000000000000000),
+		Gas:      50000,
+		GasPrice: big.NewInt(10000000000),
 		V:        new(big.Int),
 		R:        new(big.Int),
 		S:        new(big.Int),
 	}
 
-	tx := &Transaction{data: d}
+	tx := &Transaction{inner: &d}
 	t.Logf("tx before sign  %v\n", tx)
 	signTx, err := SignTx(tx, signer, key)
 	if err != nil {
@@ -323,7 +323,7 @@ func TestDelegateTx(t *testing.T) {
 	}
 	t.Logf("tx after sign  %v\n", signTx)
 
-	enc, err := rlp.EncodeToBytes(&signTx.data)
+	enc, err := rlp.EncodeToBytes(signTx.inner)
 	if err != nil {
 		t.Logf("rlp encode to byte err %v\n", err)
 	}
@@ -344,3 +344,193 @@ func TestDecodeTx(t *testing.T) {
 	fmt.Printf("decode tx %v\n", tx.String())
 
 }
+
+func TestAccessListTxEncode(t *testing.T) {
+	key, addr := defaultTestKey()
+	signer := NewEIP2930Signer(big.NewInt(5))
+
+	to := common.HexToAddress("b94f5374fce5edbc8e2a8697c15331677e6ebf0b")
+	tx, err := SignTx(NewTx(&AccessListTx{
+		ChainID:  big.NewInt(5),
+		Nonce:    3,
+		GasPrice: big.NewInt(1),
+		Gas:      25000,
+		To:       &to,
+		Value:    big.NewInt(10),
+		Data:     common.FromHex("5544"),
+		AccessList: AccessList{
+			{Address: to, StorageKeys: []common.Hash{{0}, {1}}},
+		},
+	}), signer, key)
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+
+	// the canonical encoding is the type byte followed by the rlp payload
+	bin, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	if bin[0] != AccessListTxType {
+		t.Fatalf("wrong type prefix %x", bin[0])
+	}
+	var parsed Transaction
+	if err := parsed.UnmarshalBinary(bin); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if parsed.Hash() != tx.Hash() {
+		t.Errorf("hash mismatch after binary round trip, want %x, got %x", tx.Hash(), parsed.Hash())
+	}
+	if parsed.Type() != AccessListTxType || len(parsed.AccessList()) != 1 || parsed.AccessList().StorageKeys() != 2 {
+		t.Errorf("access list lost in binary round trip: %v", parsed.AccessList())
+	}
+	from, err := Sender(signer, &parsed)
+	if err != nil || from != addr {
+		t.Errorf("wrong sender, want %x, got %x (%v)", addr, from, err)
+	}
+
+	// inside blocks the typed transaction is wrapped in an rlp string
+	enc, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatalf("rlp encode error: %v", err)
+	}
+	decoded, err := decodeTx(enc)
+	if err != nil {
+		t.Fatalf("rlp decode error: %v", err)
+	}
+	if decoded.Hash() != tx.Hash() {
+		t.Errorf("hash mismatch after rlp round trip, want %x, got %x", tx.Hash(), decoded.Hash())
+	}
+
+	// older signers must refuse typed transactions
+	if _, err := Sender(NewEIP155Signer(big.NewInt(5)), &parsed); err != ErrTxTypeNotSupported {
+		t.Errorf("expected %v from the EIP155 signer, got %v", ErrTxTypeNotSupported, err)
+	}
+}
+
+func TestAccessListTxJSON(t *testing.T) {
+	key, _ := defaultTestKey()
+	signer := NewEIP2930Signer(big.NewInt(5))
+
+	tx, err := SignTx(NewTx(&AccessListTx{
+		ChainID:    big.NewInt(5),
+		Nonce:      1,
+		GasPrice:   big.NewInt(2),
+		Gas:        50000,
+		Data:       []byte("abcdef"),
+		AccessList: AccessList{{Address: common.Address{1}, StorageKeys: []common.Hash{{2}}}},
+	}), signer, key)
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	data, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	var parsedTx *Transaction
+	if err := json.Unmarshal(data, &parsedTx); err != nil {
+		t.Fatalf("json.Unmarshal failed: %v", err)
+	}
+	if tx.Hash() != parsedTx.Hash() {
+		t.Errorf("parsed tx differs from original tx, want %v, got %v", tx, parsedTx)
+	}
+	if parsedTx.ChainId().Cmp(big.NewInt(5)) != 0 {
+		t.Errorf("invalid chain id, want 5, got %d", parsedTx.ChainId())
+	}
+}
+
+func TestDynamicFeeTxEncode(t *testing.T) {
+	key, addr := defaultTestKey()
+	signer := NewLondonSigner(big.NewInt(5))
+
+	to := common.HexToAddress("b94f5374fce5edbc8e2a8697c15331677e6ebf0b")
+	tx, err := SignTx(NewTx(&DynamicFeeTx{
+		ChainID:   big.NewInt(5),
+		Nonce:     3,
+		GasTipCap: big.NewInt(2),
+		GasFeeCap: big.NewInt(10),
+		Gas:       25000,
+		To:        &to,
+		Value:     big.NewInt(10),
+		Data:      common.FromHex("5544"),
+	}), signer, key)
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+
+	bin, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	if bin[0] != DynamicFeeTxType {
+		t.Fatalf("wrong type prefix %x", bin[0])
+	}
+	var parsed Transaction
+	if err := parsed.UnmarshalBinary(bin); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if parsed.Hash() != tx.Hash() {
+		t.Errorf("hash mismatch after binary round trip, want %x, got %x", tx.Hash(), parsed.Hash())
+	}
+	if parsed.GasTipCap().Cmp(big.NewInt(2)) != 0 || parsed.GasFeeCap().Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("fee caps lost in binary round trip, tip %v, fee cap %v", parsed.GasTipCap(), parsed.GasFeeCap())
+	}
+	from, err := Sender(signer, &parsed)
+	if err != nil || from != addr {
+		t.Errorf("wrong sender, want %x, got %x (%v)", addr, from, err)
+	}
+
+	// the tip is capped by the fee cap above the base fee
+	for _, test := range []struct {
+		baseFee *big.Int
+		tip     int64
+		err     error
+	}{
+		{nil, 2, nil},
+		{big.NewInt(5), 2, nil},
+		{big.NewInt(9), 1, nil},
+		{big.NewInt(11), -1, ErrGasFeeCapTooLow},
+	} {
+		tip, err := tx.EffectiveGasTip(test.baseFee)
+		if tip.Int64() != test.tip || err != test.err {
+			t.Errorf("base fee %v: effective tip mismatch, want %d (%v), got %v (%v)", test.baseFee, test.tip, test.err, tip, err)
+		}
+	}
+
+	// the Berlin signer must refuse dynamic fee transactions
+	if _, err := Sender(NewEIP2930Signer(big.NewInt(5)), &parsed); err != ErrTxTypeNotSupported {
+		t.Errorf("expected %v from the EIP2930 signer, got %v", ErrTxTypeNotSupported, err)
+	}
+}
+
+func TestDynamicFeeTxJSON(t *testing.T) {
+	key, _ := defaultTestKey()
+	signer := NewLondonSigner(big.NewInt(5))
+
+	tx, err := SignTx(NewTx(&DynamicFeeTx{
+		ChainID:    big.NewInt(5),
+		Nonce:      1,
+		GasTipCap:  big.NewInt(1),
+		GasFeeCap:  big.NewInt(3),
+		Gas:        50000,
+		Data:       []byte("abcdef"),
+		AccessList: AccessList{{Address: common.Address{1}, StorageKeys: []common.Hash{{2}}}},
+	}), signer, key)
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	data, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	var parsedTx *Transaction
+	if err := json.Unmarshal(data, &parsedTx); err != nil {
+		t.Fatalf("json.Unmarshal failed: %v", err)
+	}
+	if tx.Hash() != parsedTx.Hash() {
+		t.Errorf("parsed tx differs from original tx, want %v, got %v", tx, parsedTx)
+	}
+	if parsedTx.Type() != DynamicFeeTxType || parsedTx.GasFeeCap().Cmp(big.NewInt(3)) != 0 {
+		t.Errorf("invalid parsed tx, type %d, fee cap %v", parsedTx.Type(), parsedTx.GasFeeCap())
+	}
+}
@@ -8,11 +8,15 @@ import (
 
 	"github.com/neatio-net/neatio/utilities/crypto/blake2b"
 
+	"github.com/neatio-net/neatio/chain/accounts/abi"
+	"github.com/neatio-net/neatio/chain/core/types"
+	neatAbi "github.com/neatio-net/neatio/neatabi/abi"
 	"github.com/neatio-net/neatio/params"
 	"github.com/neatio-net/neatio/utilities/common"
 	"github.com/neatio-net/neatio/utilities/common/math"
 	"github.com/neatio-net/neatio/utilities/crypto"
 	"github.com/neatio-net/neatio/utilities/crypto/bn256"
+	"github.com/neatio-net/neatio/utilities/rlp"
 	"golang.org/x/crypto/ripemd160"
 )
 
@@ -51,6 +55,42 @@ var PrecompiledContractsIstanbul = map[common.Address]PrecompiledContract{
 	common.BytesToAddress([]byte{9}): &blake2F{},
 }
 
+var PrecompiledContractsNeatFork = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{1}): &ecrecover{},
+	common.BytesToAddress([]byte{2}): &sha256hash{},
+	common.BytesToAddress([]byte{3}): &ripemd160hash{},
+	common.BytesToAddress([]byte{4}): &dataCopy{},
+	common.BytesToAddress([]byte{5}): &bigModExp{},
+	common.BytesToAddress([]byte{6}): &bn256AddIstanbul{},
+	common.BytesToAddress([]byte{7}): &bn256ScalarMulIstanbul{},
+	common.BytesToAddress([]byte{8}): &bn256PairingIstanbul{},
+	common.BytesToAddress([]byte{9}): &blake2F{},
+
+	common.BytesToAddress([]byte{0x10, 0x02}): &sideChainHeader{},
+	common.BytesToAddress([]byte{0x10, 0x03}): &receiptProof{},
+	common.BytesToAddress([]byte{0x10, 0x04}): &staking{},
+}
+
+// ActivePrecompiles returns the addresses of the precompiles enabled with the
+// given rules, which are warm from the start of a Berlin transaction.
+func ActivePrecompiles(rules params.Rules) []common.Address {
+	precompiles := PrecompiledContractsHomestead
+	if rules.IsByzantium {
+		precompiles = PrecompiledContractsByzantium
+	}
+	if rules.IsIstanbul {
+		precompiles = PrecompiledContractsIstanbul
+	}
+	if rules.IsNeatFork {
+		precompiles = PrecompiledContractsNeatFork
+	}
+	addrs := make([]common.Address, 0, len(precompiles))
+	for addr := range precompiles {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
 func RunPrecompiledContract(p PrecompiledContract, input []byte, contract *Contract) (ret []byte, err error) {
 	gas := p.RequiredGas(input)
 	if contract.UseGas(gas) {
@@ -59,6 +99,36 @@ func RunPrecompiledContract(p PrecompiledContract, input []byte, contract *Contr
 	return nil, ErrOutOfGas
 }
 
+// chainPrecompiledContract reads the chains committed in the state through the
+// evm context
+type chainPrecompiledContract interface {
+	PrecompiledContract
+	RunWithContext(ctx *Context, db StateDB, input []byte) ([]byte, error)
+}
+
+func runChainPrecompiledContract(p chainPrecompiledContract, ctx *Context, db StateDB, input []byte, contract *Contract) (ret []byte, err error) {
+	gas := p.RequiredGas(input)
+	if contract.UseGas(gas) {
+		return p.RunWithContext(ctx, db, input)
+	}
+	return nil, ErrOutOfGas
+}
+
+// callerPrecompiledContract acts on behalf of the caller of the precompile and
+// may move the value sent with the call
+type callerPrecompiledContract interface {
+	PrecompiledContract
+	RunWithCaller(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, error)
+}
+
+func runCallerPrecompiledContract(p callerPrecompiledContract, evm *EVM, input []byte, contract *Contract, readOnly bool) (ret []byte, err error) {
+	gas := p.RequiredGas(input)
+	if contract.UseGas(gas) {
+		return p.RunWithCaller(evm, contract, input, readOnly)
+	}
+	return nil, ErrOutOfGas
+}
+
 type ecrecover struct{}
 
 func (c *ecrecover) RequiredGas(input []byte) uint64 {
@@ -418,3 +488,227 @@ func (c *blake2F) Run(input []byte) ([]byte, error) {
 	}
 	return output, nil
 }
+
+var (
+	errNoChainContext = errors.New("no chain context for the precompiled contract")
+	errHeaderNotSaved = errors.New("header not saved to the main chain")
+)
+
+// anchorStateDB is the state recording the side chain blocks saved to the main chain
+type anchorStateDB interface {
+	GetSideChainAnchor(chainId string, number uint64) common.Hash
+	GetSideChainReceiptHash(chainId string, number uint64) common.Hash
+}
+
+// sideChainHeader verifies the header of a side chain is saved to the main
+// chain by SaveDataToMainChain, the input is the rlp encoded header. It returns
+// the keccak256 of the chain id, the number, the hash, the state root, the tx
+// root and the receipt root of the header, each in a 32 bytes word
+type sideChainHeader struct{}
+
+func (c *sideChainHeader) RequiredGas(input []byte) uint64 {
+	return params.SideChainHeaderVerifyGas
+}
+
+func (c *sideChainHeader) Run(input []byte) ([]byte, error) {
+	return nil, errNoChainContext
+}
+
+func (c *sideChainHeader) RunWithContext(ctx *Context, db StateDB, input []byte) ([]byte, error) {
+	anchors, ok := db.(anchorStateDB)
+	if ctx.HeaderChainId == nil || !ok {
+		return nil, errNoChainContext
+	}
+
+	var header types.Header
+	if err := rlp.DecodeBytes(input, &header); err != nil {
+		return nil, err
+	}
+	chainId, err := ctx.HeaderChainId(&header)
+	if err != nil {
+		return nil, err
+	}
+	if anchors.GetSideChainAnchor(chainId, header.Number.Uint64()) != header.Hash() {
+		return nil, errHeaderNotSaved
+	}
+
+	output := make([]byte, 0, 6*32)
+	output = append(output, crypto.Keccak256([]byte(chainId))...)
+	output = append(output, common.LeftPadBytes(header.Number.Bytes(), 32)...)
+	output = append(output, header.Hash().Bytes()...)
+	output = append(output, header.Root.Bytes()...)
+	output = append(output, header.TxHash.Bytes()...)
+	output = append(output, header.ReceiptHash.Bytes()...)
+	return output, nil
+}
+
+// receiptProof verifies the receipt is in the receipt trie of a side chain
+// block saved to the main chain, the input is the rlp encoded receiptProofInput.
+// It returns the log at the index of the receipt abi encoded as
+// (address, bytes32[] topics, bytes data)
+type receiptProof struct{}
+
+type receiptProofInput struct {
+	ChainId      string
+	Number       uint64
+	ReceiptIndex uint
+	LogIndex     uint
+	Proof        *types.BSKeyValueSet
+}
+
+var (
+	errReceiptProofInputLength = errors.New("invalid input length")
+	errReceiptProofLogIndex    = errors.New("log index out of range")
+
+	receiptLogOutputs = abi.Arguments{
+		{Type: mustNewABIType("address")},
+		{Type: mustNewABIType("bytes32[]")},
+		{Type: mustNewABIType("bytes")},
+	}
+)
+
+func (c *receiptProof) RequiredGas(input []byte) uint64 {
+	return uint64(len(input)+31)/32*params.ReceiptProofPerWordGas + params.ReceiptProofBaseGas
+}
+
+func (c *receiptProof) Run(input []byte) ([]byte, error) {
+	return nil, errNoChainContext
+}
+
+func (c *receiptProof) RunWithContext(ctx *Context, db StateDB, input []byte) ([]byte, error) {
+	anchors, ok := db.(anchorStateDB)
+	if !ok {
+		return nil, errNoChainContext
+	}
+
+	var in receiptProofInput
+	if err := rlp.DecodeBytes(input, &in); err != nil {
+		return nil, err
+	}
+	if in.Proof == nil {
+		return nil, errReceiptProofInputLength
+	}
+
+	// the receipts are proved against the block saved by SaveDataToMainChain only
+	root := anchors.GetSideChainReceiptHash(in.ChainId, in.Number)
+	if root == (common.Hash{}) {
+		return nil, errHeaderNotSaved
+	}
+	receipt, err := types.VerifyReceiptProof(root, in.ReceiptIndex, in.Proof)
+	if err != nil {
+		return nil, err
+	}
+	if int(in.LogIndex) >= len(receipt.Logs) {
+		return nil, errReceiptProofLogIndex
+	}
+
+	log := receipt.Logs[in.LogIndex]
+	topics := make([][32]byte, len(log.Topics))
+	for i, topic := range log.Topics {
+		topics[i] = topic
+	}
+	return receiptLogOutputs.Pack(log.Address, topics, log.Data)
+}
+
+// staking lets the caller delegate to the candidates, undelegate and withdraw
+// its rewards like the delegation transactions, the caller is the delegator.
+// The input is abi encoded with the neatAbi.StakingABI
+type staking struct{}
+
+var (
+	errStakingMethod       = errors.New("unknown staking method")
+	errStakingNotPayable   = errors.New("staking method is not payable")
+	errStakingDelegateCall = errors.New("staking precompile must be called directly")
+)
+
+func (c *staking) RequiredGas(input []byte) uint64 {
+	if len(input) >= 4 {
+		if method, err := neatAbi.StakingABI.MethodById(input[:4]); err == nil && !method.Const {
+			return params.StakingUpdateGas
+		}
+	}
+	return params.StakingQueryGas
+}
+
+func (c *staking) Run(input []byte) ([]byte, error) {
+	return nil, errNoChainContext
+}
+
+func (c *staking) RunWithCaller(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, error) {
+	if evm.Staking == nil {
+		return nil, errNoChainContext
+	}
+	if len(input) < 4 {
+		return nil, errStakingMethod
+	}
+	method, err := neatAbi.StakingABI.MethodById(input[:4])
+	if err != nil {
+		return nil, errStakingMethod
+	}
+	if !method.Const {
+		if readOnly {
+			return nil, ErrWriteProtection
+		}
+		// the delegator must be the caller, not the contract running the code
+		if contract.CodeAddr == nil || *contract.CodeAddr != contract.Address() {
+			return nil, errStakingDelegateCall
+		}
+	}
+	if method.Name != "delegate" && contract.Value().Sign() != 0 {
+		return nil, errStakingNotPayable
+	}
+
+	delegator := contract.Caller()
+	switch method.Name {
+	case "delegate":
+		var args neatAbi.DelegateArgs
+		if err := method.Inputs.Unpack(&args, input[4:]); err != nil {
+			return nil, err
+		}
+		// the value was sent to the precompile, it is given back to be taken
+		// from the delegator like the value of a delegation transaction
+		amount := contract.Value()
+		evm.StateDB.SubBalance(contract.Address(), amount)
+		evm.StateDB.AddBalance(delegator, amount)
+		return nil, evm.Staking.Delegate(delegator, args.Candidate, amount)
+
+	case "undelegate":
+		var args neatAbi.UnDelegateArgs
+		if err := method.Inputs.Unpack(&args, input[4:]); err != nil {
+			return nil, err
+		}
+		return nil, evm.Staking.UnDelegate(delegator, args.Candidate, args.Amount)
+
+	case "withdrawReward":
+		var args neatAbi.WithdrawRewardArgs
+		if err := method.Inputs.Unpack(&args, input[4:]); err != nil {
+			return nil, err
+		}
+		return nil, evm.Staking.WithdrawReward(delegator, args.DelegateAddress, args.Amount)
+
+	case "getDelegation":
+		var args neatAbi.GetDelegationArgs
+		if err := method.Inputs.Unpack(&args, input[4:]); err != nil {
+			return nil, err
+		}
+		proxied, depositProxied, pendingRefund, reward := evm.Staking.GetDelegation(args.Delegator, args.Candidate)
+		return method.Outputs.Pack(proxied, depositProxied, pendingRefund, reward)
+
+	case "getCandidate":
+		var args neatAbi.GetCandidateArgs
+		if err := method.Inputs.Unpack(&args, input[4:]); err != nil {
+			return nil, err
+		}
+		isCandidate, commission, proxied, depositProxied := evm.Staking.GetCandidate(args.Candidate)
+		return method.Outputs.Pack(isCandidate, commission, proxied, depositProxied)
+	}
+	return nil, errStakingMethod
+}
+
+func mustNewABIType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
@@ -7,7 +7,14 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/neatio-net/neatio/chain/core/rawdb"
+	"github.com/neatio-net/neatio/chain/core/state"
+	"github.com/neatio-net/neatio/chain/core/types"
+	neatAbi "github.com/neatio-net/neatio/neatabi/abi"
+	"github.com/neatio-net/neatio/params"
 	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/crypto"
+	"github.com/neatio-net/neatio/utilities/rlp"
 )
 
 type precompiledTest struct {
@@ -619,3 +626,205 @@ func TestPrecompiledEcrecover(t *testing.T) {
 	}
 
 }
+
+func TestPrecompiledReceiptProof(t *testing.T) {
+	logs := []*types.Log{
+		{Address: common.HexToAddress("0x0aaa"), Topics: []common.Hash{{1}, {2}}, Data: []byte("payload")},
+	}
+	receipts := types.Receipts{
+		&types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{}},
+		&types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: logs},
+	}
+	proofData, err := types.NewCrossChainMessageProofData(&types.Header{Number: big.NewInt(1)}, receipts, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := types.DeriveSha(receipts)
+
+	enc, _ := rlp.EncodeToBytes(receiptProofInput{ChainId: "sidetest", Number: 1, ReceiptIndex: 1, LogIndex: 0, Proof: proofData.ReceiptProof})
+	p := PrecompiledContractsNeatFork[common.BytesToAddress([]byte{0x10, 0x03})].(chainPrecompiledContract)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	if _, err := p.RunWithContext(&Context{}, statedb, enc); err != errHeaderNotSaved {
+		t.Fatalf("expected %v, got %v", errHeaderNotSaved, err)
+	}
+
+	statedb.SetSideChainAnchor("sidetest", 1, common.Hash{1}, root)
+	res, err := p.RunWithContext(&Context{}, statedb, enc)
+	if err != nil {
+		t.Fatalf("verify receipt proof error %v", err)
+	}
+
+	values, err := receiptLogOutputs.UnpackValues(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, topics, data := values[0].(common.Address), values[1].([][32]byte), values[2].([]byte)
+	if address != logs[0].Address || len(topics) != 2 || topics[1] != logs[0].Topics[1] || !bytes.Equal(data, logs[0].Data) {
+		t.Errorf("log mismatch, got %x %x %x", address, topics, data)
+	}
+
+	// the root saved for the block is used, not one given by the caller
+	statedb.SetSideChainAnchor("sidetest", 1, common.Hash{1}, common.Hash{2})
+	if _, err := p.RunWithContext(&Context{}, statedb, enc); err == nil {
+		t.Errorf("expected error for another receipt root")
+	}
+}
+
+func TestPrecompiledSideChainHeader(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(7), Root: common.Hash{1}, ReceiptHash: common.Hash{2}, Difficulty: big.NewInt(1), Time: big.NewInt(1)}
+	input, _ := rlp.EncodeToBytes(header)
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	p := PrecompiledContractsNeatFork[common.BytesToAddress([]byte{0x10, 0x02})].(chainPrecompiledContract)
+	if _, err := p.RunWithContext(&Context{}, statedb, input); err != errNoChainContext {
+		t.Fatalf("expected %v, got %v", errNoChainContext, err)
+	}
+
+	ctx := &Context{HeaderChainId: func(h *types.Header) (string, error) {
+		return "sidetest", nil
+	}}
+	if _, err := p.RunWithContext(ctx, statedb, input); err != errHeaderNotSaved {
+		t.Fatalf("expected %v, got %v", errHeaderNotSaved, err)
+	}
+	statedb.SetSideChainAnchor("sidetest", 7, header.Hash(), header.ReceiptHash)
+	res, err := p.RunWithContext(ctx, statedb, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 6*32 {
+		t.Fatalf("output length %d", len(res))
+	}
+	if !bytes.Equal(res[:32], crypto.Keccak256([]byte("sidetest"))) || new(big.Int).SetBytes(res[32:64]).Uint64() != 7 ||
+		!bytes.Equal(res[64:96], header.Hash().Bytes()) || !bytes.Equal(res[96:128], header.Root.Bytes()) || !bytes.Equal(res[160:], header.ReceiptHash.Bytes()) {
+		t.Errorf("output mismatch %x", res)
+	}
+}
+
+func TestNeatForkPrecompiles(t *testing.T) {
+	config := *params.TestChainConfig
+	config.NeatForkBlock = big.NewInt(10)
+
+	for _, addr := range []common.Address{
+		common.BytesToAddress([]byte{0x10, 0x02}),
+		common.BytesToAddress([]byte{0x10, 0x03}),
+		common.BytesToAddress([]byte{0x10, 0x04}),
+	} {
+		for _, c := range []struct {
+			number *big.Int
+			active bool
+		}{{big.NewInt(9), false}, {big.NewInt(10), true}} {
+			active := false
+			for _, a := range ActivePrecompiles(config.Rules(c.number)) {
+				active = active || a == addr
+			}
+			if active != c.active {
+				t.Errorf("precompile %x at block %v: active %v, want %v", addr, c.number, active, c.active)
+			}
+		}
+	}
+}
+
+type testStakingHandler struct {
+	delegator, candidate common.Address
+	amount               *big.Int
+}
+
+func (h *testStakingHandler) Delegate(delegator, candidate common.Address, amount *big.Int) error {
+	h.delegator, h.candidate, h.amount = delegator, candidate, amount
+	return nil
+}
+
+func (h *testStakingHandler) UnDelegate(delegator, candidate common.Address, amount *big.Int) error {
+	return fmt.Errorf("no delegation")
+}
+
+func (h *testStakingHandler) WithdrawReward(delegator, candidate common.Address, amount *big.Int) error {
+	return nil
+}
+
+func (h *testStakingHandler) GetDelegation(delegator, candidate common.Address) (*big.Int, *big.Int, *big.Int, *big.Int) {
+	return big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4)
+}
+
+func (h *testStakingHandler) GetCandidate(candidate common.Address) (bool, uint8, *big.Int, *big.Int) {
+	return true, 10, big.NewInt(5), big.NewInt(6)
+}
+
+func TestPrecompiledStaking(t *testing.T) {
+	var (
+		statedb, _ = state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		handler    = new(testStakingHandler)
+		addr       = common.BytesToAddress([]byte{0x10, 0x04})
+		caller     = common.HexToAddress("0xc0ffee")
+		candidate  = common.HexToAddress("0xca11")
+	)
+	statedb.AddBalance(caller, big.NewInt(1000))
+
+	ctx := Context{
+		CanTransfer: func(db StateDB, addr common.Address, amount *big.Int) bool {
+			return db.GetBalance(addr).Cmp(amount) >= 0
+		},
+		Transfer: func(db StateDB, sender, recipient common.Address, amount *big.Int) {
+			db.SubBalance(sender, amount)
+			db.AddBalance(recipient, amount)
+		},
+		BlockNumber: big.NewInt(1),
+		Staking:     handler,
+	}
+	evm := NewEVM(ctx, statedb, params.TestChainConfig, Config{})
+
+	input, _ := neatAbi.StakingABI.Pack("delegate", candidate)
+	if _, _, err := evm.StaticCall(AccountRef(caller), addr, input, 100000); err != ErrWriteProtection {
+		t.Fatalf("static delegate: expected %v, got %v", ErrWriteProtection, err)
+	}
+	if _, _, err := evm.Call(AccountRef(caller), addr, input, 100000, big.NewInt(100)); err != nil {
+		t.Fatalf("delegate failed: %v", err)
+	}
+	if handler.delegator != caller || handler.candidate != candidate || handler.amount.Cmp(big.NewInt(100)) != 0 {
+		t.Errorf("delegate mismatch: have %x %x %v", handler.delegator, handler.candidate, handler.amount)
+	}
+	// the value is taken by the handler, the precompile must not keep it
+	if statedb.GetBalance(addr).Sign() != 0 || statedb.GetBalance(caller).Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("balance mismatch: precompile %v, caller %v", statedb.GetBalance(addr), statedb.GetBalance(caller))
+	}
+
+	input, _ = neatAbi.StakingABI.Pack("undelegate", candidate, big.NewInt(1))
+	if _, _, err := evm.Call(AccountRef(caller), addr, input, 100000, big.NewInt(1)); err != errStakingNotPayable {
+		t.Errorf("payable undelegate: expected %v, got %v", errStakingNotPayable, err)
+	}
+	if _, _, err := evm.Call(AccountRef(caller), addr, input, 100000, new(big.Int)); err == nil {
+		t.Errorf("expected the handler error")
+	}
+
+	input, _ = neatAbi.StakingABI.Pack("getDelegation", caller, candidate)
+	res, _, err := evm.StaticCall(AccountRef(caller), addr, input, 100000)
+	if err != nil {
+		t.Fatalf("getDelegation failed: %v", err)
+	}
+	var delegation struct {
+		ProxiedBalance        *big.Int
+		DepositProxiedBalance *big.Int
+		PendingRefundBalance  *big.Int
+		RewardBalance         *big.Int
+	}
+	if err := neatAbi.StakingABI.Unpack(&delegation, "getDelegation", res); err != nil {
+		t.Fatal(err)
+	}
+	if delegation.ProxiedBalance.Int64() != 1 || delegation.DepositProxiedBalance.Int64() != 2 ||
+		delegation.PendingRefundBalance.Int64() != 3 || delegation.RewardBalance.Int64() != 4 {
+		t.Errorf("getDelegation mismatch: %+v", delegation)
+	}
+
+	input, _ = neatAbi.StakingABI.Pack("getCandidate", candidate)
+	if res, _, err = evm.StaticCall(AccountRef(caller), addr, input, 100000); err != nil {
+		t.Fatalf("getCandidate failed: %v", err)
+	}
+	if len(res) != 4*32 || res[31] != 1 || res[63] != 10 || res[95] != 5 || res[127] != 6 {
+		t.Errorf("getCandidate mismatch: %x", res)
+	}
+
+	evm.Staking = nil
+	if _, _, err := evm.StaticCall(AccountRef(caller), addr, input, 100000); err != errNoChainContext {
+		t.Errorf("expected %v without a handler, got %v", errNoChainContext, err)
+	}
+}
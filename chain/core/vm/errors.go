@@ -11,9 +11,11 @@ var (
 	ErrContractAddressCollision = errors.New("contract address collision")
 	ErrNoCompatibleInterpreter  = errors.New("no compatible interpreter")
 
-	ErrWriteProtection       = errors.New("write protection")
-	ErrReturnDataOutOfBounds = errors.New("return data out of bounds")
-	ErrExecutionReverted     = errors.New("execution reverted")
-	ErrMaxCodeSizeExceeded   = errors.New("max code size exceeded")
-	ErrInvalidJump           = errors.New("invalid jump destination")
+	ErrWriteProtection         = errors.New("write protection")
+	ErrReturnDataOutOfBounds   = errors.New("return data out of bounds")
+	ErrExecutionReverted       = errors.New("execution reverted")
+	ErrMaxCodeSizeExceeded     = errors.New("max code size exceeded")
+	ErrMaxInitCodeSizeExceeded = errors.New("max initcode size exceeded")
+	ErrInvalidCode             = errors.New("invalid code: must not begin with 0xef")
+	ErrInvalidJump             = errors.New("invalid jump destination")
 )
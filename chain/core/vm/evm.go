@@ -5,6 +5,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/neatio-net/neatio/chain/core/types"
 	"github.com/neatio-net/neatio/params"
 	"github.com/neatio-net/neatio/utilities/common"
 	"github.com/neatio-net/neatio/utilities/crypto"
@@ -18,6 +19,20 @@ type (
 	TransferFunc func(StateDB, common.Address, common.Address, *big.Int)
 
 	GetHashFunc func(uint64) common.Hash
+
+	// HeaderChainIdFunc returns the chain id the header is signed for
+	HeaderChainIdFunc func(*types.Header) (string, error)
+
+	// StakingHandler applies the operations of the staking precompile to the
+	// state with the delegation rules of the chain
+	StakingHandler interface {
+		Delegate(delegator, candidate common.Address, amount *big.Int) error
+		UnDelegate(delegator, candidate common.Address, amount *big.Int) error
+		WithdrawReward(delegator, candidate common.Address, amount *big.Int) error
+
+		GetDelegation(delegator, candidate common.Address) (proxied, depositProxied, pendingRefund, reward *big.Int)
+		GetCandidate(candidate common.Address) (isCandidate bool, commission uint8, proxied, depositProxied *big.Int)
+	}
 )
 
 func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, error) {
@@ -29,7 +44,16 @@ func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, err
 		if evm.chainRules.IsIstanbul {
 			precompiles = PrecompiledContractsIstanbul
 		}
+		if evm.chainRules.IsNeatFork {
+			precompiles = PrecompiledContractsNeatFork
+		}
 		if p := precompiles[*contract.CodeAddr]; p != nil {
+			if cp, ok := p.(chainPrecompiledContract); ok {
+				return runChainPrecompiledContract(cp, &evm.Context, evm.StateDB, input, contract)
+			}
+			if cp, ok := p.(callerPrecompiledContract); ok {
+				return runCallerPrecompiledContract(cp, evm, input, contract, readOnly)
+			}
 			return RunPrecompiledContract(p, input, contract)
 		}
 	}
@@ -55,6 +79,10 @@ type Context struct {
 
 	GetHash GetHashFunc
 
+	HeaderChainId HeaderChainIdFunc
+
+	Staking StakingHandler
+
 	Origin   common.Address
 	GasPrice *big.Int
 
@@ -63,6 +91,7 @@ type Context struct {
 	BlockNumber *big.Int
 	Time        *big.Int
 	Difficulty  *big.Int
+	BaseFee     *big.Int
 }
 
 type EVM struct {
@@ -144,6 +173,9 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 		if evm.chainRules.IsIstanbul {
 			precompiles = PrecompiledContractsIstanbul
 		}
+		if evm.chainRules.IsNeatFork {
+			precompiles = PrecompiledContractsNeatFork
+		}
 		if precompiles[addr] == nil && evm.chainRules.IsEIP158 && value.Sign() == 0 {
 
 			if evm.vmConfig.Debug && evm.depth == 0 {
@@ -288,6 +320,10 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	}
 	nonce := evm.StateDB.GetNonce(caller.Address())
 	evm.StateDB.SetNonce(caller.Address(), nonce+1)
+	// The address is warm even if the creation fails
+	if evm.chainRules.IsBerlin {
+		evm.StateDB.AddAddressToAccessList(address)
+	}
 
 	contractHash := evm.StateDB.GetCodeHash(address)
 	if evm.StateDB.GetNonce(address) != 0 || (contractHash != (common.Hash{}) && contractHash != emptyCodeHash) {
@@ -317,6 +353,11 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 
 	maxCodeSizeExceeded := evm.chainRules.IsEIP158 && len(ret) > params.MaxCodeSize
 
+	// Reject code starting with 0xEF after London, see EIP-3541
+	if err == nil && !maxCodeSizeExceeded && len(ret) >= 1 && ret[0] == 0xEF && evm.chainRules.IsLondon {
+		err = ErrInvalidCode
+	}
+
 	if err == nil && !maxCodeSizeExceeded {
 		createDataGas := uint64(len(ret)) * params.CreateDataGas
 		if contract.UseGas(createDataGas) {
@@ -355,3 +396,5 @@ func (evm *EVM) Create2(caller ContractRef, code []byte, gas uint64, endowment *
 }
 
 func (evm *EVM) ChainConfig() *params.ChainConfig { return evm.chainConfig }
+
+func (evm *EVM) VMConfig() Config { return evm.vmConfig }
@@ -2,7 +2,10 @@ package vm
 
 import (
 	"math"
+	"math/big"
 	"testing"
+
+	"github.com/neatio-net/neatio/params"
 )
 
 func TestMemoryGasCost(t *testing.T) {
@@ -53,3 +56,30 @@ var eip2200Tests = []struct {
 	{1, 2306, "0x6001600055", 2306, 0, ErrOutOfGas},
 	{1, 2307, "0x6001600055", 806, 0, nil},
 }
+
+func TestCreateGasEip3860(t *testing.T) {
+	tests := []struct {
+		size    uint64
+		create  uint64
+		create2 uint64
+		err     error
+	}{
+		{0, 0, 0, nil},
+		{1, 2, 8, nil},
+		{params.MaxInitCodeSize, 3072, 12288, nil},
+		{params.MaxInitCodeSize + 1, 0, 0, ErrMaxInitCodeSizeExceeded},
+	}
+	for i, tt := range tests {
+		stack := newstack()
+		stack.push(new(big.Int).SetUint64(tt.size))
+		stack.push(new(big.Int))
+		stack.push(new(big.Int))
+
+		if gas, err := gasCreateEip3860(nil, nil, stack, &Memory{}, 0); err != tt.err || gas != tt.create {
+			t.Errorf("test %d: CREATE gas mismatch: have %v (%v), want %v (%v)", i, gas, err, tt.create, tt.err)
+		}
+		if gas, err := gasCreate2Eip3860(nil, nil, stack, &Memory{}, 0); err != tt.err || gas != tt.create2 {
+			t.Errorf("test %d: CREATE2 gas mismatch: have %v (%v), want %v (%v)", i, gas, err, tt.create2, tt.err)
+		}
+	}
+}
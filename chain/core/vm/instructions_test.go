@@ -194,6 +194,43 @@ func TestSAR(t *testing.T) {
 }
 
 // getResult is a convenience function to generate the expected values
+func TestOpBaseFee(t *testing.T) {
+	var (
+		env            = NewEVM(Context{BaseFee: big.NewInt(7)}, nil, params.TestChainConfig, Config{})
+		stack          = newstack()
+		pc             = uint64(0)
+		evmInterpreter = env.interpreter.(*EVMInterpreter)
+	)
+	evmInterpreter.intPool = poolOfIntPools.get()
+	opBaseFee(&pc, evmInterpreter, nil, nil, stack)
+	if have := stack.pop(); have.Cmp(big.NewInt(7)) != 0 {
+		t.Errorf("base fee mismatch: have %v, want 7", have)
+	}
+	env.BaseFee = nil
+	opBaseFee(&pc, evmInterpreter, nil, nil, stack)
+	if have := stack.pop(); have.Sign() != 0 {
+		t.Errorf("base fee without London mismatch: have %v, want 0", have)
+	}
+	poolOfIntPools.put(evmInterpreter.intPool)
+}
+
+func TestOpPush0(t *testing.T) {
+	var (
+		env            = NewEVM(Context{}, nil, params.TestChainConfig, Config{})
+		stack          = newstack()
+		pc             = uint64(0)
+		evmInterpreter = env.interpreter.(*EVMInterpreter)
+	)
+	// Pooled integers must not leak their value into PUSH0
+	evmInterpreter.intPool = poolOfIntPools.get()
+	evmInterpreter.intPool.put(big.NewInt(-1337))
+	opPush0(&pc, evmInterpreter, nil, nil, stack)
+	if stack.len() != 1 || stack.peek().Sign() != 0 {
+		t.Errorf("PUSH0 pushed %v, want 0", stack.peek())
+	}
+	poolOfIntPools.put(evmInterpreter.intPool)
+}
+
 func getResult(args []*twoOperandParams, opFn executionFunc) []TwoOperandTestcase {
 	var (
 		env         = NewEVM(Context{}, nil, params.TestChainConfig, Config{})
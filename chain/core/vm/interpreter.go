@@ -23,6 +23,10 @@ type Config struct {
 	EVMInterpreter   string
 
 	ExtraEips []int
+
+	// NoBaseFee skips the base fee checks of the message, used by eth_call
+	// and gas estimation which run with a zero gas price.
+	NoBaseFee bool
 }
 
 type Interpreter interface {
@@ -54,6 +58,12 @@ func NewEVMInterpreter(evm *EVM, cfg Config) *EVMInterpreter {
 	if !cfg.JumpTable[STOP].valid {
 		var jt JumpTable
 		switch {
+		case evm.chainRules.IsShanghai:
+			jt = shanghaiInstructionSet
+		case evm.chainRules.IsLondon:
+			jt = londonInstructionSet
+		case evm.chainRules.IsBerlin:
+			jt = berlinInstructionSet
 		case evm.chainRules.IsIstanbul:
 			jt = istanbulInstructionSet
 		case evm.chainRules.IsConstantinople:
@@ -42,10 +42,39 @@ var (
 	byzantiumInstructionSet        = newByzantiumInstructionSet()
 	constantinopleInstructionSet   = newConstantinopleInstructionSet()
 	istanbulInstructionSet         = newIstanbulInstructionSet()
+	berlinInstructionSet           = newBerlinInstructionSet()
+	londonInstructionSet           = newLondonInstructionSet()
+	shanghaiInstructionSet         = newShanghaiInstructionSet()
 )
 
 type JumpTable [256]operation
 
+func newShanghaiInstructionSet() JumpTable {
+	instructionSet := newLondonInstructionSet()
+
+	enable3855(&instructionSet)
+	enable3860(&instructionSet)
+
+	return instructionSet
+}
+
+func newLondonInstructionSet() JumpTable {
+	instructionSet := newBerlinInstructionSet()
+
+	enable3529(&instructionSet)
+	enable3198(&instructionSet)
+
+	return instructionSet
+}
+
+func newBerlinInstructionSet() JumpTable {
+	instructionSet := newIstanbulInstructionSet()
+
+	enable2929(&instructionSet)
+
+	return instructionSet
+}
+
 func newIstanbulInstructionSet() JumpTable {
 	instructionSet := newConstantinopleInstructionSet()
 
@@ -3,12 +3,14 @@ package main
 import (
 	"fmt"
 	"io/ioutil"
+	"strings"
 
 	"github.com/neatio-net/neatio/params"
 
 	"github.com/neatio-net/neatio/chain/accounts"
 	"github.com/neatio-net/neatio/chain/accounts/keystore"
 	"github.com/neatio-net/neatio/chain/log"
+	"github.com/neatio-net/neatio/network/node"
 	"github.com/neatio-net/neatio/utilities/console"
 	"github.com/neatio-net/neatio/utilities/crypto"
 	"github.com/neatio-net/neatio/utilities/utils"
@@ -223,6 +225,26 @@ func unlockAccount(ctx *cli.Context, ks *keystore.KeyStore, address string, i in
 	return accounts.Account{}, ""
 }
 
+// unlockAccounts unlocks the accounts given with the --unlock flag in the
+// keystore of the node
+func unlockAccounts(ctx *cli.Context, stack *node.Node) {
+	var unlocks []string
+	for _, account := range strings.Split(ctx.GlobalString(utils.UnlockedAccountFlag.Name), ",") {
+		if trimmed := strings.TrimSpace(account); trimmed != "" {
+			unlocks = append(unlocks, trimmed)
+		}
+	}
+	if len(unlocks) == 0 {
+		return
+	}
+
+	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	passwords := utils.MakePasswordList(ctx)
+	for i, account := range unlocks {
+		unlockAccount(ctx, ks, account, i, passwords)
+	}
+}
+
 func getPassPhrase(prompt string, confirmation bool, i int, passwords []string) string {
 
 	if len(passwords) > 0 {
@@ -2,51 +2,75 @@ package main
 
 import (
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"runtime"
-	"strings"
 	"testing"
 
 	"github.com/cespare/cp"
 )
 
+const testBalances = "{100000000000000000000000000,100000000000000000000000}"
 
-func tmpDatadirWithKeystore(t *testing.T) string {
-	datadir := tmpdir(t)
-	keystore := filepath.Join(datadir, "keystore")
-	source := filepath.Join("..", "..", "accounts", "keystore", "testdata", "keystore")
-	if err := cp.CopyAll(keystore, source); err != nil {
+// copyTestKeystore adds the keys of the keystore tests to the keystore of the
+// main chain in datadir
+func copyTestKeystore(t *testing.T, datadir string) {
+	keystore := filepath.Join(datadir, clientIdentifier, "keystore")
+	source := filepath.Join("..", "accounts", "keystore", "testdata", "keystore")
+	if err := os.MkdirAll(keystore, 0700); err != nil {
 		t.Fatal(err)
 	}
+	files, err := ioutil.ReadDir(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if err := cp.CopyAll(filepath.Join(keystore, f.Name()), filepath.Join(source, f.Name())); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func tmpDatadirWithKeystore(t *testing.T) string {
+	datadir := tmpdir(t)
+	copyTestKeystore(t, datadir)
+	return datadir
+}
+
+// tmpNodeDatadirWithKeystore adds the test keys to an initialized chain, the
+// key of the validator sorts as the second account
+func tmpNodeDatadirWithKeystore(t *testing.T) string {
+	datadir := tmpDatadirWithGenesis(t, testBalances)
+	copyTestKeystore(t, datadir)
 	return datadir
 }
 
 func TestAccountListEmpty(t *testing.T) {
-	geth := runGeth(t, "account", "list")
+	geth := runneatchain(t, "account", "list")
 	geth.ExpectExit()
 }
 
 func TestAccountList(t *testing.T) {
 	datadir := tmpDatadirWithKeystore(t)
-	geth := runGeth(t, "account", "list", "--datadir", datadir)
+	geth := runneatchain(t, "account", "list", "--datadir", datadir)
 	defer geth.ExpectExit()
 	if runtime.GOOS == "windows" {
 		geth.Expect(`
-Account #0: {7ef5a6135f1fd6a02593eedc869c6d41d934aef8} keystore://{{.Datadir}}\keystore\UTC--2016-03-22T12-57-55.920751759Z--7ef5a6135f1fd6a02593eedc869c6d41d934aef8
-Account #1: {f466859ead1932d743d622cb74fc058882e8648a} keystore://{{.Datadir}}\keystore\aaa
-Account #2: {289d485d9771714cce91d3393d764e1311907acc} keystore://{{.Datadir}}\keystore\zzz
+Account #0: {0x7EF5A6135f1FD6a02593eEdC869c6D41D934aef8} keystore://{{.Datadir}}\neatio\keystore\UTC--2016-03-22T12-57-55.920751759Z--7ef5a6135f1fd6a02593eedc869c6d41d934aef8
+Account #1: {0xf466859eAD1932D743d622CB74FC058882E8648A} keystore://{{.Datadir}}\neatio\keystore\aaa
+Account #2: {0x289d485D9771714CCe91D3393D764E1311907ACc} keystore://{{.Datadir}}\neatio\keystore\zzz
 `)
 	} else {
 		geth.Expect(`
-Account #0: {7ef5a6135f1fd6a02593eedc869c6d41d934aef8} keystore://{{.Datadir}}/keystore/UTC--2016-03-22T12-57-55.920751759Z--7ef5a6135f1fd6a02593eedc869c6d41d934aef8
-Account #1: {f466859ead1932d743d622cb74fc058882e8648a} keystore://{{.Datadir}}/keystore/aaa
-Account #2: {289d485d9771714cce91d3393d764e1311907acc} keystore://{{.Datadir}}/keystore/zzz
+Account #0: {0x7EF5A6135f1FD6a02593eEdC869c6D41D934aef8} keystore://{{.Datadir}}/neatio/keystore/UTC--2016-03-22T12-57-55.920751759Z--7ef5a6135f1fd6a02593eedc869c6d41d934aef8
+Account #1: {0xf466859eAD1932D743d622CB74FC058882E8648A} keystore://{{.Datadir}}/neatio/keystore/aaa
+Account #2: {0x289d485D9771714CCe91D3393D764E1311907ACc} keystore://{{.Datadir}}/neatio/keystore/zzz
 `)
 	}
 }
 
 func TestAccountNew(t *testing.T) {
-	geth := runGeth(t, "account", "new", "--lightkdf")
+	geth := runneatchain(t, "account", "new")
 	defer geth.ExpectExit()
 	geth.Expect(`
 Your new account is locked with a password. Please give a password. Do not forget this password.
@@ -54,11 +78,11 @@ Your new account is locked with a password. Please give a password. Do not forge
 Passphrase: {{.InputLine "foobar"}}
 Repeat passphrase: {{.InputLine "foobar"}}
 `)
-	geth.ExpectRegexp(`Address: \{[0-9a-f]{40}\}\n`)
+	geth.ExpectRegexp(`Address: 0x[0-9a-fA-F]{40}\n`)
 }
 
 func TestAccountNewBadRepeat(t *testing.T) {
-	geth := runGeth(t, "account", "new", "--lightkdf")
+	geth := runneatchain(t, "account", "new")
 	defer geth.ExpectExit()
 	geth.Expect(`
 Your new account is locked with a password. Please give a password. Do not forget this password.
@@ -71,8 +95,8 @@ Fatal: Passphrases do not match
 
 func TestAccountUpdate(t *testing.T) {
 	datadir := tmpDatadirWithKeystore(t)
-	geth := runGeth(t, "account", "update",
-		"--datadir", datadir, "--lightkdf",
+	geth := runneatchain(t, "--verbosity", "2", "account", "update",
+		"--datadir", datadir,
 		"f466859ead1932d743d622cb74fc058882e8648a")
 	defer geth.ExpectExit()
 	geth.Expect(`
@@ -85,188 +109,175 @@ Repeat passphrase: {{.InputLine "foobar2"}}
 `)
 }
 
-func TestWalletImport(t *testing.T) {
-	geth := runGeth(t, "wallet", "import", "--lightkdf", "testdata/guswallet.json")
+func TestAccountImport(t *testing.T) {
+	keyfile := filepath.Join(tmpdir(t), "key")
+	defer os.RemoveAll(filepath.Dir(keyfile))
+	if err := ioutil.WriteFile(keyfile, []byte("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	geth := runneatchain(t, "account", "import", keyfile)
 	defer geth.ExpectExit()
 	geth.Expect(`
+Your new account is locked with a password. Please give a password. Do not forget this password.
 !! Unsupported terminal, password will be echoed.
 Passphrase: {{.InputLine "foo"}}
-Address: {d4584b5f6229b7be90727b0fc8c6b91bb427821f}
+Repeat passphrase: {{.InputLine "foo"}}
+Address: {71562b71999873db5b286df957af199ec94617f7}
 `)
 
-	files, err := ioutil.ReadDir(filepath.Join(geth.Datadir, "keystore"))
+	files, err := ioutil.ReadDir(filepath.Join(geth.Datadir, clientIdentifier, "keystore"))
 	if len(files) != 1 {
 		t.Errorf("expected one key file in keystore directory, found %d files (error: %v)", len(files), err)
 	}
 }
 
-func TestWalletImportBadPassword(t *testing.T) {
-	geth := runGeth(t, "wallet", "import", "--lightkdf", "testdata/guswallet.json")
+func TestAccountImportExisting(t *testing.T) {
+	datadir := tmpdir(t)
+	keyfile := filepath.Join(datadir, "key")
+	if err := ioutil.WriteFile(keyfile, []byte("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	runneatchain(t, "account", "import", "--datadir", datadir, "--password", "testdata/passwords.txt", keyfile).WaitExit()
+
+	geth := runneatchain(t, "account", "import", "--datadir", datadir, keyfile)
 	defer geth.ExpectExit()
 	geth.Expect(`
+Your new account is locked with a password. Please give a password. Do not forget this password.
 !! Unsupported terminal, password will be echoed.
-Passphrase: {{.InputLine "wrong"}}
-Fatal: could not decrypt key with given passphrase
+Passphrase: {{.InputLine "foo"}}
+Repeat passphrase: {{.InputLine "foo"}}
+Fatal: Could not create the account: account already exists
 `)
 }
 
 func TestUnlockFlag(t *testing.T) {
-	datadir := tmpDatadirWithKeystore(t)
-	geth := runGeth(t,
-		"--datadir", datadir, "--nat", "none", "--nodiscover", "--maxpeers", "0", "--port", "0",
-		"--unlock", "f466859ead1932d743d622cb74fc058882e8648a",
-		"js", "testdata/empty.js")
-	geth.Expect(`
-Unlocking account f466859ead1932d743d622cb74fc058882e8648a | Attempt 1/3
-!! Unsupported terminal, password will be echoed.
-Passphrase: {{.InputLine "foobar"}}
-`)
-	geth.ExpectExit()
+	datadir := tmpNodeDatadirWithKeystore(t)
+	defer os.RemoveAll(datadir)
+	geth := runNode(t, datadir,
+		"--unlock", "f466859ead1932d743d622cb74fc058882e8648a")
+	defer geth.Kill()
 
-	wantMessages := []string{
-		"Unlocked account",
-		"=0xf466859eAD1932D743d622CB74FC058882E8648A",
-	}
-	for _, m := range wantMessages {
-		if !strings.Contains(geth.StderrText(), m) {
-			t.Errorf("stderr text does not contain %q", m)
-		}
-	}
+	geth.InputLine("foobar")
+	expectLines(geth,
+		"Unlocking account f466859ead1932d743d622cb74fc058882e8648a | Attempt 1/3",
+		"!! Unsupported terminal, password will be echoed.",
+		"Passphrase: ",
+		"Unlocked account", "=0xf466859eAD1932D743d622CB74FC058882E8648A")
 }
 
 func TestUnlockFlagWrongPassword(t *testing.T) {
-	datadir := tmpDatadirWithKeystore(t)
-	geth := runGeth(t,
-		"--datadir", datadir, "--nat", "none", "--nodiscover", "--maxpeers", "0", "--port", "0",
+	datadir := tmpNodeDatadirWithKeystore(t)
+	defer os.RemoveAll(datadir)
+	geth := runNode(t, datadir,
 		"--unlock", "f466859ead1932d743d622cb74fc058882e8648a")
-	defer geth.ExpectExit()
-	geth.Expect(`
-Unlocking account f466859ead1932d743d622cb74fc058882e8648a | Attempt 1/3
-!! Unsupported terminal, password will be echoed.
-Passphrase: {{.InputLine "wrong1"}}
-Unlocking account f466859ead1932d743d622cb74fc058882e8648a | Attempt 2/3
-Passphrase: {{.InputLine "wrong2"}}
-Unlocking account f466859ead1932d743d622cb74fc058882e8648a | Attempt 3/3
-Passphrase: {{.InputLine "wrong3"}}
-Fatal: Failed to unlock account f466859ead1932d743d622cb74fc058882e8648a (could not decrypt key with given passphrase)
-`)
+	defer geth.WaitExit()
+
+	geth.InputLine("wrong1")
+	geth.InputLine("wrong2")
+	geth.InputLine("wrong3")
+	expectLines(geth,
+		"Unlocking account f466859ead1932d743d622cb74fc058882e8648a | Attempt 1/3",
+		"!! Unsupported terminal, password will be echoed.",
+		"Passphrase: ",
+		"Unlocking account f466859ead1932d743d622cb74fc058882e8648a | Attempt 2/3",
+		"Passphrase: ",
+		"Unlocking account f466859ead1932d743d622cb74fc058882e8648a | Attempt 3/3",
+		"Passphrase: ",
+		"Fatal: Failed to unlock account f466859ead1932d743d622cb74fc058882e8648a (could not decrypt key with given passphrase)")
 }
 
 func TestUnlockFlagMultiIndex(t *testing.T) {
-	datadir := tmpDatadirWithKeystore(t)
-	geth := runGeth(t,
-		"--datadir", datadir, "--nat", "none", "--nodiscover", "--maxpeers", "0", "--port", "0",
-		"--unlock", "0,2",
-		"js", "testdata/empty.js")
-	geth.Expect(`
-Unlocking account 0 | Attempt 1/3
-!! Unsupported terminal, password will be echoed.
-Passphrase: {{.InputLine "foobar"}}
-Unlocking account 2 | Attempt 1/3
-Passphrase: {{.InputLine "foobar"}}
-`)
-	geth.ExpectExit()
+	datadir := tmpNodeDatadirWithKeystore(t)
+	defer os.RemoveAll(datadir)
+	geth := runNode(t, datadir,
+		"--unlock", "0,3")
+	defer geth.Kill()
 
-	wantMessages := []string{
-		"Unlocked account",
-		"=0x7EF5A6135f1FD6a02593eEdC869c6D41D934aef8",
-		"=0x289d485D9771714CCe91D3393D764E1311907ACc",
-	}
-	for _, m := range wantMessages {
-		if !strings.Contains(geth.StderrText(), m) {
-			t.Errorf("stderr text does not contain %q", m)
-		}
-	}
+	geth.InputLine("foobar")
+	geth.InputLine("foobar")
+	expectLines(geth,
+		"Unlocking account 0 | Attempt 1/3",
+		"!! Unsupported terminal, password will be echoed.",
+		"Passphrase: ",
+		"Unlocked account", "=0x7EF5A6135f1FD6a02593eEdC869c6D41D934aef8",
+		"Unlocking account 3 | Attempt 1/3",
+		"Passphrase: ",
+		"Unlocked account", "=0x289d485D9771714CCe91D3393D764E1311907ACc")
 }
 
 func TestUnlockFlagPasswordFile(t *testing.T) {
-	datadir := tmpDatadirWithKeystore(t)
-	geth := runGeth(t,
-		"--datadir", datadir, "--nat", "none", "--nodiscover", "--maxpeers", "0", "--port", "0",
-		"--password", "testdata/passwords.txt", "--unlock", "0,2",
-		"js", "testdata/empty.js")
-	geth.ExpectExit()
+	datadir := tmpNodeDatadirWithKeystore(t)
+	defer os.RemoveAll(datadir)
+	geth := runNode(t, datadir,
+		"--password", "testdata/passwords.txt", "--unlock", "0,3")
+	defer geth.Kill()
 
-	wantMessages := []string{
-		"Unlocked account",
-		"=0x7EF5A6135f1FD6a02593eEdC869c6D41D934aef8",
-		"=0x289d485D9771714CCe91D3393D764E1311907ACc",
-	}
-	for _, m := range wantMessages {
-		if !strings.Contains(geth.StderrText(), m) {
-			t.Errorf("stderr text does not contain %q", m)
-		}
-	}
+	expectLines(geth,
+		"Unlocked account", "=0x7EF5A6135f1FD6a02593eEdC869c6D41D934aef8",
+		"Unlocked account", "=0x289d485D9771714CCe91D3393D764E1311907ACc")
 }
 
 func TestUnlockFlagPasswordFileWrongPassword(t *testing.T) {
-	datadir := tmpDatadirWithKeystore(t)
-	geth := runGeth(t,
-		"--datadir", datadir, "--nat", "none", "--nodiscover", "--maxpeers", "0", "--port", "0",
-		"--password", "testdata/wrong-passwords.txt", "--unlock", "0,2")
-	defer geth.ExpectExit()
-	geth.Expect(`
-Fatal: Failed to unlock account 0 (could not decrypt key with given passphrase)
-`)
+	datadir := tmpNodeDatadirWithKeystore(t)
+	defer os.RemoveAll(datadir)
+	geth := runNode(t, datadir,
+		"--password", "testdata/wrong-passwords.txt", "--unlock", "0,3")
+	defer geth.WaitExit()
+
+	expectLines(geth,
+		"Fatal: Failed to unlock account 0 (could not decrypt key with given passphrase)")
 }
 
 func TestUnlockFlagAmbiguous(t *testing.T) {
-	store := filepath.Join("..", "..", "accounts", "keystore", "testdata", "dupes")
-	geth := runGeth(t,
-		"--keystore", store, "--nat", "none", "--nodiscover", "--maxpeers", "0", "--port", "0",
-		"--unlock", "f466859ead1932d743d622cb74fc058882e8648a",
-		"js", "testdata/empty.js")
-	defer geth.ExpectExit()
+	datadir := tmpDatadirWithGenesis(t, testBalances)
+	defer os.RemoveAll(datadir)
+	store := filepath.Join("..", "accounts", "keystore", "testdata", "dupes")
+	geth := runNode(t, datadir,
+		"--keystore", store,
+		"--unlock", "f466859ead1932d743d622cb74fc058882e8648a")
+	defer geth.Kill()
 
-	geth.SetTemplateFunc("keypath", func(file string) string {
+	keypath := func(file string) string {
 		abs, _ := filepath.Abs(filepath.Join(store, file))
 		return abs
-	})
-	geth.Expect(`
-Unlocking account f466859ead1932d743d622cb74fc058882e8648a | Attempt 1/3
-!! Unsupported terminal, password will be echoed.
-Passphrase: {{.InputLine "foobar"}}
-Multiple key files exist for address f466859ead1932d743d622cb74fc058882e8648a:
-   keystore://{{keypath "1"}}
-   keystore://{{keypath "2"}}
-Testing your passphrase against all of them...
-Your passphrase unlocked keystore://{{keypath "1"}}
-In order to avoid this warning, you need to remove the following duplicate key files:
-   keystore://{{keypath "2"}}
-`)
-	geth.ExpectExit()
-
-	wantMessages := []string{
-		"Unlocked account",
-		"=0xf466859eAD1932D743d622CB74FC058882E8648A",
-	}
-	for _, m := range wantMessages {
-		if !strings.Contains(geth.StderrText(), m) {
-			t.Errorf("stderr text does not contain %q", m)
-		}
 	}
+	geth.InputLine("foobar")
+	expectLines(geth,
+		"Unlocking account f466859ead1932d743d622cb74fc058882e8648a | Attempt 1/3",
+		"!! Unsupported terminal, password will be echoed.",
+		"Passphrase: ",
+		"Unlocked account", "=0xf466859eAD1932D743d622CB74FC058882E8648A",
+		"Multiple key files exist for address f466859ead1932d743d622cb74fc058882e8648a:",
+		"   keystore://"+keypath("1"),
+		"   keystore://"+keypath("2"),
+		"Testing your passphrase against all of them...",
+		"Your passphrase unlocked keystore://"+keypath("1"),
+		"In order to avoid this warning, you need to remove the following duplicate key files:",
+		"   keystore://"+keypath("2"))
 }
 
 func TestUnlockFlagAmbiguousWrongPassword(t *testing.T) {
-	store := filepath.Join("..", "..", "accounts", "keystore", "testdata", "dupes")
-	geth := runGeth(t,
-		"--keystore", store, "--nat", "none", "--nodiscover", "--maxpeers", "0", "--port", "0",
+	datadir := tmpDatadirWithGenesis(t, testBalances)
+	defer os.RemoveAll(datadir)
+	store := filepath.Join("..", "accounts", "keystore", "testdata", "dupes")
+	geth := runNode(t, datadir,
+		"--keystore", store,
 		"--unlock", "f466859ead1932d743d622cb74fc058882e8648a")
-	defer geth.ExpectExit()
+	defer geth.WaitExit()
 
-	geth.SetTemplateFunc("keypath", func(file string) string {
+	keypath := func(file string) string {
 		abs, _ := filepath.Abs(filepath.Join(store, file))
 		return abs
-	})
-	geth.Expect(`
-Unlocking account f466859ead1932d743d622cb74fc058882e8648a | Attempt 1/3
-!! Unsupported terminal, password will be echoed.
-Passphrase: {{.InputLine "wrong"}}
-Multiple key files exist for address f466859ead1932d743d622cb74fc058882e8648a:
-   keystore://{{keypath "1"}}
-   keystore://{{keypath "2"}}
-Testing your passphrase against all of them...
-Fatal: None of the listed files could be unlocked.
-`)
-	geth.ExpectExit()
+	}
+	geth.InputLine("wrong")
+	expectLines(geth,
+		"Unlocking account f466859ead1932d743d622cb74fc058882e8648a | Attempt 1/3",
+		"!! Unsupported terminal, password will be echoed.",
+		"Passphrase: ",
+		"Multiple key files exist for address f466859ead1932d743d622cb74fc058882e8648a:",
+		"   keystore://"+keypath("1"),
+		"   keystore://"+keypath("2"),
+		"Testing your passphrase against all of them...",
+		"Fatal: None of the listed files could be unlocked.")
 }
@@ -28,7 +28,7 @@ const issueUrl = "https://github.com/neatlab/neatio/issues/new"
 func reportBug(ctx *cli.Context) error {
 	var buff bytes.Buffer
 
-	fmt.Fprintln(&buff, header)
+	fmt.Fprint(&buff, header, "\n")
 	fmt.Fprintln(&buff, "Version:", params.Version)
 	fmt.Fprintln(&buff, "Go Version:", runtime.Version())
 	fmt.Fprintln(&buff, "OS:", runtime.GOOS)
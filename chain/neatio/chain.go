@@ -8,6 +8,7 @@ import (
 	ntcTypes "github.com/neatio-net/neatio/chain/consensus/neatcon/types"
 	"github.com/neatio-net/neatio/chain/log"
 	neatnode "github.com/neatio-net/neatio/network/node"
+	"github.com/neatio-net/neatio/utilities/common"
 	"github.com/neatio-net/neatio/utilities/utils"
 	"gopkg.in/urfave/cli.v1"
 )
@@ -68,7 +69,7 @@ func StartChain(ctx *cli.Context, chain *Chain, startDone chan<- struct{}) error
 	return nil
 }
 
-func CreateSideChain(ctx *cli.Context, chainId string, validator ntcTypes.PrivValidator, keyJson []byte, validators []ntcTypes.GenesisValidator) error {
+func CreateSideChain(ctx *cli.Context, chainId string, owner common.Address, validator ntcTypes.PrivValidator, keyJson []byte, validators []ntcTypes.GenesisValidator) error {
 
 	config := utils.GetNeatConConfig(chainId, ctx)
 
@@ -81,11 +82,16 @@ func CreateSideChain(ctx *cli.Context, chainId string, validator ntcTypes.PrivVa
 		}
 	}
 
+	// the key is copied from the main chain, what it signed there does not
+	// count on the new chain
+	validator.LastHeight, validator.LastRound, validator.LastStep = 0, 0, 0
+	validator.LastSignature, validator.LastSignBytes = nil, nil
+
 	privValFile := config.GetString("priv_validator_file_root")
 	validator.SetFile(privValFile + ".json")
 	validator.Save()
 
-	err := initEthGenesisFromExistValidator(chainId, config, validators)
+	err := initEthGenesisFromExistValidator(chainId, owner, config, validators)
 	if err != nil {
 		return err
 	}
@@ -37,6 +37,10 @@ type ChainManager struct {
 	sideChains          map[string]*Chain
 	sideQuits           map[string]<-chan struct{}
 
+	// side chains running in child processes, with --sidechain.process
+	sideProcs map[string]*sideChainProcess
+	sidePorts int
+
 	stop chan struct{}
 
 	server *utils.NeatChainP2PServer
@@ -53,6 +57,7 @@ func GetCMInstance(ctx *cli.Context) *ChainManager {
 		chainMgr.stop = make(chan struct{})
 		chainMgr.sideChains = make(map[string]*Chain)
 		chainMgr.sideQuits = make(map[string]<-chan struct{})
+		chainMgr.sideProcs = make(map[string]*sideChainProcess)
 		chainMgr.cch = &CrossChainHelper{}
 	})
 	return chainMgr
@@ -102,6 +107,11 @@ func (cm *ChainManager) LoadChains(sideIds []string) error {
 			continue
 		}
 
+		if core.GetDecommissionedChain(cm.cch.chainInfoDB, requestId) != nil {
+			log.Warnf("Side chain %s has been decommissioned, skip it", requestId)
+			continue
+		}
+
 		if _, present := readyToLoadChains[requestId]; present {
 
 			continue
@@ -114,6 +124,16 @@ func (cm *ChainManager) LoadChains(sideIds []string) error {
 	log.Infof("Number of side chain to be loaded :%v", len(readyToLoadChains))
 	log.Infof("Start to load side chain: %v", readyToLoadChains)
 
+	if cm.sideChainProcessMode() {
+		if cm.mainChain.NeatNode.IPCEndpoint() == "" {
+			return errors.New("side chain processes need the IPC endpoint of the main chain")
+		}
+		for chainId := range readyToLoadChains {
+			cm.sideProcs[chainId] = cm.newSideChainProcess(chainId)
+		}
+		return nil
+	}
+
 	for chainId := range readyToLoadChains {
 		chain := LoadSideChain(cm.ctx, chainId)
 		if chain == nil {
@@ -198,6 +218,10 @@ func (cm *ChainManager) StartChains() error {
 		cm.server.BroadcastNewSideChainMsg(chain.Id)
 	}
 
+	for _, proc := range cm.sideProcs {
+		proc.Start()
+	}
+
 	return nil
 }
 
@@ -265,6 +289,29 @@ func (cm *ChainManager) StartInspectEvent() {
 			}
 		}
 	}()
+
+	decommissionSideChainCh := make(chan core.DecommissionSideChainEvent, 10)
+	decommissionSideChainSub := MustGetNeatChainFromNode(cm.mainChain.NeatNode).BlockChain().SubscribeDecommissionSideChainEvent(decommissionSideChainCh)
+
+	go func() {
+		defer decommissionSideChainSub.Unsubscribe()
+
+		for {
+			select {
+			case event := <-decommissionSideChainCh:
+				log.Infof("DecommissionSideChainEvent received: %v", event)
+
+				go func() {
+					cm.createSideChainLock.Lock()
+					defer cm.createSideChainLock.Unlock()
+
+					cm.StopSideChain(event.ChainId)
+				}()
+			case <-decommissionSideChainSub.Err():
+				return
+			}
+		}
+	}()
 }
 
 func (cm *ChainManager) LoadSideChainInRT(chainId string) {
@@ -303,7 +350,7 @@ func (cm *ChainManager) LoadSideChainInRT(chainId string) {
 		})
 	}
 
-	defer writeGenesisIntoChainInfoDB(cm.cch.chainInfoDB, chainId, validators)
+	defer writeGenesisIntoChainInfoDB(cm.cch.chainInfoDB, chainId, cci.Owner, validators)
 
 	if !validator {
 		log.Warnf("You are not in the validators of side chain %v, no need to start the side chain", chainId)
@@ -315,6 +362,10 @@ func (cm *ChainManager) LoadSideChainInRT(chainId string) {
 		log.Infof("Side Chain [%v] has been already loaded.", chainId)
 		return
 	}
+	if _, ok := cm.sideProcs[chainId]; ok {
+		log.Infof("Side Chain [%v] has been already loaded.", chainId)
+		return
+	}
 
 	var keyJson []byte
 	wallet, walletErr := cm.mainChain.NeatNode.AccountManager().Find(accounts.Account{Address: localEtherbase})
@@ -329,12 +380,22 @@ func (cm *ChainManager) LoadSideChainInRT(chainId string) {
 	privValidatorFile := cm.mainChain.Config.GetString("priv_validator_file")
 	self := types.LoadPrivValidator(privValidatorFile)
 
-	err := CreateSideChain(cm.ctx, chainId, *self, keyJson, validators)
+	err := CreateSideChain(cm.ctx, chainId, cci.Owner, *self, keyJson, validators)
 	if err != nil {
 		log.Errorf("Create Side Chain %v failed! %v", chainId, err)
 		return
 	}
 
+	// the first epoch is saved by the main chain once the side chain sends its first block
+	if cm.sideChainProcessMode() {
+		cm.formalizeSideChain(chainId, *cci, nil)
+
+		proc := cm.newSideChainProcess(chainId)
+		cm.sideProcs[chainId] = proc
+		proc.Start()
+		return
+	}
+
 	chain := LoadSideChain(cm.ctx, chainId)
 	if chain == nil {
 		log.Errorf("Side Chain %v load failed!", chainId)
@@ -387,6 +448,48 @@ func (cm *ChainManager) LoadSideChainInRT(chainId string) {
 
 }
 
+// StopSideChain closes the node of the decommissioned side chain
+func (cm *ChainManager) StopSideChain(chainId string) {
+
+	if proc, ok := cm.sideProcs[chainId]; ok {
+		proc.Stop()
+		delete(cm.sideProcs, chainId)
+		log.Infof("Side Chain [%v] stopped.", chainId)
+		return
+	}
+
+	chain, ok := cm.sideChains[chainId]
+	if !ok {
+		log.Infof("Side Chain [%v] is not running here.", chainId)
+		return
+	}
+
+	if address, ok := cm.getNodeValidator(chain.NeatNode); ok {
+		cm.server.RemoveLocalValidator(chainId, address)
+	}
+
+	utils.UnhookHTTP(chainId)
+	utils.UnhookWS(chainId)
+
+	// the p2p server is shared with the main chain, only the side protocols are removed
+	cm.server.Server().RemoveChildProtocolCaps(chain.NeatNode.GatherProtocols())
+
+	if err := chain.NeatNode.Stop1(); err != nil {
+		log.Error("Error when stopping side chain", "side id", chainId, "err", err)
+	}
+	if err := chain.NeatNode.AccountManager().Close(); err != nil {
+		log.Error("Error when closing side chain accounts", "side id", chainId, "err", err)
+	}
+
+	if quit, ok := cm.sideQuits[chainId]; ok {
+		<-quit
+		delete(cm.sideQuits, chainId)
+	}
+	delete(cm.sideChains, chainId)
+
+	log.Infof("Side Chain [%v] stopped.", chainId)
+}
+
 func (cm *ChainManager) formalizeSideChain(chainId string, cci core.CoreChainInfo, ep *epoch.Epoch) {
 	core.DeletePendingSideChainData(cm.cch.chainInfoDB, chainId)
 	core.SaveChainInfo(cm.cch.chainInfoDB, &core.ChainInfo{CoreChainInfo: cci, Epoch: ep})
@@ -405,14 +508,19 @@ func (cm *ChainManager) checkCoinbaseInSideChain(sideEpoch *epoch.Epoch) bool {
 }
 
 func (cm *ChainManager) StopChain() {
-	go func() {
-		mainChainError := cm.mainChain.NeatNode.Close()
-		if mainChainError != nil {
-			log.Error("Error when closing main chain", "err", mainChainError)
-		} else {
-			log.Info("Main Chain Closed")
-		}
-	}()
+	for _, proc := range cm.sideProcs {
+		go proc.Stop()
+	}
+	if cm.mainChain != nil {
+		go func() {
+			mainChainError := cm.mainChain.NeatNode.Close()
+			if mainChainError != nil {
+				log.Error("Error when closing main chain", "err", mainChainError)
+			} else {
+				log.Info("Main Chain Closed")
+			}
+		}()
+	}
 	for _, side := range cm.sideChains {
 		go func() {
 			sideChainError := side.NeatNode.Close()
@@ -424,17 +532,24 @@ func (cm *ChainManager) StopChain() {
 }
 
 func (cm *ChainManager) WaitChainsStop() {
-	<-cm.mainQuit
+	if cm.mainChain != nil {
+		<-cm.mainQuit
+	}
 	for _, quit := range cm.sideQuits {
 		<-quit
 	}
+	for _, proc := range cm.sideProcs {
+		proc.Stop()
+	}
 }
 
 func (cm *ChainManager) Stop() {
 	utils.StopRPC()
 	cm.server.Stop()
 	cm.cch.localTX3CacheDB.Close()
-	cm.cch.chainInfoDB.Close()
+	if cm.cch.chainInfoDB != nil {
+		cm.cch.chainInfoDB.Close()
+	}
 
 	close(cm.stop)
 }
@@ -456,8 +571,8 @@ func (cm *ChainManager) getNodeValidator(neatNode *node.Node) (common.Address, b
 	return coinbase, epoch.Validators.HasAddress(coinbase[:])
 }
 
-func writeGenesisIntoChainInfoDB(db dbm.DB, sideChainId string, validators []types.GenesisValidator) {
-	ethByte, _ := generateETHGenesis(sideChainId, validators)
+func writeGenesisIntoChainInfoDB(db dbm.DB, sideChainId string, owner common.Address, validators []types.GenesisValidator) {
+	ethByte, _ := generateETHGenesis(sideChainId, owner, validators)
 	ntcByte, _ := generateNTCGenesis(sideChainId, validators)
 	core.SaveChainGenesis(db, sideChainId, ethByte, ntcByte)
 }
@@ -82,8 +82,8 @@ func defaultNodeConfig() node.Config {
 	cfg := node.DefaultConfig
 	cfg.Name = clientIdentifier
 	cfg.Version = params.VersionWithCommit(gitCommit)
-	cfg.HTTPModules = append(cfg.HTTPModules, "neat", "eth")
-	cfg.WSModules = append(cfg.WSModules, "neat", "eth")
+	cfg.HTTPModules = append(cfg.HTTPModules, "neat", "eth", "chain")
+	cfg.WSModules = append(cfg.WSModules, "neat", "eth", "chain")
 	cfg.IPCPath = "neatio.ipc"
 	return cfg
 }
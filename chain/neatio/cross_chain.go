@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
@@ -40,6 +41,10 @@ type CrossChainHelper struct {
 
 	client      *neatcli.Client
 	mainChainId string
+
+	// the side chain runs in a child process, the main chain is only
+	// reached through the client and the chain info db is not opened
+	remote bool
 }
 
 func (cch *CrossChainHelper) GetMutex() *sync.Mutex {
@@ -54,6 +59,23 @@ func (cch *CrossChainHelper) GetClient() *neatcli.Client {
 	return cch.client
 }
 
+// CheckSideChainRunning returns whether the side chain is launched and not decommissioned
+func (cch *CrossChainHelper) CheckSideChainRunning(chainId string) bool {
+	if cch.remote {
+		ctx, cancel := context.WithTimeout(context.Background(), remoteCallTimeout)
+		defer cancel()
+
+		sc, err := cch.client.SideChain(ctx, chainId)
+		if err != nil {
+			log.Warn("Failed to get the side chain status", "chainId", chainId, "err", err)
+			return false
+		}
+		return sc.Status == "running"
+	}
+
+	return core.CheckSideChainRunning(cch.chainInfoDB, chainId)
+}
+
 func (cch *CrossChainHelper) GetMainChainId() string {
 	return cch.mainChainId
 }
@@ -200,9 +222,9 @@ func (cch *CrossChainHelper) JoinSideChain(from common.Address, pubkey crypto.Pu
 	return nil
 }
 
-func (cch *CrossChainHelper) ReadyForLaunchSideChain(height *big.Int, stateDB *state.StateDB) ([]string, []byte, []string) {
+func (cch *CrossChainHelper) ReadyForLaunchSideChain(height *big.Int, stateDB *state.StateDB, neatFork bool) ([]string, []byte, []string) {
 
-	readyId, updateBytes, removedId := core.GetSideChainForLaunch(cch.chainInfoDB, height, stateDB)
+	readyId, updateBytes, removedId := core.GetSideChainForLaunch(cch.chainInfoDB, height, stateDB, neatFork)
 	if len(readyId) == 0 {
 
 	} else {
@@ -216,6 +238,94 @@ func (cch *CrossChainHelper) ProcessPostPendingData(newPendingIdxBytes []byte, d
 	core.ProcessPostPendingData(cch.chainInfoDB, newPendingIdxBytes, deleteSideChainIds)
 }
 
+func (cch *CrossChainHelper) ValidateDecommissionSideChain(from common.Address, chainId string, proofData []byte) error {
+
+	ci := core.GetChainInfo(cch.chainInfoDB, chainId)
+	if ci == nil {
+		return fmt.Errorf("side chain %s not exist", chainId)
+	}
+
+	if core.GetDecommissionedChain(cch.chainInfoDB, chainId) != nil || !core.CheckSideChainRunning(cch.chainInfoDB, chainId) {
+		return fmt.Errorf("side chain %s is not running", chainId)
+	}
+
+	if ci.Owner != from {
+		return fmt.Errorf("only the owner of side chain %s can decommission it", chainId)
+	}
+
+	if len(proofData) > 0 {
+		header, err := decodeSideChainHeader(proofData)
+		if err != nil {
+			return err
+		}
+		if ncExtra, err := ntcTypes.ExtractNeatConExtra(header); err != nil {
+			return err
+		} else if ncExtra.ChainID != chainId {
+			return fmt.Errorf("proof data is from side chain %s, not %s", ncExtra.ChainID, chainId)
+		}
+		if err := cch.VerifySideChainProofData(proofData); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cch *CrossChainHelper) ReadyForDecommissionSideChain(height *big.Int, stateDB *state.StateDB) []string {
+	return core.GetSideChainForDecommission(height, stateDB)
+}
+
+func (cch *CrossChainHelper) SettleSideChainDeposits(chainId string, stateDB *state.StateDB) error {
+
+	if core.SettleSideChainDeposits(chainId, stateDB) {
+		return nil
+	}
+
+	ci := core.GetChainInfo(cch.chainInfoDB, chainId)
+	if ci == nil {
+		return fmt.Errorf("side chain %s not exist", chainId)
+	}
+
+	core.SettleLegacySideChainDeposits(&ci.CoreChainInfo, stateDB)
+	return nil
+}
+
+// DecommissionSideChain records the final state of the side chain, which is
+// the block in the proof data or the latest one saved to the main chain
+func (cch *CrossChainHelper) DecommissionSideChain(chainId string, mainChainHeight *big.Int, proofData []byte) error {
+	log.Debug("DecommissionSideChain - start")
+
+	dc := &core.DecommissionedChain{MainChainHeight: mainChainHeight}
+
+	if len(proofData) > 0 {
+		header, err := decodeSideChainHeader(proofData)
+		if err != nil {
+			return err
+		}
+		dc.FinalState = core.SideChainState{Height: header.Number.Uint64(), BlockHash: header.Hash(), StateRoot: header.Root}
+		core.SaveSideChainAnchor(cch.chainInfoDB, chainId, &dc.FinalState)
+	} else if anchor := core.GetSideChainAnchor(cch.chainInfoDB, chainId); anchor != nil {
+		dc.FinalState = *anchor
+	}
+
+	core.SaveDecommissionedChain(cch.chainInfoDB, chainId, dc)
+	log.Infof("Side chain %s decommissioned at main chain block %v, final state: %v", chainId, mainChainHeight, dc.FinalState)
+
+	log.Debug("DecommissionSideChain - end")
+	return nil
+}
+
+func decodeSideChainHeader(bs []byte) (*types.Header, error) {
+	var proofData types.SideChainProofData
+	if err := rlp.DecodeBytes(bs, &proofData); err != nil {
+		return nil, err
+	}
+	if proofData.Header == nil {
+		return nil, errors.New("missing header in proof data")
+	}
+	return proofData.Header, nil
+}
+
 func (cch *CrossChainHelper) VoteNextEpoch(ep *epoch.Epoch, from common.Address, voteHash common.Hash, txHash common.Hash) error {
 
 	voteSet := ep.GetNextEpoch().GetEpochValidatorVoteSet()
@@ -287,12 +397,36 @@ func (cch *CrossChainHelper) UpdateNextEpoch(ep *epoch.Epoch, from common.Addres
 	return nil
 }
 
-func (cch *CrossChainHelper) GetHeightFromMainChain() *big.Int {
+// GetHeightFromMainChain returns the current height of the main chain, it fails
+// when the main chain node can not be reached from the side chain process
+func (cch *CrossChainHelper) GetHeightFromMainChain() (*big.Int, error) {
+	if cch.remote {
+		ctx, cancel := context.WithTimeout(context.Background(), remoteCallTimeout)
+		defer cancel()
+
+		number, err := cch.client.BlockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the main chain height: %v", err)
+		}
+		return number, nil
+	}
+
 	neatio := MustGetNeatChainFromNode(chainMgr.mainChain.NeatNode)
-	return neatio.BlockChain().CurrentBlock().Number()
+	return neatio.BlockChain().CurrentBlock().Number(), nil
 }
 
 func (cch *CrossChainHelper) GetTxFromMainChain(txHash common.Hash) *types.Transaction {
+	if cch.remote {
+		ctx, cancel := context.WithTimeout(context.Background(), remoteCallTimeout)
+		defer cancel()
+
+		tx, isPending, err := cch.client.TransactionByHash(ctx, txHash)
+		if err != nil || isPending {
+			return nil
+		}
+		return tx
+	}
+
 	neatio := MustGetNeatChainFromNode(chainMgr.mainChain.NeatNode)
 	chainDb := neatio.ChainDb()
 
@@ -301,6 +435,10 @@ func (cch *CrossChainHelper) GetTxFromMainChain(txHash common.Hash) *types.Trans
 }
 
 func (cch *CrossChainHelper) GetEpochFromMainChain() (string, *epoch.Epoch) {
+	if cch.remote {
+		return cch.mainChainId, nil
+	}
+
 	neatio := MustGetNeatChainFromNode(chainMgr.mainChain.NeatNode)
 	var ep *epoch.Epoch
 	if neatcon, ok := neatio.Engine().(consensus.NeatCon); ok {
@@ -357,6 +495,10 @@ func (cch *CrossChainHelper) VerifySideChainProofData(bs []byte) error {
 		return fmt.Errorf("invalid side chain id: %s", chainId)
 	}
 
+	if core.GetDecommissionedChain(cch.chainInfoDB, chainId) != nil {
+		return fmt.Errorf("side chain %s has been decommissioned", chainId)
+	}
+
 	if header.Nonce != (types.NeatConEmptyNonce) && !bytes.Equal(header.Nonce[:], types.NeatConNonce) {
 		return errors.New("invalid nonce")
 	}
@@ -435,6 +577,8 @@ func (cch *CrossChainHelper) SaveSideChainProofDataToMainChain(bs []byte) error
 		return fmt.Errorf("invalid side chain id: %s", chainId)
 	}
 
+	core.SaveSideChainAnchor(cch.chainInfoDB, chainId, &core.SideChainState{Height: header.Number.Uint64(), BlockHash: header.Hash(), StateRoot: header.Root})
+
 	if len(ncExtra.EpochBytes) != 0 {
 		ep := epoch.FromBytes(ncExtra.EpochBytes)
 		if ep != nil {
@@ -470,6 +614,72 @@ func (cch *CrossChainHelper) SaveSideChainProofDataToMainChain(bs []byte) error
 	return nil
 }
 
+// VerifyCrossChainMessageHeader checks the header is committed by its chain,
+// the main chain headers must be on the canonical chain and the side chain
+// headers must carry the committed seals of the validators of their epoch
+func (cch *CrossChainHelper) VerifyCrossChainMessageHeader(header *types.Header) (string, error) {
+
+	ncExtra, err := ntcTypes.ExtractNeatConExtra(header)
+	if err != nil {
+		return "", err
+	}
+
+	chainId := ncExtra.ChainID
+	if chainId == "" {
+		return "", errors.New("empty chain id")
+	}
+
+	if chainId == cch.mainChainId {
+		var canonical *types.Header
+		if cch.remote {
+			ctx, cancel := context.WithTimeout(context.Background(), remoteCallTimeout)
+			defer cancel()
+			canonical, _ = cch.client.HeaderByNumber(ctx, header.Number)
+		} else {
+			neatio := MustGetNeatChainFromNode(chainMgr.mainChain.NeatNode)
+			canonical = neatio.BlockChain().GetHeaderByNumber(header.Number.Uint64())
+		}
+		if canonical == nil || canonical.Hash() != header.Hash() {
+			return "", errors.New("header not on the main chain")
+		}
+		return chainId, nil
+	}
+
+	if header.MixDigest != types.NeatConDigest {
+		return "", errors.New("invalid mix digest")
+	}
+
+	// the epochs of the side chains are only known to the main chain process
+	if cch.remote {
+		return "", errors.New("side chain headers can not be verified in a side chain process")
+	}
+
+	ci := core.GetChainInfo(cch.chainInfoDB, chainId)
+	if ci == nil {
+		return "", fmt.Errorf("chain info %s not found", chainId)
+	}
+	epoch := ci.GetEpochByBlockNumber(ncExtra.Height)
+	if epoch == nil {
+		return "", fmt.Errorf("could not get epoch for block height %v", ncExtra.Height)
+	}
+
+	valSet := epoch.Validators
+	if !bytes.Equal(valSet.Hash(), ncExtra.ValidatorsHash) {
+		return "", errors.New("inconsistent validator set")
+	}
+
+	seenCommit := ncExtra.SeenCommit
+	if seenCommit == nil || !bytes.Equal(ncExtra.SeenCommitHash, seenCommit.Hash()) {
+		return "", errors.New("invalid committed seals")
+	}
+
+	if err = valSet.VerifyCommit(ncExtra.ChainID, ncExtra.Height, seenCommit); err != nil {
+		return "", err
+	}
+
+	return chainId, nil
+}
+
 func (cch *CrossChainHelper) ValidateTX3ProofData(proofData *types.TX3ProofData) error {
 	log.Debug("ValidateTX3ProofData - start")
 
@@ -541,6 +751,10 @@ func (cch *CrossChainHelper) ValidateTX3ProofData(proofData *types.TX3ProofData)
 		return err
 	}
 
+	if len(proofData.ReceiptProofs) != 0 && len(proofData.ReceiptProofs) != len(proofData.TxIndexs) {
+		return errors.New("inconsistent receipt proofs")
+	}
+
 	keybuf := new(bytes.Buffer)
 	for i, txIndex := range proofData.TxIndexs {
 		keybuf.Reset()
@@ -549,6 +763,11 @@ func (cch *CrossChainHelper) ValidateTX3ProofData(proofData *types.TX3ProofData)
 		if err != nil {
 			return err
 		}
+		if len(proofData.ReceiptProofs) != 0 {
+			if _, err := types.VerifyReceiptProof(header.ReceiptHash, txIndex, proofData.ReceiptProofs[i]); err != nil {
+				return err
+			}
+		}
 	}
 
 	log.Debug("ValidateTX3ProofData - end")
@@ -557,7 +776,7 @@ func (cch *CrossChainHelper) ValidateTX3ProofData(proofData *types.TX3ProofData)
 
 func (cch *CrossChainHelper) ValidateTX4WithInMemTX3ProofData(tx4 *types.Transaction, tx3ProofData *types.TX3ProofData) error {
 
-	signer := types.NewEIP155Signer(tx4.ChainId())
+	signer := types.LatestSignerForChainID(tx4.ChainId())
 	from, err := types.Sender(signer, tx4)
 	if err != nil {
 		return core.ErrInvalidSender
@@ -595,12 +814,12 @@ func (cch *CrossChainHelper) ValidateTX4WithInMemTX3ProofData(tx4 *types.Transac
 	}
 
 	var tx3 types.Transaction
-	err = rlp.DecodeBytes(val, &tx3)
+	err = tx3.UnmarshalBinary(val)
 	if err != nil {
 		return err
 	}
 
-	signer2 := types.NewEIP155Signer(tx3.ChainId())
+	signer2 := types.LatestSignerForChainID(tx3.ChainId())
 	tx3From, err := types.Sender(signer2, &tx3)
 	if err != nil {
 		return core.ErrInvalidSender
@@ -1,90 +1,49 @@
 package main
 
 import (
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 var customGenesisTests = []struct {
-	genesis string
-	query   string
-	result  string
+	balances string
+	query    string
+	result   string
 }{
 
 	{
-		genesis: `{
-			"alloc"      : {},
-			"coinbase"   : "0x0000000000000000000000000000000000000000",
-			"difficulty" : "0x20000",
-			"extraData"  : "",
-			"gasLimit"   : "0x2fefd8",
-			"nonce"      : "0x0000000000000042",
-			"mixhash"    : "0x0000000000000000000000000000000000000000000000000000000000000000",
-			"parentHash" : "0x0000000000000000000000000000000000000000000000000000000000000000",
-			"timestamp"  : "0x00"
-		}`,
-		query:  "eth.getBlock(0).nonce",
-		result: "0x0000000000000042",
+		balances: "{100000000000000000000000000,100000000000000000000000}",
+		query:    "eth.getBalance(eth.coinbase, 0)",
+		result:   `1e\+26`,
 	},
 
 	{
-		genesis: `{
-			"alloc"      : {},
-			"coinbase"   : "0x0000000000000000000000000000000000000000",
-			"difficulty" : "0x20000",
-			"extraData"  : "",
-			"gasLimit"   : "0x2fefd8",
-			"nonce"      : "0x0000000000000042",
-			"mixhash"    : "0x0000000000000000000000000000000000000000000000000000000000000000",
-			"parentHash" : "0x0000000000000000000000000000000000000000000000000000000000000000",
-			"timestamp"  : "0x00",
-			"config"     : {}
-		}`,
-		query:  "eth.getBlock(0).nonce",
-		result: "0x0000000000000042",
-	},
-
-	{
-		genesis: `{
-			"alloc"      : {},
-			"coinbase"   : "0x0000000000000000000000000000000000000000",
-			"difficulty" : "0x20000",
-			"extraData"  : "",
-			"gasLimit"   : "0x2fefd8",
-			"nonce"      : "0x0000000000000042",
-			"mixhash"    : "0x0000000000000000000000000000000000000000000000000000000000000000",
-			"parentHash" : "0x0000000000000000000000000000000000000000000000000000000000000000",
-			"timestamp"  : "0x00",
-			"config"     : {
-				"homesteadBlock" : 314,
-				"daoForkBlock"   : 141,
-				"daoForkSupport" : true
-			},
-		}`,
-		query:  "eth.getBlock(0).nonce",
-		result: "0x0000000000000042",
+		balances: "{250000000000000000000000000,500000000000000000000000}",
+		query:    "eth.getBalance(eth.coinbase, 0)",
+		result:   `2\.5e\+26`,
 	},
 }
 
 func TestCustomGenesis(t *testing.T) {
-	for i, tt := range customGenesisTests {
+	for _, tt := range customGenesisTests {
 
-		datadir := tmpdir(t)
+		datadir := tmpDatadirWithGenesis(t, tt.balances)
 		defer os.RemoveAll(datadir)
 
-		json := filepath.Join(datadir, "genesis.json")
-		if err := ioutil.WriteFile(json, []byte(tt.genesis), 0600); err != nil {
-			t.Fatalf("test %d: failed to write genesis file: %v", i, err)
-		}
-		runGeth(t, "--datadir", datadir, "init", json).WaitExit()
+		ws := tmpdir(t)
+		defer os.RemoveAll(ws)
+		ipc := filepath.Join(ws, "neatio.ipc")
 
-		geth := runGeth(t,
-			"--datadir", datadir, "--maxpeers", "0", "--port", "0",
-			"--nodiscover", "--nat", "none", "--ipcdisable",
-			"--exec", tt.query, "console")
+		neatio := runNode(t, datadir, "--ipcpath", ipc)
+		time.Sleep(2 * time.Second)
+
+		geth := runneatchain(t, "attach", "--exec", tt.query, "ipc:"+ipc)
 		geth.ExpectRegexp(tt.result)
 		geth.ExpectExit()
+
+		neatio.Kill()
+		neatio.WaitExit()
 	}
 }
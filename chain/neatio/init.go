@@ -428,9 +428,9 @@ func checkAccount(coreGenesis core.Genesis) (common.Address, *big.Int, error) {
 	return act, amount, nil
 }
 
-func initEthGenesisFromExistValidator(sideChainID string, sideConfig cfg.Config, validators []types.GenesisValidator) error {
+func initEthGenesisFromExistValidator(sideChainID string, owner common.Address, sideConfig cfg.Config, validators []types.GenesisValidator) error {
 
-	contents, err := generateETHGenesis(sideChainID, validators)
+	contents, err := generateETHGenesis(sideChainID, owner, validators)
 	if err != nil {
 		return err
 	}
@@ -442,7 +442,7 @@ func initEthGenesisFromExistValidator(sideChainID string, sideConfig cfg.Config,
 	return nil
 }
 
-func generateETHGenesis(sideChainID string, validators []types.GenesisValidator) ([]byte, error) {
+func generateETHGenesis(sideChainID string, owner common.Address, validators []types.GenesisValidator) ([]byte, error) {
 	var coreGenesis = core.Genesis{
 		Config:     params.NewSideChainConfig(sideChainID),
 		Nonce:      0xdeadbeefdeadbeef,
@@ -463,6 +463,7 @@ func generateETHGenesis(sideChainID string, validators []types.GenesisValidator)
 	}
 
 	coreGenesis.Alloc[abi.NeatioSideChainsAddress] = core.GenesisAccount{
+		Storage: map[common.Hash]common.Hash{abi.SideChainOwnerKey: common.BytesToHash(owner.Bytes())},
 		Balance: new(big.Int).Mul(big.NewInt(100000), big.NewInt(1e+18)),
 		Amount:  common.Big0,
 	}
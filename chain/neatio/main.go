@@ -46,6 +46,8 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		utils.TxPoolSpecialSlotsFlag,
+		utils.TxPoolSpecialAccountSlotsFlag,
 
 		utils.SyncModeFlag,
 		utils.GCModeFlag,
@@ -62,6 +64,7 @@ var (
 		utils.MinerGasLimitFlag,
 		utils.MinerGasPriceFlag,
 		utils.MinerCoinbaseFlag,
+		utils.MinerAutoRevealFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.DiscoveryV5Flag,
@@ -81,6 +84,7 @@ var (
 		utils.ExtraDataFlag,
 
 		utils.SideChainFlag,
+		utils.SideChainProcessFlag,
 	}
 
 	rpcFlags = []cli.Flag{
@@ -127,6 +131,12 @@ func init() {
 
 		dumpConfigCommand,
 		versionCommand,
+
+		walInspectCommand,
+		signerCommand,
+		relayerCommand,
+		sideChainCommand,
+		validatorCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
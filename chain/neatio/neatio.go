@@ -46,6 +46,8 @@ func neatchainCmd(ctx *cli.Context) error {
 
 	err = chainMgr.StartMainChain()
 
+	unlockAccounts(ctx, chainMgr.mainChain.NeatNode)
+
 	err = chainMgr.LoadChains(requestSideChain)
 	if err != nil {
 		log.Errorf("Load Side Chains failed. %v", err)
@@ -66,28 +68,30 @@ func neatchainCmd(ctx *cli.Context) error {
 
 	chainMgr.StartInspectEvent()
 
-	go func() {
-		sigc := make(chan os.Signal, 1)
-		signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
-		defer signal.Stop(sigc)
-		<-sigc
-		log.Info("Got interrupt, shutting down...")
-
-		chainMgr.StopChain()
-		chainMgr.WaitChainsStop()
-		chainMgr.Stop()
-
-		for i := 10; i > 0; i-- {
-			<-sigc
-			if i > 1 {
-				log.Info(fmt.Sprintf("Already shutting down, interrupt %d more times for panic.", i-1))
-			}
-		}
-		debug.Exit()
-		debug.LoudPanic("boom")
-	}()
+	go handleInterrupt(chainMgr)
 
 	chainMgr.Wait()
 
 	return nil
 }
+
+func handleInterrupt(chainMgr *ChainManager) {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigc)
+	<-sigc
+	log.Info("Got interrupt, shutting down...")
+
+	chainMgr.StopChain()
+	chainMgr.WaitChainsStop()
+	chainMgr.Stop()
+
+	for i := 10; i > 0; i-- {
+		<-sigc
+		if i > 1 {
+			log.Info(fmt.Sprintf("Already shutting down, interrupt %d more times for panic.", i-1))
+		}
+	}
+	debug.Exit()
+	debug.LoudPanic("boom")
+}
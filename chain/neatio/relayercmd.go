@@ -0,0 +1,350 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/neatio-net/neatio/chain/accounts/keystore"
+	"github.com/neatio-net/neatio/chain/core/types"
+	"github.com/neatio-net/neatio/chain/log"
+	"github.com/neatio-net/neatio/neatcli"
+	"github.com/neatio-net/neatio/network/node"
+	"github.com/neatio-net/neatio/utilities/metrics"
+	"github.com/neatio-net/neatio/utilities/metrics/exp"
+	"github.com/neatio-net/neatio/utilities/utils"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	relayerSideChainRPCFlag = cli.StringFlag{
+		Name:  "sidechain.rpc",
+		Usage: "RPC endpoint of a node of the side chain (default: the side chain on the local http rpc)",
+	}
+	relayerMainChainRPCFlag = cli.StringFlag{
+		Name:  "mainchain.rpc",
+		Usage: "RPC endpoint of a node of the main chain (default: the main chain on the local http rpc)",
+	}
+	relayerKeyFileFlag = cli.StringFlag{
+		Name:  "keyfile",
+		Usage: "Keystore file of the account sending the txs to the main chain",
+	}
+	relayerStartFlag = cli.Uint64Flag{
+		Name:  "start",
+		Usage: "Side chain block to start from if nothing is relayed yet (default: the next block)",
+	}
+	relayerIntervalFlag = cli.IntFlag{
+		Name:  "interval",
+		Usage: "Seconds between the checks of the side chain",
+		Value: 3,
+	}
+	relayerMetricsAddrFlag = cli.StringFlag{
+		Name:  "metrics.addr",
+		Usage: "Serve the relayer metrics on http://<addr>/debug/metrics",
+	}
+	relayerCommand = cli.Command{
+		Action:    utils.MigrateFlags(runRelayer),
+		Name:      "relayer",
+		Usage:     "Relay the blocks of a side chain to the main chain",
+		ArgsUsage: "<chainId>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.PasswordFileFlag,
+			relayerSideChainRPCFlag,
+			relayerMainChainRPCFlag,
+			relayerKeyFileFlag,
+			relayerStartFlag,
+			relayerIntervalFlag,
+			relayerMetricsAddrFlag,
+		},
+		Category: "MISCELLANEOUS COMMANDS",
+		Description: `
+The relayer command follows a side chain over RPC and sends the epochs of the
+side chain blocks to the main chain with SaveDataToMainChain txs, and the proof
+data of the withdrawals to the main chain nodes. The last relayed block is saved
+in <datadir>/relayer/<chainId>.json, the relayer starts from there after a restart.
+
+A block is relayed only after the main chain took it, if a node is down the
+relayer retries the same block until it succeeds. The lag behind the side chain
+is reported as metrics.
+
+Set external_relayer = true in the config.toml of the side chain validators so
+they stop sending the blocks themselves.`,
+	}
+)
+
+func runRelayer(ctx *cli.Context) error {
+	chainId := ctx.Args().First()
+	if chainId == "" || chainId == MainChain || chainId == TestnetChain {
+		utils.Fatalf("A side chain id is required")
+	}
+	mainChainId := MainChain
+	if ctx.GlobalBool(utils.TestnetFlag.Name) {
+		mainChainId = TestnetChain
+	}
+
+	keyFile := ctx.String(relayerKeyFileFlag.Name)
+	if keyFile == "" {
+		utils.Fatalf("--%v is required", relayerKeyFileFlag.Name)
+	}
+	keyJson, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		utils.Fatalf("Failed to read the key file: %v", err)
+	}
+	var password string
+	if file := ctx.String(utils.PasswordFileFlag.Name); file != "" {
+		text, err := ioutil.ReadFile(file)
+		if err != nil {
+			utils.Fatalf("Failed to read the password file: %v", err)
+		}
+		password = strings.TrimRight(strings.Split(string(text), "\n")[0], "\r")
+	} else {
+		password = getPassPhrase("Please give the password of the relayer account", false, 0, nil)
+	}
+	key, err := keystore.DecryptKey(keyJson, password)
+	if err != nil {
+		utils.Fatalf("Failed to decrypt the key: %v", err)
+	}
+
+	if addr := ctx.String(relayerMetricsAddrFlag.Name); addr != "" {
+		metrics.Enabled = true
+		mux := http.NewServeMux()
+		mux.Handle("/debug/metrics", exp.ExpHandler(metrics.DefaultRegistry))
+		go func() {
+			log.Info("Starting relayer metrics server", "addr", fmt.Sprintf("http://%s/debug/metrics", addr))
+			if err := http.ListenAndServe(addr, mux); err != nil {
+				log.Error("Failure in running relayer metrics server", "err", err)
+			}
+		}()
+	}
+
+	endpoint := func(flag cli.StringFlag, chainId string) string {
+		if url := ctx.String(flag.Name); url != "" {
+			return url
+		}
+		return fmt.Sprintf("http://%s:%d/%s", node.DefaultHTTPHost, node.DefaultHTTPPort, chainId)
+	}
+	side, err := neatcli.Dial(endpoint(relayerSideChainRPCFlag, chainId))
+	if err != nil {
+		utils.Fatalf("Unable to connect to the side chain: %v", err)
+	}
+	main, err := neatcli.Dial(endpoint(relayerMainChainRPCFlag, mainChainId))
+	if err != nil {
+		utils.Fatalf("Unable to connect to the main chain: %v", err)
+	}
+
+	stateFile := filepath.Join(utils.MakeDataDir(ctx), "relayer", chainId+".json")
+	r, err := newRelayer(chainId, mainChainId, side, main, key.PrivateKey, stateFile)
+	if err != nil {
+		utils.Fatalf("Failed to load the relayer state: %v", err)
+	}
+	if !r.state.Started {
+		if start := ctx.Uint64(relayerStartFlag.Name); start > 0 {
+			r.state.Relayed = start - 1
+		} else {
+			number, err := side.BlockNumber(context.Background())
+			if err != nil {
+				utils.Fatalf("Failed to get the side chain block: %v", err)
+			}
+			r.state.Relayed = number.Uint64()
+		}
+		r.state.Started = true
+	}
+
+	quit := make(chan struct{})
+	go func() {
+		sigc := make(chan os.Signal, 1)
+		signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+		<-sigc
+		log.Info("Shutting down relayer")
+		close(quit)
+	}()
+
+	log.Infof("Relaying side chain %s to %s from block %v with account %x", chainId, mainChainId, r.state.Relayed+1, key.Address)
+	r.run(time.Duration(ctx.Int(relayerIntervalFlag.Name))*time.Second, quit)
+	return r.saveState()
+}
+
+// relayerState is saved after each relayed block
+type relayerState struct {
+	Started bool   `json:"started"`
+	Relayed uint64 `json:"relayed"`
+}
+
+type relayer struct {
+	chainId     string
+	mainChainId string
+	side, main  *neatcli.Client
+	key         *ecdsa.PrivateKey
+
+	stateFile string
+	state     relayerState
+
+	sideHeight    metrics.Gauge
+	relayedHeight metrics.Gauge
+	lag           metrics.Gauge
+	anchored      metrics.Counter
+	broadcast     metrics.Counter
+	failures      metrics.Counter
+}
+
+func newRelayer(chainId, mainChainId string, side, main *neatcli.Client, key *ecdsa.PrivateKey, stateFile string) (*relayer, error) {
+	r := &relayer{
+		chainId:       chainId,
+		mainChainId:   mainChainId,
+		side:          side,
+		main:          main,
+		key:           key,
+		stateFile:     stateFile,
+		sideHeight:    metrics.NewRegisteredGauge("relayer/"+chainId+"/side/height", nil),
+		relayedHeight: metrics.NewRegisteredGauge("relayer/"+chainId+"/relayed/height", nil),
+		lag:           metrics.NewRegisteredGauge("relayer/"+chainId+"/lag", nil),
+		anchored:      metrics.NewRegisteredCounter("relayer/"+chainId+"/anchored", nil),
+		broadcast:     metrics.NewRegisteredCounter("relayer/"+chainId+"/broadcast", nil),
+		failures:      metrics.NewRegisteredCounter("relayer/"+chainId+"/failures", nil),
+	}
+
+	bs, err := ioutil.ReadFile(stateFile)
+	if os.IsNotExist(err) {
+		return r, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(bs, &r.state); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *relayer) saveState() error {
+	bs, err := json.Marshal(r.state)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.stateFile), 0700); err != nil {
+		return err
+	}
+	// write then rename, a crash never leaves a truncated state
+	tmp := r.stateFile + ".tmp"
+	if err := ioutil.WriteFile(tmp, bs, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.stateFile)
+}
+
+func (r *relayer) run(interval time.Duration, quit <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.relay(quit); err != nil {
+			r.failures.Inc(1)
+			log.Warn("Relay side chain block failed, retry later", "chain", r.chainId, "block", r.state.Relayed+1, "err", err)
+		}
+
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay sends the blocks after the last relayed one, it stops at the first
+// failure so the same block is sent again next time
+func (r *relayer) relay(quit <-chan struct{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	head, err := r.side.BlockNumber(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+	r.sideHeight.Update(head.Int64())
+
+	for number := r.state.Relayed + 1; number <= head.Uint64(); number++ {
+		select {
+		case <-quit:
+			return nil
+		default:
+		}
+
+		if err := r.relayBlock(number); err != nil {
+			return err
+		}
+
+		r.state.Relayed = number
+		if err := r.saveState(); err != nil {
+			return err
+		}
+		r.relayedHeight.Update(int64(number))
+		r.lag.Update(head.Int64() - int64(number))
+	}
+	return nil
+}
+
+func (r *relayer) relayBlock(number uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	data, err := r.side.RelayData(ctx, new(big.Int).SetUint64(number))
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	if len(data.ProofData) > 0 {
+		if err := r.saveDataToMainChain(data.ProofData); err != nil {
+			return err
+		}
+		r.anchored.Inc(1)
+		log.Info("Side chain block saved to the main chain", "chain", r.chainId, "block", number, "hash", data.Hash)
+	}
+
+	if len(data.TX3ProofData) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := r.main.BroadcastDataToMainChain(ctx, r.chainId, data.TX3ProofData)
+		cancel()
+		if err != nil {
+			return err
+		}
+		r.broadcast.Inc(1)
+		log.Info("Side chain withdrawals and transfers sent to the main chain", "chain", r.chainId, "block", number, "hash", data.Hash)
+	}
+	return nil
+}
+
+// saveDataToMainChain sends the SaveDataToMainChain tx and waits for it in a
+// main chain block
+func (r *relayer) saveDataToMainChain(proofData []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	hash, err := r.main.SendDataToMainChain(ctx, proofData, r.key, r.mainChainId)
+	if err != nil {
+		return err
+	}
+
+	for {
+		receipt, err := r.main.TransactionReceipt(ctx, hash)
+		if err == nil && receipt != nil {
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				return fmt.Errorf("tx %x failed in the main chain", hash)
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("tx %x not in the main chain in time", hash)
+		case <-time.After(time.Second):
+		}
+	}
+}
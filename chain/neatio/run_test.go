@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/docker/docker/pkg/reexec"
@@ -75,3 +78,28 @@ func runneatchain(t *testing.T, args ...string) *testneatchain {
 
 	return tt
 }
+
+// tmpDatadirWithGenesis initializes the main chain in a new data directory,
+// the node only starts on an initialized chain
+func tmpDatadirWithGenesis(t *testing.T, balances string) string {
+	datadir := tmpdir(t)
+	runneatchain(t, "--datadir", datadir, "init-neatio", balances).WaitExit()
+	runneatchain(t, "--datadir", datadir, "init", filepath.Join(datadir, clientIdentifier, "neat_genesis.json")).WaitExit()
+	return datadir
+}
+
+// runNode starts the main chain node without peers, stop it with Kill
+func runNode(t *testing.T, datadir string, args ...string) *testneatchain {
+	args = append([]string{"--datadir", datadir, "--nat", "none", "--nodiscover", "--maxpeers", "0", "--port", "0"}, args...)
+	return runneatchain(t, args...)
+}
+
+// expectLines matches the given lines in order, the node logs to stdout so
+// log lines may come in between
+func expectLines(tt *testneatchain, lines ...string) {
+	quoted := make([]string, len(lines))
+	for i, line := range lines {
+		quoted[i] = regexp.QuoteMeta(line)
+	}
+	tt.ExpectRegexp(`(?s)` + strings.Join(quoted, `.*?`))
+}
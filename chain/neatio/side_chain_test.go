@@ -0,0 +1,373 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	goCrypto "github.com/neatio-net/crypto-go"
+	"github.com/neatio-net/neatio/chain/accounts"
+	"github.com/neatio-net/neatio/chain/accounts/keystore"
+	"github.com/neatio-net/neatio/chain/consensus"
+	ncConsensus "github.com/neatio-net/neatio/chain/consensus/neatcon/consensus"
+	ntcTypes "github.com/neatio-net/neatio/chain/consensus/neatcon/types"
+	"github.com/neatio-net/neatio/chain/core"
+	"github.com/neatio-net/neatio/chain/core/types"
+	"github.com/neatio-net/neatio/internal/neatapi"
+	"github.com/neatio-net/neatio/neatabi/abi"
+	"github.com/neatio-net/neatio/neatcli"
+	"github.com/neatio-net/neatio/params"
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/common/hexutil"
+	"github.com/neatio-net/neatio/utilities/common/math"
+	"github.com/neatio-net/neatio/utilities/crypto"
+	"github.com/neatio-net/neatio/utilities/rlp"
+	"github.com/neatio-net/neatio/utilities/utils"
+	"gopkg.in/urfave/cli.v1"
+)
+
+const testSideChainId = "sidetest"
+
+// TestSideChainLifecycle runs the main chain with one validator, creates a side
+// chain, joins it with the validator key and waits for the side chain to be
+// launched and loaded in the same process, then decommissions it
+func TestSideChainLifecycle(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs a main chain and a side chain")
+	}
+
+	datadir := tmpdir(t)
+	defer os.RemoveAll(datadir)
+
+	// the side chain sends its blocks to the main chain through the http rpc
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpcPort := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	set := flag.NewFlagSet("neatio-test", flag.ContinueOnError)
+	for _, f := range app.Flags {
+		f.Apply(set)
+	}
+	if err := set.Parse([]string{
+		"--" + utils.DataDirFlag.Name, datadir,
+		"--" + utils.ListenPortFlag.Name, "0",
+		"--" + utils.NoDiscoverFlag.Name,
+		"--" + utils.RPCEnabledFlag.Name,
+		"--" + utils.RPCPortFlag.Name, strconv.Itoa(rpcPort),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := cli.NewContext(app, set, nil)
+
+	mainConfig := utils.GetNeatConConfig(MainChain, ctx)
+	if err := init_neat_genesis(mainConfig, "{100000000000000000000000000,100000000000000000000000}", true); err != nil {
+		t.Fatalf("init genesis error %v", err)
+	}
+
+	// the side chain transactions apply from the NeatFork block on, the main
+	// chain takes its config from the genesis
+	genesisFile := mainConfig.GetString("neat_genesis_file")
+	contents, err := ioutil.ReadFile(genesisFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := new(core.Genesis)
+	if err := json.Unmarshal(contents, genesis); err != nil {
+		t.Fatal(err)
+	}
+	chainConfig := *params.MainnetChainConfig
+	chainConfig.NeatForkBlock = big.NewInt(0)
+	genesis.Config = &chainConfig
+	if contents, err = json.MarshalIndent(genesis, "", "\t"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(genesisFile, contents, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := init_cmd(ctx, mainConfig, MainChain, mainConfig.GetString("neat_genesis_file")); err != nil {
+		t.Fatalf("init main chain error %v", err)
+	}
+
+	cm := GetCMInstance(ctx)
+	cm.InitP2P()
+	if err := cm.LoadMainChain(); err != nil {
+		t.Fatal(err)
+	}
+	cm.InitCrossChainHelper()
+	if err := cm.StartP2PServer(); err != nil {
+		t.Fatal(err)
+	}
+	ncConsensus.NodeID = cm.GetNodeID()[0:16]
+	if err := cm.StartMainChain(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.StartRPC(); err != nil {
+		t.Fatal(err)
+	}
+	cm.StartInspectEvent()
+	defer func() {
+		cm.StopChain()
+		cm.WaitChainsStop()
+		cm.Stop()
+	}()
+
+	neatio := MustGetNeatChainFromNode(cm.mainChain.NeatNode)
+	from := neatio.Engine().(consensus.NeatCon).PrivateValidator()
+
+	ks := cm.mainChain.NeatNode.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	if err := ks.Unlock(accounts.Account{Address: from}, DefaultAccountPassword); err != nil {
+		t.Fatalf("unlock validator account error %v", err)
+	}
+
+	client, err := cm.mainChain.NeatNode.Attach()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	events := make(chan neatapi.SideChainEvent, 10)
+	eventSub, err := client.Subscribe(context.Background(), "chain", events, "sideChainEvents")
+	if err != nil {
+		t.Fatalf("subscribe side chain events error %v", err)
+	}
+	defer eventSub.Unsubscribe()
+
+	waitFor(t, "main chain block 1", func() bool {
+		return neatio.BlockChain().CurrentBlock().NumberU64() >= 1
+	})
+
+	current := neatio.BlockChain().CurrentBlock().Number()
+	startBlock := new(big.Int).Add(current, big.NewInt(5))
+	endBlock := new(big.Int).Add(current, big.NewInt(1000))
+	minDeposit := math.MustParseBig256(core.OFFICIAL_MINIMUM_DEPOSIT)
+
+	var txHash common.Hash
+	if err := client.Call(&txHash, "chain_createSideChain", from, testSideChainId, hexutil.Uint(1), (*hexutil.Big)(minDeposit), (*hexutil.Big)(startBlock), (*hexutil.Big)(endBlock), nil); err != nil {
+		t.Fatalf("create side chain error %v", err)
+	}
+	waitFor(t, "side chain created", func() bool {
+		return core.GetPendingSideChainData(cm.cch.chainInfoDB, testSideChainId) != nil
+	})
+
+	var pending []*neatapi.SideChainInfo
+	if err := client.Call(&pending, "chain_getPendingSideChains"); err != nil {
+		t.Fatalf("get pending side chains error %v", err)
+	}
+	if len(pending) != 1 || pending[0].ChainId != testSideChainId || pending[0].Owner != from {
+		t.Errorf("pending side chains mismatch, got %v", pending)
+	}
+
+	privVal := ntcTypes.LoadPrivValidator(mainConfig.GetString("priv_validator_file"))
+	var pubkey goCrypto.BLSPubKey
+	copy(pubkey[:], privVal.PubKey.Bytes())
+	signature := privVal.PrivKey.Sign(from.Bytes())
+	if err := client.Call(&txHash, "chain_joinSideChain", from, pubkey, testSideChainId, (*hexutil.Big)(minDeposit), hexutil.Bytes(signature.Bytes()), nil); err != nil {
+		t.Fatalf("join side chain error %v", err)
+	}
+	waitFor(t, "validator joined", func() bool {
+		cci := core.GetPendingSideChainData(cm.cch.chainInfoDB, testSideChainId)
+		return cci != nil && len(cci.JoinedValidators) == 1
+	})
+
+	// launched at the start block, then loaded and started by the chain manager
+	waitFor(t, "side chain launched", func() bool {
+		return core.GetChainInfo(cm.cch.chainInfoDB, testSideChainId) != nil
+	})
+	waitFor(t, "side chain block 1", func() bool {
+		cm.createSideChainLock.Lock()
+		defer cm.createSideChainLock.Unlock()
+
+		side, ok := cm.sideChains[testSideChainId]
+		if !ok {
+			return false
+		}
+		return MustGetNeatChainFromNode(side.NeatNode).BlockChain().CurrentBlock().NumberU64() >= 1
+	})
+
+	// the relayer follows the side chain over the http rpc and resumes from its state file
+	sideClient, err := neatcli.Dial(fmt.Sprintf("http://127.0.0.1:%d/%s", rpcPort, testSideChainId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mainClient, err := neatcli.Dial(fmt.Sprintf("http://127.0.0.1:%d/%s", rpcPort, MainChain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	relayerKey, _ := crypto.GenerateKey()
+	stateFile := filepath.Join(datadir, "relayer", testSideChainId+".json")
+	r, err := newRelayer(testSideChainId, MainChain, sideClient, mainClient, relayerKey, stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.state.Started = true
+	if err := r.relay(nil); err != nil {
+		t.Fatalf("relay side chain error %v", err)
+	}
+	if r.state.Relayed < 1 {
+		t.Errorf("side chain blocks not relayed, relayed %v", r.state.Relayed)
+	}
+	resumed, err := newRelayer(testSideChainId, MainChain, sideClient, mainClient, relayerKey, stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.state != r.state {
+		t.Errorf("relayer state not recovered, got %v want %v", resumed.state, r.state)
+	}
+
+	// the owner sets the block reward of the side chain, having no balance there
+	// it pays a zero gas price
+	cm.createSideChainLock.Lock()
+	sideNode := cm.sideChains[testSideChainId].NeatNode
+	cm.createSideChainLock.Unlock()
+	sideKs := sideNode.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	if err := sideKs.Unlock(accounts.Account{Address: from}, DefaultAccountPassword); err != nil {
+		t.Fatalf("unlock validator account on the side chain error %v", err)
+	}
+	sideRpc, err := sideNode.Attach()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sideRpc.Close()
+
+	sideState, err := MustGetNeatChainFromNode(sideNode).BlockChain().State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner := sideState.GetState(abi.NeatioSideChainsAddress, abi.SideChainOwnerKey); common.BytesToAddress(owner.Bytes()) != from {
+		t.Errorf("side chain owner in the genesis, got %x want %x", owner, from)
+	}
+
+	reward := big.NewInt(1e18)
+	if err := sideRpc.Call(&txHash, "chain_setBlockReward", from, (*hexutil.Big)(reward), (*hexutil.Big)(new(big.Int))); err != nil {
+		t.Fatalf("set block reward error %v", err)
+	}
+	waitFor(t, "side chain block reward set", func() bool {
+		state, err := MustGetNeatChainFromNode(sideNode).BlockChain().State()
+		return err == nil && state.GetSideChainRewardPerBlock().Cmp(reward) == 0
+	})
+	var pool neatapi.RewardPool
+	if err := sideRpc.Call(&pool, "chain_getRewardPool", "latest"); err != nil {
+		t.Fatalf("get reward pool error %v", err)
+	}
+	if (*big.Int)(pool.RewardPerBlock).Cmp(reward) != 0 || pool.RemainingBlocks == nil || len(pool.Votes) != 0 {
+		t.Errorf("reward pool mismatch, got %+v", pool)
+	}
+
+	select {
+	case ev := <-events:
+		if ev.Type != "launch" || ev.ChainId != testSideChainId {
+			t.Errorf("launch event mismatch, got %v", ev)
+		}
+	case <-time.After(10 * time.Second):
+		t.Errorf("launch event not received")
+	}
+
+	var info neatapi.SideChainInfo
+	if err := client.Call(&info, "chain_getSideChain", testSideChainId); err != nil {
+		t.Fatalf("get side chain error %v", err)
+	}
+	if info.Status != "running" || info.Owner != from || len(info.Validators) != 1 || info.Epoch == nil || info.LastAnchoredHeight == nil {
+		t.Errorf("side chain info mismatch, got %+v", info)
+	}
+	var running []*neatapi.SideChainInfo
+	if err := client.Call(&running, "chain_listSideChains"); err != nil {
+		t.Fatalf("list side chains error %v", err)
+	}
+	if len(running) != 1 || running[0].ChainId != testSideChainId {
+		t.Errorf("running side chains mismatch, got %v", running)
+	}
+
+	ci := core.GetChainInfo(cm.cch.chainInfoDB, testSideChainId)
+	if ci.Owner != from || ci.Epoch == nil || !ci.Epoch.Validators.HasAddress(from.Bytes()) {
+		t.Errorf("side chain info mismatch, owner %x, epoch %v", ci.Owner, ci.Epoch)
+	}
+
+	state, err := neatio.BlockChain().State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := state.GetSideChainDepositBalance(testSideChainId, from); got.Sign() != 0 {
+		t.Errorf("deposit still locked after launch, got %v", got)
+	}
+	if got, want := state.GetChainBalance(from), new(big.Int).Mul(minDeposit, big.NewInt(2)); got.Cmp(want) != 0 {
+		t.Errorf("chain balance of the owner, got %v want %v", got, want)
+	}
+
+	cm.createSideChainLock.Lock()
+	sideHeader := MustGetNeatChainFromNode(cm.sideChains[testSideChainId].NeatNode).BlockChain().CurrentHeader()
+	cm.createSideChainLock.Unlock()
+	proofData, err := rlp.EncodeToBytes(types.SideChainProofData{Header: sideHeader})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	balance := state.GetBalance(from)
+	if err := client.Call(&txHash, "chain_decommissionSideChain", from, testSideChainId, hexutil.Bytes(proofData), nil); err != nil {
+		t.Fatalf("decommission side chain error %v", err)
+	}
+	waitFor(t, "side chain decommissioned", func() bool {
+		return core.GetDecommissionedChain(cm.cch.chainInfoDB, testSideChainId) != nil
+	})
+	waitFor(t, "side chain stopped", func() bool {
+		cm.createSideChainLock.Lock()
+		defer cm.createSideChainLock.Unlock()
+
+		_, ok := cm.sideChains[testSideChainId]
+		return !ok
+	})
+
+	select {
+	case ev := <-events:
+		if ev.Type != "decommission" || ev.ChainId != testSideChainId {
+			t.Errorf("decommission event mismatch, got %v", ev)
+		}
+	case <-time.After(10 * time.Second):
+		t.Errorf("decommission event not received")
+	}
+	if err := client.Call(&info, "chain_getSideChain", testSideChainId); err != nil || info.Status != "decommissioned" {
+		t.Errorf("decommissioned side chain info, got %v err %v", info.Status, err)
+	}
+
+	dc := core.GetDecommissionedChain(cm.cch.chainInfoDB, testSideChainId)
+	if dc.FinalState.Height != sideHeader.Number.Uint64() || dc.FinalState.StateRoot != sideHeader.Root {
+		t.Errorf("final state mismatch, got %v want height %v root %x", dc.FinalState, sideHeader.Number, sideHeader.Root)
+	}
+	if core.CheckSideChainRunning(cm.cch.chainInfoDB, testSideChainId) {
+		t.Errorf("decommissioned side chain still in the side chain ids")
+	}
+
+	state, err = neatio.BlockChain().State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := state.GetChainBalance(from); got.Sign() != 0 {
+		t.Errorf("chain balance of the owner after decommission, got %v", got)
+	}
+	// both deposits are refunded, the balance only pays the gas
+	if got, min := state.GetBalance(from), new(big.Int).Add(balance, minDeposit); got.Cmp(min) <= 0 {
+		t.Errorf("deposits not refunded, balance %v before %v", got, balance)
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	timeout := time.After(3 * time.Minute)
+	for !cond() {
+		select {
+		case <-timeout:
+			t.Fatalf("timeout waiting for %s", what)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
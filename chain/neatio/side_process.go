@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neatio-net/neatio/chain/consensus/neatcon/consensus"
+	"github.com/neatio-net/neatio/chain/core/rawdb"
+	"github.com/neatio-net/neatio/chain/log"
+	"github.com/neatio-net/neatio/neatcli"
+	"github.com/neatio-net/neatio/network/node"
+	"github.com/neatio-net/neatio/utilities/utils"
+	"gopkg.in/urfave/cli.v1"
+)
+
+const (
+	sideProcessHealthFailures = 3
+
+	remoteCallTimeout = 10 * time.Second
+)
+
+// the supervisor timings, variables for the tests
+var (
+	sideProcessMinBackoff  = time.Second
+	sideProcessMaxBackoff  = time.Minute
+	sideProcessStableRun   = 5 * time.Minute
+	sideProcessStopTimeout = 30 * time.Second
+
+	sideProcessHealthInterval = 15 * time.Second
+)
+
+var (
+	sideChainParentFlag = cli.StringFlag{
+		Name:  "parent",
+		Usage: "IPC endpoint of the main chain node",
+	}
+	sideChainCommand = cli.Command{
+		Action:    utils.MigrateFlags(runSideChainProcess),
+		Name:      "sidechain",
+		Usage:     "Run a side chain in a child process of the main chain node",
+		ArgsUsage: "<chainId>",
+		Flags: []cli.Flag{
+			sideChainParentFlag,
+		},
+		Hidden:   true,
+		Category: "MISCELLANEOUS COMMANDS",
+		Description: `
+The sidechain command is started by the main chain node with --sidechain.process
+for each side chain it runs, it is not meant to be run by hand. The side chain
+reaches the main chain over the IPC endpoint of the parent, its own RPC is served
+on <datadir>/<chainId>/neatio.ipc.`,
+	}
+
+	// flags of the parent which are not passed on to the side chain processes
+	sideProcessSkipFlags = map[string]bool{
+		utils.ListenPortFlag.Name:       true,
+		utils.NodeKeyFileFlag.Name:      true,
+		utils.NodeKeyHexFlag.Name:       true,
+		utils.SideChainFlag.Name:        true,
+		utils.SideChainProcessFlag.Name: true,
+		"pprof":                         true,
+		"pprofport":                     true,
+		"pprofaddr":                     true,
+		"cpuprofile":                    true,
+		"trace":                         true,
+	}
+)
+
+// sideChainProcess supervises a side chain running in a child neatio process,
+// the child is restarted with backoff when it exits or stops answering on its
+// IPC endpoint
+type sideChainProcess struct {
+	chainId string
+	args    []string
+	ipc     string
+
+	command func() (*exec.Cmd, error) // builds the child process
+	check   func() error              // health check of the running child
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func (cm *ChainManager) sideChainProcessMode() bool {
+	return cm.ctx.GlobalBool(utils.SideChainProcessFlag.Name)
+}
+
+func (cm *ChainManager) newSideChainProcess(chainId string) *sideChainProcess {
+	// the children listen next to the parent, or on a random port as the parent does
+	port := cm.ctx.GlobalInt(utils.ListenPortFlag.Name)
+	if port != 0 {
+		cm.sidePorts++
+		port += cm.sidePorts
+	}
+
+	args := make([]string, 0)
+	for _, name := range cm.ctx.GlobalFlagNames() {
+		if sideProcessSkipFlags[name] || !cm.ctx.GlobalIsSet(name) {
+			continue
+		}
+		if value, ok := cm.ctx.GlobalGeneric(name).(flag.Value); ok {
+			args = append(args, fmt.Sprintf("--%s=%s", name, value.String()))
+		}
+	}
+	args = append(args, fmt.Sprintf("--%s=%d", utils.ListenPortFlag.Name, port))
+	args = append(args, sideChainCommand.Name, "--"+sideChainParentFlag.Name, cm.mainChain.NeatNode.IPCEndpoint(), chainId)
+
+	ipc := (&node.Config{DataDir: filepath.Join(cm.ctx.GlobalString(utils.DataDirFlag.Name), chainId), IPCPath: "neatio.ipc"}).IPCEndpoint()
+
+	p := &sideChainProcess{
+		chainId: chainId,
+		args:    args,
+		ipc:     ipc,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	p.command = p.childCommand
+	p.check = p.healthCheck
+	return p
+}
+
+func (p *sideChainProcess) Start() {
+	go p.loop()
+}
+
+// Stop terminates the child process and waits for the supervisor to return
+func (p *sideChainProcess) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+	<-p.done
+}
+
+func (p *sideChainProcess) loop() {
+	defer close(p.done)
+
+	backoff := sideProcessMinBackoff
+	for {
+		started := time.Now()
+		err := p.run()
+
+		select {
+		case <-p.stop:
+			log.Infof("Side chain process %v stopped", p.chainId)
+			return
+		default:
+		}
+
+		var wait time.Duration
+		wait, backoff = restartBackoff(backoff, time.Since(started))
+		log.Warn("Side chain process exited, restarting", "chain", p.chainId, "err", err, "backoff", wait)
+
+		select {
+		case <-p.stop:
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// restartBackoff returns the wait before restarting a child which ran for the
+// given time and the backoff of the next restart. The backoff doubles up to
+// the maximum and is reset once the child ran long enough to be stable
+func restartBackoff(backoff, ran time.Duration) (time.Duration, time.Duration) {
+	if ran > sideProcessStableRun {
+		backoff = sideProcessMinBackoff
+	}
+	next := backoff * 2
+	if next > sideProcessMaxBackoff {
+		next = sideProcessMaxBackoff
+	}
+	return backoff, next
+}
+
+// run starts the child and returns when it exits, the child is killed after
+// too many failed health checks
+func (p *sideChainProcess) run() error {
+	cmd, err := p.command()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	log.Info("Side chain process started", "chain", p.chainId, "pid", cmd.Process.Pid)
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	ticker := time.NewTicker(sideProcessHealthInterval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case err := <-exited:
+			return err
+
+		case <-p.stop:
+			cmd.Process.Signal(os.Interrupt)
+			select {
+			case err := <-exited:
+				return err
+			case <-time.After(sideProcessStopTimeout):
+				log.Warn("Side chain process did not stop in time, killing it", "chain", p.chainId)
+				cmd.Process.Kill()
+				return <-exited
+			}
+
+		case <-ticker.C:
+			if err := p.check(); err != nil {
+				failures++
+				log.Warn("Side chain process health check failed", "chain", p.chainId, "failures", failures, "err", err)
+				if failures >= sideProcessHealthFailures {
+					cmd.Process.Kill()
+				}
+			} else {
+				failures = 0
+			}
+		}
+	}
+}
+
+// childCommand runs the side chain in a child neatio process
+func (p *sideChainProcess) childCommand() (*exec.Cmd, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(exe, p.args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	setSideChainProcessAttr(cmd)
+	return cmd, nil
+}
+
+func (p *sideChainProcess) healthCheck() error {
+	client, err := neatcli.Dial(p.ipc)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), remoteCallTimeout)
+	defer cancel()
+
+	_, err = client.BlockNumber(ctx)
+	return err
+}
+
+// InitRemoteCrossChainHelper connects the side chain process to the main chain
+// node over its IPC endpoint, the chain info db stays with the main chain node
+func (cm *ChainManager) InitRemoteCrossChainHelper(endpoint, chainId string) error {
+	client, err := neatcli.Dial(endpoint)
+	if err != nil {
+		return err
+	}
+	cm.cch.client = client
+	cm.cch.remote = true
+
+	cm.cch.localTX3CacheDB, err = rawdb.NewLevelDBDatabase(path.Join(cm.ctx.GlobalString(utils.DataDirFlag.Name), chainId, "tx3cache"), 0, 0, "neatio/db/tx3/")
+	if err != nil {
+		return err
+	}
+
+	mainChainId := MainChain
+	if cm.ctx.GlobalBool(utils.TestnetFlag.Name) {
+		mainChainId = TestnetChain
+	}
+	cm.cch.mainChainId = mainChainId
+
+	return nil
+}
+
+func runSideChainProcess(ctx *cli.Context) error {
+	chainId := ctx.Args().First()
+	if chainId == "" || strings.Contains(chainId, ",") {
+		utils.Fatalf("This command requires one side chain id")
+	}
+	parent := ctx.String(sideChainParentFlag.Name)
+	if parent == "" {
+		utils.Fatalf("The IPC endpoint of the main chain node is required (--%s)", sideChainParentFlag.Name)
+	}
+
+	chainMgr := GetCMInstance(ctx)
+	chainMgr.InitP2P()
+
+	if err := chainMgr.InitRemoteCrossChainHelper(parent, chainId); err != nil {
+		return fmt.Errorf("failed to connect to the main chain node: %v", err)
+	}
+
+	chain := LoadSideChain(ctx, chainId)
+	if chain == nil {
+		return errors.New("load side chain failed")
+	}
+	chainMgr.sideChains[chainId] = chain
+
+	// the side chain process has its own node key and node database
+	dataDir := filepath.Join(ctx.GlobalString(utils.DataDirFlag.Name), chainId)
+	nodeConfig := &node.Config{GeneralDataDir: dataDir, DataDir: dataDir}
+	srv := chainMgr.server.Server()
+	srv.PrivateKey = nodeConfig.NodeKey()
+	srv.NodeDatabase = nodeConfig.NodeDB()
+	if err := srv.Start(); err != nil {
+		return err
+	}
+	consensus.NodeID = chainMgr.GetNodeID()[0:16]
+
+	if err := chainMgr.StartChains(); err != nil {
+		return err
+	}
+
+	go handleInterrupt(chainMgr)
+
+	chainMgr.Wait()
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neatio-net/neatio/neatcli"
+	"github.com/neatio-net/neatio/network/rpc"
+	"github.com/neatio-net/neatio/utilities/common/hexutil"
+	"github.com/neatio-net/neatio/utilities/utils"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func setSideProcessTimings(t *testing.T, minBackoff, maxBackoff, stableRun, healthInterval time.Duration) {
+	oldMin, oldMax, oldStable, oldHealth := sideProcessMinBackoff, sideProcessMaxBackoff, sideProcessStableRun, sideProcessHealthInterval
+	sideProcessMinBackoff, sideProcessMaxBackoff, sideProcessStableRun, sideProcessHealthInterval = minBackoff, maxBackoff, stableRun, healthInterval
+	t.Cleanup(func() {
+		sideProcessMinBackoff, sideProcessMaxBackoff, sideProcessStableRun, sideProcessHealthInterval = oldMin, oldMax, oldStable, oldHealth
+	})
+}
+
+func TestRestartBackoff(t *testing.T) {
+	setSideProcessTimings(t, time.Second, 8*time.Second, time.Minute, time.Second)
+
+	tests := []struct {
+		backoff, ran time.Duration
+		wait, next   time.Duration
+	}{
+		{time.Second, 0, time.Second, 2 * time.Second},
+		{2 * time.Second, time.Second, 2 * time.Second, 4 * time.Second},
+		{4 * time.Second, time.Second, 4 * time.Second, 8 * time.Second},
+		{8 * time.Second, time.Second, 8 * time.Second, 8 * time.Second},
+		// a stable run resets the backoff
+		{8 * time.Second, 2 * time.Minute, time.Second, 2 * time.Second},
+	}
+	for i, tt := range tests {
+		wait, next := restartBackoff(tt.backoff, tt.ran)
+		if wait != tt.wait || next != tt.next {
+			t.Errorf("test %d: got wait %v next %v, want %v %v", i, wait, next, tt.wait, tt.next)
+		}
+	}
+}
+
+func newTestSideChainProcess(command func() (*exec.Cmd, error), check func() error) *sideChainProcess {
+	return &sideChainProcess{
+		chainId: testSideChainId,
+		command: command,
+		check:   check,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+func waitRuns(t *testing.T, runs *int32, want int32) {
+	deadline := time.Now().Add(10 * time.Second)
+	for atomic.LoadInt32(runs) < want {
+		if time.Now().After(deadline) {
+			t.Fatalf("child started %d times, want %d", atomic.LoadInt32(runs), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func stopSideChainProcess(t *testing.T, p *sideChainProcess) {
+	stopped := make(chan struct{})
+	go func() {
+		p.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("side chain process not stopped")
+	}
+}
+
+func TestSideChainProcessRestart(t *testing.T) {
+	setSideProcessTimings(t, 10*time.Millisecond, 40*time.Millisecond, time.Minute, time.Minute)
+
+	var runs int32
+	p := newTestSideChainProcess(func() (*exec.Cmd, error) {
+		atomic.AddInt32(&runs, 1)
+		return exec.Command("sh", "-c", "exit 1"), nil
+	}, nil)
+	p.Start()
+
+	// the exited child is started again
+	waitRuns(t, &runs, 4)
+	stopSideChainProcess(t, p)
+
+	// the command error is retried like an exit
+	var failed int32
+	p = newTestSideChainProcess(func() (*exec.Cmd, error) {
+		atomic.AddInt32(&failed, 1)
+		return nil, errors.New("no executable")
+	}, nil)
+	p.Start()
+	waitRuns(t, &failed, 2)
+	stopSideChainProcess(t, p)
+}
+
+func TestSideChainProcessHealthCheck(t *testing.T) {
+	setSideProcessTimings(t, 10*time.Millisecond, 40*time.Millisecond, time.Minute, 10*time.Millisecond)
+
+	var runs, checks int32
+	p := newTestSideChainProcess(func() (*exec.Cmd, error) {
+		atomic.AddInt32(&runs, 1)
+		return exec.Command("sleep", "60"), nil
+	}, func() error {
+		atomic.AddInt32(&checks, 1)
+		return errors.New("not answering")
+	})
+	p.Start()
+
+	// the child is killed after the failed health checks and started again
+	waitRuns(t, &runs, 2)
+	if c := atomic.LoadInt32(&checks); c < sideProcessHealthFailures {
+		t.Errorf("child killed after %d health checks, want %d", c, sideProcessHealthFailures)
+	}
+	stopSideChainProcess(t, p)
+}
+
+func TestSideChainProcessStop(t *testing.T) {
+	setSideProcessTimings(t, 10*time.Millisecond, 40*time.Millisecond, time.Minute, time.Minute)
+
+	var runs int32
+	p := newTestSideChainProcess(func() (*exec.Cmd, error) {
+		atomic.AddInt32(&runs, 1)
+		return exec.Command("sleep", "60"), nil
+	}, func() error { return nil })
+	p.Start()
+
+	waitRuns(t, &runs, 1)
+	// the healthy child is interrupted and not started again
+	stopSideChainProcess(t, p)
+	if r := atomic.LoadInt32(&runs); r != 1 {
+		t.Errorf("child started %d times, want 1", r)
+	}
+}
+
+type testMainChainService struct {
+	height uint64
+}
+
+func (s *testMainChainService) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(s.height)
+}
+
+type testChainService struct{}
+
+func (s *testChainService) GetSideChain(chainId string) *neatcli.SideChain {
+	if chainId != testSideChainId {
+		return nil
+	}
+	return &neatcli.SideChain{ChainId: chainId, Status: "running"}
+}
+
+func TestRemoteCrossChainHelper(t *testing.T) {
+	datadir := tmpdir(t)
+	defer os.RemoveAll(datadir)
+
+	endpoint := filepath.Join(datadir, "main.ipc")
+	listener, server, err := rpc.StartIPCEndpoint(endpoint, []rpc.API{
+		{Namespace: "eth", Version: "1.0", Service: &testMainChainService{height: 42}, Public: true},
+		{Namespace: "chain", Version: "1.0", Service: &testChainService{}, Public: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	set := flag.NewFlagSet("neatio-test", flag.ContinueOnError)
+	utils.DataDirFlag.Apply(set)
+	utils.TestnetFlag.Apply(set)
+	if err := set.Parse([]string{"--" + utils.DataDirFlag.Name, datadir}); err != nil {
+		t.Fatal(err)
+	}
+	cm := &ChainManager{ctx: cli.NewContext(app, set, nil), cch: &CrossChainHelper{}}
+	if err := cm.InitRemoteCrossChainHelper(endpoint, testSideChainId); err != nil {
+		t.Fatalf("init remote cross chain helper error %v", err)
+	}
+	cch := cm.cch
+	defer cch.localTX3CacheDB.Close()
+	defer cch.client.Close()
+
+	if cch.GetMainChainId() != MainChain {
+		t.Errorf("main chain id, got %v want %v", cch.GetMainChainId(), MainChain)
+	}
+	if height, err := cch.GetHeightFromMainChain(); err != nil || height.Uint64() != 42 {
+		t.Errorf("main chain height, got %v %v want 42", height, err)
+	}
+	if !cch.CheckSideChainRunning(testSideChainId) || cch.CheckSideChainRunning("unknown") {
+		t.Errorf("side chain running mismatch")
+	}
+	if chainId, ep := cch.GetEpochFromMainChain(); chainId != MainChain || ep != nil {
+		t.Errorf("epoch from main chain, got %v %v", chainId, ep)
+	}
+
+	// the main chain node is gone, the height is not served from a cache
+	listener.Close()
+	server.Stop()
+	if height, err := cch.GetHeightFromMainChain(); err == nil {
+		t.Errorf("expected error with the main chain node stopped, got height %v", height)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"crypto/tls"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/neatio-net/neatio/chain/consensus/neatcon/types"
+	"github.com/neatio-net/neatio/chain/log"
+	"github.com/neatio-net/neatio/utilities/utils"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	signerListenAddrFlag = cli.StringFlag{
+		Name:  "laddr",
+		Usage: "Address the signer listens on, unix:///path/to/socket or tcp://host:port",
+	}
+	signerPrivValidatorFlag = cli.StringFlag{
+		Name:  "privvalidator",
+		Usage: "priv_validator.json holding the consensus key, instead of the one of the chain in the data directory",
+	}
+	signerTLSCertFlag = cli.StringFlag{
+		Name:  "tls.cert",
+		Usage: "TLS certificate of the signer, required for tcp",
+	}
+	signerTLSKeyFlag = cli.StringFlag{
+		Name:  "tls.key",
+		Usage: "TLS private key of the signer, required for tcp",
+	}
+	signerTLSCAFlag = cli.StringFlag{
+		Name:  "tls.ca",
+		Usage: "CA issuing the certificates of the nodes allowed to connect, required for tcp",
+	}
+	signerCommand = cli.Command{
+		Action:    utils.MigrateFlags(runSigner),
+		Name:      "signer",
+		Usage:     "Run a remote signer holding the consensus key",
+		ArgsUsage: "[<chainId>]",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			signerListenAddrFlag,
+			signerPrivValidatorFlag,
+			signerTLSCertFlag,
+			signerTLSKeyFlag,
+			signerTLSCAFlag,
+		},
+		Category: "MISCELLANEOUS COMMANDS",
+		Description: `
+The signer command keeps the consensus key of a validator in a separate process,
+so it does not have to be on the internet facing node. The node connects to it
+when priv_validator_laddr is set in its config.toml, tcp connections need
+priv_validator_tls_cert, priv_validator_tls_key and priv_validator_tls_ca too.
+
+The signer saves the last signed height/round/step in the priv_validator file
+and refuses to sign anything conflicting with it. Run only one signer per key.`,
+	}
+)
+
+func runSigner(ctx *cli.Context) error {
+	laddr := ctx.String(signerListenAddrFlag.Name)
+	if laddr == "" {
+		utils.Fatalf("--%v is required", signerListenAddrFlag.Name)
+	}
+
+	file := ctx.String(signerPrivValidatorFlag.Name)
+	if file == "" {
+		chainId := ctx.Args().First()
+		if chainId == "" {
+			chainId = MainChain
+			if ctx.GlobalBool(utils.TestnetFlag.Name) {
+				chainId = TestnetChain
+			}
+		}
+		file = utils.GetNeatConConfig(chainId, ctx).GetString("priv_validator_file")
+	}
+	if _, err := os.Stat(file); err != nil {
+		utils.Fatalf("Can not open priv validator file: %v", err)
+	}
+	privVal := types.LoadPrivValidator(file)
+
+	var tlsConfig *tls.Config
+	if strings.HasPrefix(laddr, "tcp://") {
+		var err error
+		tlsConfig, err = types.LoadSignerTLSConfig(ctx.String(signerTLSCertFlag.Name), ctx.String(signerTLSKeyFlag.Name), ctx.String(signerTLSCAFlag.Name), true)
+		if err != nil {
+			utils.Fatalf("Failed to load TLS config: %v", err)
+		}
+	}
+
+	listener, err := types.ListenSigner(laddr, tlsConfig)
+	if err != nil {
+		utils.Fatalf("Failed to listen on %v: %v", laddr, err)
+	}
+	if strings.HasPrefix(laddr, "unix://") {
+		os.Chmod(strings.TrimPrefix(laddr, "unix://"), 0600)
+	}
+
+	server := types.NewSignerServer(listener, privVal, log.Root())
+	go func() {
+		sigc := make(chan os.Signal, 1)
+		signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+		<-sigc
+		log.Info("Shutting down signer")
+		server.Stop()
+	}()
+
+	log.Infof("Signer for validator %x listening on %v", privVal.Address, laddr)
+	return server.Serve()
+}
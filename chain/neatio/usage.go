@@ -69,6 +69,8 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TxPoolAccountQueueFlag,
 			utils.TxPoolGlobalQueueFlag,
 			utils.TxPoolLifetimeFlag,
+			utils.TxPoolSpecialSlotsFlag,
+			utils.TxPoolSpecialAccountSlotsFlag,
 		},
 	},
 	{
@@ -134,6 +136,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.MinerGasTargetFlag,
 			utils.MinerGasLimitFlag,
 			utils.MinerCoinbaseFlag,
+			utils.MinerAutoRevealFlag,
 			utils.ExtraDataFlag,
 		},
 	},
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/neatio-net/crypto-go"
+	"github.com/neatio-net/neatio/chain/consensus/neatcon/types"
+	"github.com/neatio-net/neatio/network/node"
+	"github.com/neatio-net/neatio/network/rpc"
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/common/hexutil"
+	"github.com/neatio-net/neatio/utilities/utils"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	validatorAttachFlag = cli.StringFlag{
+		Name:  "attach",
+		Usage: "API endpoint of the running node (default = <datadir>/neatio.ipc)",
+	}
+	validatorAmountFlag = cli.StringFlag{
+		Name:  "amount",
+		Usage: "Voting power for the next epoch in wei, delegations included, 0 to leave the validator set",
+	}
+	validatorPubKeyFlag = cli.StringFlag{
+		Name:  "pubkey",
+		Usage: "Consensus public key in hex, read from the priv validator file if not set",
+	}
+	validatorSignatureFlag = cli.StringFlag{
+		Name:  "signature",
+		Usage: "Signature of the address by the consensus key in hex, made from the priv validator file if not set",
+	}
+	validatorPrivValidatorFlag = cli.StringFlag{
+		Name:  "privvalidator",
+		Usage: "priv_validator.json holding the consensus key (default = the one of the main chain)",
+	}
+	validatorCommand = cli.Command{
+		Name:     "validator",
+		Usage:    "Vote for the validator set of the next epoch",
+		Category: "ACCOUNT COMMANDS",
+		Description: `
+The votes for the validator set of the next epoch are sent in two steps. The
+hash of the vote is sent first, and the vote itself is revealed later in the
+epoch. The salt of the vote is kept in <keystore>/votes of the running node
+until it is revealed, start the node with --miner.autoreveal to reveal it
+automatically once the reveal stage opens.`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "vote",
+				Usage:     "Send the hash of the vote for the next epoch",
+				Action:    utils.MigrateFlags(validatorVote),
+				ArgsUsage: "<address>",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.PasswordFileFlag,
+					validatorAttachFlag,
+					validatorAmountFlag,
+					validatorPubKeyFlag,
+					validatorSignatureFlag,
+					validatorPrivValidatorFlag,
+				},
+				Description: `
+    neatio validator vote --amount <wei> <address>
+
+Sends the hash of the vote for the next epoch through the running node, the
+account is unlocked with --password if given. The hash vote stage lasts until
+75% of the epoch.`,
+			},
+			{
+				Name:      "reveal",
+				Usage:     "Reveal the vote for the next epoch",
+				Action:    utils.MigrateFlags(validatorReveal),
+				ArgsUsage: "<address>",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.PasswordFileFlag,
+					validatorAttachFlag,
+				},
+				Description: `
+    neatio validator reveal <address>
+
+Reveals the vote saved by "neatio validator vote". The reveal stage lasts from
+75% to 85% of the epoch.`,
+			},
+		},
+	}
+)
+
+func validatorVote(ctx *cli.Context) error {
+	from := validatorAddress(ctx)
+
+	amount, ok := new(big.Int).SetString(ctx.String(validatorAmountFlag.Name), 10)
+	if !ok || amount.Sign() < 0 {
+		utils.Fatalf("Invalid --%v", validatorAmountFlag.Name)
+	}
+
+	var pubkey crypto.BLSPubKey
+	var signature []byte
+	if ctx.IsSet(validatorPubKeyFlag.Name) {
+		pubkeyBytes := common.FromHex(ctx.String(validatorPubKeyFlag.Name))
+		if len(pubkeyBytes) != len(pubkey) {
+			utils.Fatalf("Invalid consensus public key")
+		}
+		copy(pubkey[:], pubkeyBytes)
+		signature = common.FromHex(ctx.String(validatorSignatureFlag.Name))
+	} else {
+		file := ctx.String(validatorPrivValidatorFlag.Name)
+		if file == "" {
+			file = utils.GetNeatConConfig(validatorChainId(ctx), ctx).GetString("priv_validator_file")
+		}
+		if _, err := os.Stat(file); err != nil {
+			utils.Fatalf("Can not open priv validator file: %v", err)
+		}
+		privVal := types.LoadPrivValidator(file)
+		blsPubKey, ok := privVal.PubKey.(crypto.BLSPubKey)
+		if !ok {
+			utils.Fatalf("Unexpected consensus public key type in %v", file)
+		}
+		pubkey = blsPubKey
+		signature = privVal.PrivKey.Sign(from.Bytes()).Bytes()
+	}
+	if err := crypto.CheckConsensusPubKey(from, pubkey.Bytes(), signature); err != nil {
+		utils.Fatalf("Invalid consensus key: %v", err)
+	}
+
+	client := validatorClient(ctx, from)
+	defer client.Close()
+
+	var hash common.Hash
+	if err := client.Call(&hash, "neat_voteNextEpoch", from, pubkey, (*hexutil.Big)(amount), hexutil.Bytes(signature), nil); err != nil {
+		utils.Fatalf("Failed to vote: %v", err)
+	}
+	fmt.Printf("Vote hash sent, tx hash: %x\n", hash)
+	return nil
+}
+
+func validatorReveal(ctx *cli.Context) error {
+	from := validatorAddress(ctx)
+
+	client := validatorClient(ctx, from)
+	defer client.Close()
+
+	var hash common.Hash
+	if err := client.Call(&hash, "neat_revealVote", from, nil); err != nil {
+		utils.Fatalf("Failed to reveal the vote: %v", err)
+	}
+	fmt.Printf("Vote revealed, tx hash: %x\n", hash)
+	return nil
+}
+
+func validatorAddress(ctx *cli.Context) common.Address {
+	if !common.IsHexAddress(ctx.Args().First()) {
+		utils.Fatalf("A valid address is required")
+	}
+	return common.HexToAddress(ctx.Args().First())
+}
+
+func validatorChainId(ctx *cli.Context) string {
+	if ctx.GlobalBool(utils.TestnetFlag.Name) {
+		return TestnetChain
+	}
+	return MainChain
+}
+
+// validatorClient attaches to the running node and unlocks the account if a password file is given
+func validatorClient(ctx *cli.Context, from common.Address) *rpc.Client {
+	endpoint := ctx.String(validatorAttachFlag.Name)
+	if endpoint == "" {
+		path := node.DefaultDataDir()
+		if ctx.GlobalIsSet(utils.DataDirFlag.Name) {
+			path = ctx.GlobalString(utils.DataDirFlag.Name)
+		}
+		if ctx.GlobalBool(utils.TestnetFlag.Name) {
+			path = filepath.Join(path, "testnet")
+		}
+		endpoint = fmt.Sprintf("%s/neatio.ipc", path)
+	}
+	client, err := dialRPC(endpoint)
+	if err != nil {
+		utils.Fatalf("Unable to attach to remote neatio: %v", err)
+	}
+
+	if passwords := utils.MakePasswordList(ctx); len(passwords) > 0 {
+		var unlocked bool
+		if err := client.Call(&unlocked, "personal_unlockAccount", from, passwords[0], 60); err != nil {
+			utils.Fatalf("Failed to unlock account %x: %v", from, err)
+		}
+	}
+	return client
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/neatio-net/neatio/chain/consensus/neatcon/consensus"
+	"github.com/neatio-net/neatio/utilities/utils"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	walInspectFileFlag = cli.StringFlag{
+		Name:  "file",
+		Usage: "WAL file to dump, instead of the one of the chain in the data directory",
+	}
+	walInspectHeightFlag = cli.Uint64Flag{
+		Name:  "height",
+		Usage: "Only dump the messages of this height",
+	}
+	walInspectPrevFlag = cli.BoolFlag{
+		Name:  "prev",
+		Usage: "Dump the rotated WAL file before the current one",
+	}
+	walInspectCommand = cli.Command{
+		Action:    utils.MigrateFlags(walInspect),
+		Name:      "wal-inspect",
+		Usage:     "Dump the consensus write ahead log",
+		ArgsUsage: "[<chainId>]",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			walInspectFileFlag,
+			walInspectHeightFlag,
+			walInspectPrevFlag,
+		},
+		Category: "MISCELLANEOUS COMMANDS",
+		Description: `
+The wal-inspect command prints the messages, timeouts and signatures recorded
+by the consensus state of a validator, one line per record, for post-mortems.
+Stop the node before inspecting its WAL.`,
+	}
+)
+
+func walInspect(ctx *cli.Context) error {
+	file := ctx.String(walInspectFileFlag.Name)
+	if file == "" {
+		chainId := ctx.Args().First()
+		if chainId == "" {
+			chainId = MainChain
+			if ctx.GlobalBool(utils.TestnetFlag.Name) {
+				chainId = TestnetChain
+			}
+		}
+		file = utils.GetNeatConConfig(chainId, ctx).GetString("cs_wal_file")
+	}
+	if ctx.Bool(walInspectPrevFlag.Name) {
+		file += ".1"
+	}
+
+	if _, err := os.Stat(file); err != nil {
+		utils.Fatalf("Can not open WAL file: %v", err)
+	}
+
+	msgs, err := consensus.ReadWALFile(file)
+	height := ctx.Uint64(walInspectHeightFlag.Name)
+	for _, msg := range msgs {
+		if height != 0 && consensus.WALMessageHeight(msg.Msg) != height {
+			continue
+		}
+		fmt.Println(msg.String())
+	}
+	if err != nil {
+		utils.Fatalf("WAL %v is broken after %v records: %v", file, len(msgs), err)
+	}
+
+	return nil
+}
@@ -6,11 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/neatio-net/neatio/chain/consensus"
 	"github.com/neatio-net/neatio/chain/consensus/neatcon/epoch"
+	ntcTypes "github.com/neatio-net/neatio/chain/consensus/neatcon/types"
 	"github.com/neatio-net/neatio/chain/core/state"
 
 	goCrypto "github.com/neatio-net/crypto-go"
@@ -44,6 +46,9 @@ var (
 
 	maxDelegationAddresses = 1000
 
+	// redelegations of one delegator waiting for the next epoch
+	maxPendingRedelegations = 3
+
 	maxEditValidatorLength = 100
 )
 
@@ -60,6 +65,50 @@ func (s *PublicNEATChainAPI) GasPrice(ctx context.Context) (*hexutil.Big, error)
 	return (*hexutil.Big)(price), err
 }
 
+// MaxPriorityFeePerGas returns a suggestion for a gas tip cap for dynamic fee transactions.
+func (s *PublicNEATChainAPI) MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error) {
+	tipcap, err := s.b.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(tipcap), err
+}
+
+type feeHistoryResult struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+// FeeHistory returns the fee market history of the blocks ending with lastBlock.
+func (s *PublicNEATChainAPI) FeeHistory(ctx context.Context, blockCount rpc.DecimalOrHex, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*feeHistoryResult, error) {
+	oldest, reward, baseFee, gasUsed, err := s.b.FeeHistory(ctx, int(blockCount), lastBlock, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	results := &feeHistoryResult{
+		OldestBlock:  (*hexutil.Big)(oldest),
+		GasUsedRatio: gasUsed,
+	}
+	if reward != nil {
+		results.Reward = make([][]*hexutil.Big, len(reward))
+		for i, w := range reward {
+			results.Reward[i] = make([]*hexutil.Big, len(w))
+			for j, v := range w {
+				results.Reward[i][j] = (*hexutil.Big)(v)
+			}
+		}
+	}
+	if baseFee != nil {
+		results.BaseFee = make([]*hexutil.Big, len(baseFee))
+		for i, v := range baseFee {
+			results.BaseFee[i] = (*hexutil.Big)(v)
+		}
+	}
+	return results, nil
+}
+
 func (s *PublicNEATChainAPI) ProtocolVersion() hexutil.Uint {
 	return hexutil.Uint(s.b.ProtocolVersion())
 }
@@ -326,8 +375,8 @@ func (s *PrivateAccountAPI) SignTransaction(ctx context.Context, args SendTxArgs
 	if args.Gas == nil {
 		return nil, fmt.Errorf("gas not specified")
 	}
-	if args.GasPrice == nil {
-		return nil, fmt.Errorf("gasPrice not specified")
+	if args.GasPrice == nil && (args.MaxFeePerGas == nil || args.MaxPriorityFeePerGas == nil) {
+		return nil, fmt.Errorf("missing gasPrice or maxFeePerGas/maxPriorityFeePerGas")
 	}
 	if args.Nonce == nil {
 		return nil, fmt.Errorf("nonce not specified")
@@ -336,7 +385,7 @@ func (s *PrivateAccountAPI) SignTransaction(ctx context.Context, args SendTxArgs
 	if err != nil {
 		return nil, err
 	}
-	data, err := rlp.EncodeToBytes(signed)
+	data, err := signed.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
@@ -555,6 +604,11 @@ type CallArgs struct {
 	GasPrice hexutil.Big     `json:"gasPrice"`
 	Value    hexutil.Big     `json:"value"`
 	Data     hexutil.Bytes   `json:"data"`
+
+	AccessList *types.AccessList `json:"accessList,omitempty"`
+
+	MaxFeePerGas         *hexutil.Big `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big `json:"maxPriorityFeePerGas,omitempty"`
 }
 
 func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, vmCfg vm.Config, timeout time.Duration) (*core.ExecutionResult, error) {
@@ -581,8 +635,29 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 	if gasPrice.Sign() == 0 {
 		gasPrice = new(big.Int).SetUint64(defaultGasPrice)
 	}
+	gasFeeCap, gasTipCap := gasPrice, gasPrice
+	if args.MaxFeePerGas != nil || args.MaxPriorityFeePerGas != nil {
+		gasFeeCap, gasTipCap = new(big.Int), new(big.Int)
+		if args.MaxFeePerGas != nil {
+			gasFeeCap = args.MaxFeePerGas.ToInt()
+		}
+		if args.MaxPriorityFeePerGas != nil {
+			gasTipCap = args.MaxPriorityFeePerGas.ToInt()
+		}
+		gasPrice = new(big.Int).Set(gasFeeCap)
+		if header.BaseFee != nil {
+			gasPrice = math.BigMin(new(big.Int).Add(gasTipCap, header.BaseFee), gasFeeCap)
+		}
+	}
+
+	var accessList types.AccessList
+	if args.AccessList != nil {
+		accessList = *args.AccessList
+	}
+	msg := types.NewMessage(addr, args.To, 0, args.Value.ToInt(), gas, gasPrice, gasFeeCap, gasTipCap, args.Data, accessList, false)
 
-	msg := types.NewMessage(addr, args.To, 0, args.Value.ToInt(), gas, gasPrice, args.Data, false)
+	// Calls are not charged, so the base fee of the block is not enforced
+	vmCfg.NoBaseFee = true
 
 	var cancel context.CancelFunc
 	if timeout > 0 {
@@ -808,6 +883,10 @@ func (s *PublicBlockChainAPI) rpcOutputBlock(b *types.Block, inclTx bool, fullTx
 		"receiptsRoot":     head.ReceiptHash,
 	}
 
+	if head.BaseFee != nil {
+		fields["baseFeePerGas"] = (*hexutil.Big)(head.BaseFee)
+	}
+
 	if inclTx {
 		formatTx := func(tx *types.Transaction) (interface{}, error) {
 			return tx.Hash(), nil
@@ -841,26 +920,31 @@ func (s *PublicBlockChainAPI) rpcOutputBlock(b *types.Block, inclTx bool, fullTx
 }
 
 type RPCTransaction struct {
-	BlockHash        common.Hash     `json:"blockHash"`
-	BlockNumber      *hexutil.Big    `json:"blockNumber"`
-	From             common.Address  `json:"from"`
-	Gas              hexutil.Uint64  `json:"gas"`
-	GasPrice         *hexutil.Big    `json:"gasPrice"`
-	Hash             common.Hash     `json:"hash"`
-	Input            hexutil.Bytes   `json:"input"`
-	Nonce            hexutil.Uint64  `json:"nonce"`
-	To               *common.Address `json:"to"`
-	TransactionIndex hexutil.Uint    `json:"transactionIndex"`
-	Value            *hexutil.Big    `json:"value"`
-	V                *hexutil.Big    `json:"v"`
-	R                *hexutil.Big    `json:"r"`
-	S                *hexutil.Big    `json:"s"`
-}
-
-func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber uint64, index uint64) *RPCTransaction {
+	BlockHash        common.Hash       `json:"blockHash"`
+	BlockNumber      *hexutil.Big      `json:"blockNumber"`
+	From             common.Address    `json:"from"`
+	Gas              hexutil.Uint64    `json:"gas"`
+	GasPrice         *hexutil.Big      `json:"gasPrice"`
+	GasFeeCap        *hexutil.Big      `json:"maxFeePerGas,omitempty"`
+	GasTipCap        *hexutil.Big      `json:"maxPriorityFeePerGas,omitempty"`
+	Hash             common.Hash       `json:"hash"`
+	Input            hexutil.Bytes     `json:"input"`
+	Nonce            hexutil.Uint64    `json:"nonce"`
+	To               *common.Address   `json:"to"`
+	TransactionIndex hexutil.Uint      `json:"transactionIndex"`
+	Value            *hexutil.Big      `json:"value"`
+	Type             hexutil.Uint64    `json:"type"`
+	Accesses         *types.AccessList `json:"accessList,omitempty"`
+	ChainID          *hexutil.Big      `json:"chainId,omitempty"`
+	V                *hexutil.Big      `json:"v"`
+	R                *hexutil.Big      `json:"r"`
+	S                *hexutil.Big      `json:"s"`
+}
+
+func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber uint64, index uint64, baseFee *big.Int) *RPCTransaction {
 	var signer types.Signer = types.FrontierSigner{}
 	if tx.Protected() {
-		signer = types.NewEIP155Signer(tx.ChainId())
+		signer = types.LatestSignerForChainID(tx.ChainId())
 	}
 	from, _ := types.Sender(signer, tx)
 	v, r, s := tx.RawSignatureValues()
@@ -874,10 +958,27 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		Nonce:    hexutil.Uint64(tx.Nonce()),
 		To:       tx.To(),
 		Value:    (*hexutil.Big)(tx.Value()),
+		Type:     hexutil.Uint64(tx.Type()),
 		V:        (*hexutil.Big)(v),
 		R:        (*hexutil.Big)(r),
 		S:        (*hexutil.Big)(s),
 	}
+	switch tx.Type() {
+	case types.AccessListTxType:
+		al := tx.AccessList()
+		result.Accesses = &al
+		result.ChainID = (*hexutil.Big)(tx.ChainId())
+	case types.DynamicFeeTxType:
+		al := tx.AccessList()
+		result.Accesses = &al
+		result.ChainID = (*hexutil.Big)(tx.ChainId())
+		result.GasFeeCap = (*hexutil.Big)(tx.GasFeeCap())
+		result.GasTipCap = (*hexutil.Big)(tx.GasTipCap())
+		// the gas price is the effective one once the transaction is mined
+		if baseFee != nil && blockHash != (common.Hash{}) {
+			result.GasPrice = (*hexutil.Big)(math.BigMin(new(big.Int).Add(tx.GasTipCap(), baseFee), tx.GasFeeCap()))
+		}
+	}
 	if blockHash != (common.Hash{}) {
 		result.BlockHash = blockHash
 		result.BlockNumber = (*hexutil.Big)(new(big.Int).SetUint64(blockNumber))
@@ -887,7 +988,7 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 }
 
 func newRPCPendingTransaction(tx *types.Transaction) *RPCTransaction {
-	return newRPCTransaction(tx, common.Hash{}, 0, 0)
+	return newRPCTransaction(tx, common.Hash{}, 0, 0, nil)
 }
 
 func newRPCTransactionFromBlockIndex(b *types.Block, index uint64) *RPCTransaction {
@@ -895,7 +996,7 @@ func newRPCTransactionFromBlockIndex(b *types.Block, index uint64) *RPCTransacti
 	if index >= uint64(len(txs)) {
 		return nil
 	}
-	return newRPCTransaction(txs[index], b.Hash(), b.NumberU64(), index)
+	return newRPCTransaction(txs[index], b.Hash(), b.NumberU64(), index, b.BaseFee())
 }
 
 func newRPCRawTransactionFromBlockIndex(b *types.Block, index uint64) hexutil.Bytes {
@@ -903,7 +1004,7 @@ func newRPCRawTransactionFromBlockIndex(b *types.Block, index uint64) hexutil.By
 	if index >= uint64(len(txs)) {
 		return nil
 	}
-	blob, _ := rlp.EncodeToBytes(txs[index])
+	blob, _ := txs[index].MarshalBinary()
 	return blob
 }
 
@@ -981,7 +1082,11 @@ func (s *PublicTransactionPoolAPI) GetTransactionCount(ctx context.Context, addr
 func (s *PublicTransactionPoolAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) *RPCTransaction {
 
 	if tx, blockHash, blockNumber, index := rawdb.ReadTransaction(s.b.ChainDb(), hash); tx != nil {
-		return newRPCTransaction(tx, blockHash, blockNumber, index)
+		var baseFee *big.Int
+		if header, _ := s.b.HeaderByNumber(ctx, rpc.BlockNumber(blockNumber)); header != nil {
+			baseFee = header.BaseFee
+		}
+		return newRPCTransaction(tx, blockHash, blockNumber, index, baseFee)
 	}
 
 	if tx := s.b.GetPoolTransaction(hash); tx != nil {
@@ -1001,7 +1106,7 @@ func (s *PublicTransactionPoolAPI) GetRawTransactionByHash(ctx context.Context,
 		}
 	}
 
-	return rlp.EncodeToBytes(tx)
+	return tx.MarshalBinary()
 }
 
 type Log struct {
@@ -1040,11 +1145,12 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 
 	var signer types.Signer = types.FrontierSigner{}
 	if tx.Protected() {
-		signer = types.NewEIP155Signer(tx.ChainId())
+		signer = types.LatestSignerForChainID(tx.ChainId())
 	}
 	from, _ := types.Sender(signer, tx)
 
 	fields := map[string]interface{}{
+		"type":              hexutil.Uint(tx.Type()),
 		"blockHash":         blockHash,
 		"blockNumber":       hexutil.Uint64(blockNumber),
 		"transactionHash":   hash,
@@ -1058,6 +1164,13 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 		"logsBloom":         receipt.Bloom,
 	}
 
+	// the effective gas price depends on the base fee of the block
+	gasPrice := tx.GasPrice()
+	if header, _ := s.b.HeaderByNumber(ctx, rpc.BlockNumber(blockNumber)); header != nil && header.BaseFee != nil {
+		gasPrice = math.BigMin(new(big.Int).Add(tx.GasTipCap(), header.BaseFee), tx.GasFeeCap())
+	}
+	fields["effectiveGasPrice"] = (*hexutil.Big)(gasPrice)
+
 	if len(receipt.PostState) > 0 {
 		fields["root"] = hexutil.Bytes(receipt.PostState)
 	} else {
@@ -1099,6 +1212,13 @@ type SendTxArgs struct {
 
 	Data  *hexutil.Bytes `json:"data"`
 	Input *hexutil.Bytes `json:"input"`
+
+	// Typed transaction fields, an access list makes an EIP-2930 transaction
+	// and the fee caps make an EIP-1559 transaction
+	AccessList           *types.AccessList `json:"accessList,omitempty"`
+	ChainID              *hexutil.Big      `json:"chainId,omitempty"`
+	MaxFeePerGas         *hexutil.Big      `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big      `json:"maxPriorityFeePerGas,omitempty"`
 }
 
 func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
@@ -1132,7 +1252,36 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 		}
 	}
 
-	if args.GasPrice == nil {
+	if args.MaxFeePerGas != nil || args.MaxPriorityFeePerGas != nil {
+		if args.GasPrice != nil {
+			return errors.New("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
+		}
+		head := b.CurrentBlock().Header()
+		if !b.ChainConfig().IsLondon(head.Number) || head.BaseFee == nil {
+			return types.ErrTxTypeNotSupported
+		}
+		if args.MaxPriorityFeePerGas == nil {
+			tip, err := b.SuggestGasTipCap(ctx)
+			if err != nil {
+				return err
+			}
+			args.MaxPriorityFeePerGas = (*hexutil.Big)(tip)
+		}
+		if args.MaxFeePerGas == nil {
+			// leave room for the base fee to rise in the next blocks
+			gasFeeCap := new(big.Int).Add(
+				(*big.Int)(args.MaxPriorityFeePerGas),
+				new(big.Int).Mul(head.BaseFee, big.NewInt(2)),
+			)
+			args.MaxFeePerGas = (*hexutil.Big)(gasFeeCap)
+		}
+		if args.MaxFeePerGas.ToInt().Cmp(args.MaxPriorityFeePerGas.ToInt()) < 0 {
+			return fmt.Errorf("maxFeePerGas (%v) < maxPriorityFeePerGas (%v)", args.MaxFeePerGas, args.MaxPriorityFeePerGas)
+		}
+		if args.ChainID == nil {
+			args.ChainID = (*hexutil.Big)(b.ChainConfig().ChainId)
+		}
+	} else if args.GasPrice == nil {
 		price, err := b.SuggestPrice(ctx)
 		if err != nil {
 			return err
@@ -1166,6 +1315,15 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 		args.Nonce = (*hexutil.Uint64)(&nonce)
 	}
 
+	if args.AccessList != nil {
+		if !b.ChainConfig().IsBerlin(b.CurrentBlock().Number()) {
+			return types.ErrTxTypeNotSupported
+		}
+		if args.ChainID == nil {
+			args.ChainID = (*hexutil.Big)(b.ChainConfig().ChainId)
+		}
+	}
+
 	return nil
 }
 
@@ -1176,6 +1334,35 @@ func (args *SendTxArgs) toTransaction() *types.Transaction {
 	} else if args.Input != nil {
 		input = *args.Input
 	}
+	if args.MaxFeePerGas != nil {
+		var al types.AccessList
+		if args.AccessList != nil {
+			al = *args.AccessList
+		}
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:    (*big.Int)(args.ChainID),
+			Nonce:      uint64(*args.Nonce),
+			GasTipCap:  (*big.Int)(args.MaxPriorityFeePerGas),
+			GasFeeCap:  (*big.Int)(args.MaxFeePerGas),
+			Gas:        uint64(*args.Gas),
+			To:         args.To,
+			Value:      (*big.Int)(args.Value),
+			Data:       input,
+			AccessList: al,
+		})
+	}
+	if args.AccessList != nil {
+		return types.NewTx(&types.AccessListTx{
+			ChainID:    (*big.Int)(args.ChainID),
+			Nonce:      uint64(*args.Nonce),
+			GasPrice:   (*big.Int)(args.GasPrice),
+			Gas:        uint64(*args.Gas),
+			To:         args.To,
+			Value:      (*big.Int)(args.Value),
+			Data:       input,
+			AccessList: *args.AccessList,
+		})
+	}
 	if args.To == nil {
 		return types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
 	}
@@ -1268,7 +1455,7 @@ func SendTransaction(ctx context.Context, args SendTxArgs, am *accounts.Manager,
 
 func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
 	tx := new(types.Transaction)
-	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+	if err := tx.UnmarshalBinary(encodedTx); err != nil {
 		return common.Hash{}, err
 	}
 	return submitTransaction(ctx, s.b, tx)
@@ -1299,8 +1486,8 @@ func (s *PublicTransactionPoolAPI) SignTransaction(ctx context.Context, args Sen
 	if args.Gas == nil {
 		return nil, fmt.Errorf("gas not specified")
 	}
-	if args.GasPrice == nil {
-		return nil, fmt.Errorf("gasPrice not specified")
+	if args.GasPrice == nil && (args.MaxFeePerGas == nil || args.MaxPriorityFeePerGas == nil) {
+		return nil, fmt.Errorf("missing gasPrice or maxFeePerGas/maxPriorityFeePerGas")
 	}
 	if args.Nonce == nil {
 		return nil, fmt.Errorf("nonce not specified")
@@ -1312,7 +1499,7 @@ func (s *PublicTransactionPoolAPI) SignTransaction(ctx context.Context, args Sen
 	if err != nil {
 		return nil, err
 	}
-	data, err := rlp.EncodeToBytes(tx)
+	data, err := tx.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
@@ -1329,7 +1516,7 @@ func (s *PublicTransactionPoolAPI) PendingTransactions() ([]*RPCTransaction, err
 	for _, tx := range pending {
 		var signer types.Signer = types.HomesteadSigner{}
 		if tx.Protected() {
-			signer = types.NewEIP155Signer(tx.ChainId())
+			signer = types.LatestSignerForChainID(tx.ChainId())
 		}
 		from, _ := types.Sender(signer, tx)
 		if _, err := s.b.AccountManager().Find(accounts.Account{Address: from}); err == nil {
@@ -1355,7 +1542,7 @@ func (s *PublicTransactionPoolAPI) Resend(ctx context.Context, sendArgs SendTxAr
 	for _, p := range pending {
 		var signer types.Signer = types.HomesteadSigner{}
 		if p.Protected() {
-			signer = types.NewEIP155Signer(p.ChainId())
+			signer = types.LatestSignerForChainID(p.ChainId())
 		}
 		wantSigHash := signer.Hash(matchTx)
 
@@ -1555,6 +1742,80 @@ func (api *PublicNEATAPI) UnDelegate(ctx context.Context, from, candidate common
 	return SendTransaction(ctx, args, api.am, api.b, api.nonceLock)
 }
 
+func (api *PublicNEATAPI) Redelegate(ctx context.Context, from, fromCandidate, toCandidate common.Address, amount *hexutil.Big, gasPrice *hexutil.Big) (common.Hash, error) {
+
+	input, err := neatAbi.ChainABI.Pack(neatAbi.Redelegate.String(), fromCandidate, toCandidate, (*big.Int)(amount))
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	defaultGas := neatAbi.Redelegate.RequiredGas()
+
+	args := SendTxArgs{
+		From:     from,
+		To:       &neatAbi.NeatioSmartContractAddress,
+		Gas:      (*hexutil.Uint64)(&defaultGas),
+		GasPrice: gasPrice,
+		Value:    nil,
+		Input:    (*hexutil.Bytes)(&input),
+		Nonce:    nil,
+	}
+
+	return SendTransaction(ctx, args, api.am, api.b, api.nonceLock)
+}
+
+func (api *PublicNEATAPI) GetPendingRedelegations(ctx context.Context, delegator common.Address, blockNr rpc.BlockNumber) ([]map[string]interface{}, error) {
+	state, _, err := api.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+
+	redelegations := make([]map[string]interface{}, 0)
+	for _, r := range state.GetPendingRedelegations() {
+		if r.Delegator != delegator {
+			continue
+		}
+		redelegations = append(redelegations, map[string]interface{}{
+			"from":   r.From,
+			"to":     r.To,
+			"amount": (*hexutil.Big)(r.Amount),
+		})
+	}
+	return redelegations, state.Error()
+}
+
+func (api *PublicNEATAPI) SetAutoCompound(ctx context.Context, from, candidate common.Address, enable bool, gasPrice *hexutil.Big) (common.Hash, error) {
+
+	input, err := neatAbi.ChainABI.Pack(neatAbi.SetAutoCompound.String(), candidate, enable)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	defaultGas := neatAbi.SetAutoCompound.RequiredGas()
+
+	args := SendTxArgs{
+		From:     from,
+		To:       &neatAbi.NeatioSmartContractAddress,
+		Gas:      (*hexutil.Uint64)(&defaultGas),
+		GasPrice: gasPrice,
+		Value:    nil,
+		Input:    (*hexutil.Bytes)(&input),
+		Nonce:    nil,
+	}
+
+	return SendTransaction(ctx, args, api.am, api.b, api.nonceLock)
+}
+
+// GetAutoCompound returns the candidates the delegator compounds the rewards to
+func (api *PublicNEATAPI) GetAutoCompound(ctx context.Context, delegator common.Address, blockNr rpc.BlockNumber) ([]common.Address, error) {
+	state, _, err := api.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+
+	return state.GetAutoCompoundByUser(delegator), state.Error()
+}
+
 func (api *PublicNEATAPI) Register(ctx context.Context, from common.Address, registerAmount *hexutil.Big, pubkey goCrypto.BLSPubKey, signature hexutil.Bytes, commission uint8, gasPrice *hexutil.Big) (common.Hash, error) {
 
 	input, err := neatAbi.ChainABI.Pack(neatAbi.Register.String(), pubkey.Bytes(), signature, commission)
@@ -1597,6 +1858,129 @@ func (api *PublicNEATAPI) UnRegister(ctx context.Context, from common.Address, g
 	return SendTransaction(ctx, args, api.am, api.b, api.nonceLock)
 }
 
+func (api *PublicNEATAPI) UnBanned(ctx context.Context, from common.Address, gasPrice *hexutil.Big) (common.Hash, error) {
+
+	input, err := neatAbi.ChainABI.Pack(neatAbi.UnBanned.String())
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	defaultGas := neatAbi.UnBanned.RequiredGas()
+
+	args := SendTxArgs{
+		From:     from,
+		To:       &neatAbi.NeatioSmartContractAddress,
+		Gas:      (*hexutil.Uint64)(&defaultGas),
+		GasPrice: gasPrice,
+		Value:    nil,
+		Input:    (*hexutil.Bytes)(&input),
+		Nonce:    nil,
+	}
+	return SendTransaction(ctx, args, api.am, api.b, api.nonceLock)
+}
+
+// VoteNextEpoch sends the hash of the vote for the next epoch. The salt is
+// generated here and saved with the vote in the keystore directory, RevealVote
+// or the auto revealer sends it once the reveal stage opens.
+func (api *PublicNEATAPI) VoteNextEpoch(ctx context.Context, from common.Address, pubkey goCrypto.BLSPubKey, amount *hexutil.Big, signature hexutil.Bytes, gasPrice *hexutil.Big) (common.Hash, error) {
+
+	if amount == nil || amount.ToInt().Sign() < 0 {
+		return common.Hash{}, core.ErrVoteAmountTooLow
+	}
+	if err := goCrypto.CheckConsensusPubKey(from, pubkey.Bytes(), signature); err != nil {
+		return common.Hash{}, err
+	}
+
+	ep, err := getEpochFromBackend(api.b)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	next := ep.GetNextEpoch()
+	if next == nil {
+		return common.Hash{}, errors.New("next epoch has not been proposed, please retry later")
+	}
+
+	salt, err := newVoteSalt()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	record := &VoteRecord{
+		Epoch:     hexutil.Uint64(next.Number),
+		From:      from,
+		PubKey:    pubkey.Bytes(),
+		Amount:    amount,
+		Salt:      salt,
+		Signature: signature,
+		VoteHash:  calcVoteHash(from, pubkey.Bytes(), amount.ToInt(), salt),
+	}
+
+	input, err := neatAbi.ChainABI.Pack(neatAbi.VoteNextEpoch.String(), record.VoteHash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	// keep the salt before the hash is sent, a lost salt can not be revealed
+	previous, _ := loadVoteRecord(api.am, from)
+	if err := saveVoteRecord(api.am, record); err != nil {
+		return common.Hash{}, err
+	}
+
+	defaultGas := neatAbi.VoteNextEpoch.RequiredGas()
+
+	args := SendTxArgs{
+		From:     from,
+		To:       &neatAbi.NeatioSmartContractAddress,
+		Gas:      (*hexutil.Uint64)(&defaultGas),
+		GasPrice: gasPrice,
+		Value:    nil,
+		Input:    (*hexutil.Bytes)(&input),
+		Nonce:    nil,
+	}
+	hash, err := SendTransaction(ctx, args, api.am, api.b, api.nonceLock)
+	if err != nil {
+		if previous != nil {
+			saveVoteRecord(api.am, previous)
+		}
+		return common.Hash{}, err
+	}
+
+	record.VoteTxHash = hash
+	return hash, saveVoteRecord(api.am, record)
+}
+
+// RevealVote reveals the vote for the next epoch saved by VoteNextEpoch
+func (api *PublicNEATAPI) RevealVote(ctx context.Context, from common.Address, gasPrice *hexutil.Big) (common.Hash, error) {
+
+	record, err := loadVoteRecord(api.am, from)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("no saved vote of %x: %v", from, err)
+	}
+
+	ep, err := getEpochFromBackend(api.b)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if next := ep.GetNextEpoch(); next == nil || next.Number != uint64(record.Epoch) {
+		return common.Hash{}, fmt.Errorf("saved vote of %x is for epoch %v, not the next epoch", from, uint64(record.Epoch))
+	}
+
+	return sendRevealVote(ctx, api.b, api.nonceLock, record, gasPrice)
+}
+
+func (api *PublicNEATAPI) GetBannedStatus(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (map[string]interface{}, error) {
+	state, _, err := api.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+
+	_, banned := state.GetBannedSet()[address]
+	fields := map[string]interface{}{
+		"banned":       banned,
+		"missedBlocks": hexutil.Uint64(state.GetMissedBlocks(address)),
+	}
+	return fields, state.Error()
+}
+
 func (api *PublicNEATAPI) CheckCandidate(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (map[string]interface{}, error) {
 	state, _, err := api.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
@@ -1607,9 +1991,66 @@ func (api *PublicNEATAPI) CheckCandidate(ctx context.Context, address common.Add
 		"candidate":  state.IsCandidate(address),
 		"commission": state.GetCommission(address),
 	}
+	addCandidateDescription(fields, state.GetCandidateDescription(address))
 	return fields, state.Error()
 }
 
+// GetCandidates returns the registered candidates with their descriptions, the
+// validators of the epoch which are candidates since the genesis included
+func (api *PublicNEATAPI) GetCandidates(ctx context.Context, blockNr rpc.BlockNumber) ([]map[string]interface{}, error) {
+	state, header, err := api.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+
+	seen := make(map[common.Address]bool)
+	addrs := make([]common.Address, 0)
+	for addr := range state.GetCandidateSet() {
+		if state.IsCandidate(addr) {
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	if nc, ok := api.b.Engine().(consensus.NeatCon); ok {
+		if ep := nc.GetEpoch().GetEpochByBlockNumber(header.Number.Uint64()); ep != nil {
+			for _, v := range ep.Validators.Validators {
+				addr := common.BytesToAddress(v.Address)
+				if !seen[addr] && state.IsCandidate(addr) {
+					seen[addr] = true
+					addrs = append(addrs, addr)
+				}
+			}
+		}
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i].Bytes(), addrs[j].Bytes()) < 0
+	})
+
+	candidates := make([]map[string]interface{}, 0, len(addrs))
+	for _, addr := range addrs {
+		fields := map[string]interface{}{
+			"address":               addr,
+			"pubKey":                state.GetPubkey(addr),
+			"commission":            state.GetCommission(addr),
+			"proxiedBalance":        (*hexutil.Big)(state.GetTotalProxiedBalance(addr)),
+			"depositProxiedBalance": (*hexutil.Big)(state.GetTotalDepositProxiedBalance(addr)),
+		}
+		addCandidateDescription(fields, state.GetCandidateDescription(addr))
+		candidates = append(candidates, fields)
+	}
+	return candidates, state.Error()
+}
+
+func addCandidateDescription(fields map[string]interface{}, desc *state.CandidateDescription) {
+	if desc == nil {
+		desc = &state.CandidateDescription{}
+	}
+	fields["moniker"] = desc.Moniker
+	fields["website"] = desc.Website
+	fields["identity"] = desc.Identity
+	fields["details"] = desc.Details
+}
+
 func (api *PublicNEATAPI) SetCommission(ctx context.Context, from common.Address, commission uint8, gasPrice *hexutil.Big) (common.Hash, error) {
 	input, err := neatAbi.ChainABI.Pack(neatAbi.SetCommission.String(), commission)
 	if err != nil {
@@ -1684,19 +2125,42 @@ func init() {
 	core.RegisterValidateCb(neatAbi.UnDelegate, unDelegateValidateCb)
 	core.RegisterApplyCb(neatAbi.UnDelegate, unDelegateApplyCb)
 
+	core.RegisterValidateCb(neatAbi.Redelegate, redelegateValidateCb)
+	core.RegisterForkApplyCb(neatAbi.Redelegate, redelegateApplyCb)
+
+	core.RegisterValidateCb(neatAbi.SetAutoCompound, setAutoCompoundValidateCb)
+	core.RegisterForkApplyCb(neatAbi.SetAutoCompound, setAutoCompoundApplyCb)
+
 	core.RegisterValidateCb(neatAbi.Register, registerValidateCb)
 	core.RegisterApplyCb(neatAbi.Register, registerApplyCb)
+	core.RegisterForkApplyCb(neatAbi.Register, registerForkApplyCb)
 
 	core.RegisterValidateCb(neatAbi.UnRegister, unRegisterValidateCb)
 	core.RegisterApplyCb(neatAbi.UnRegister, unRegisterApplyCb)
+	core.RegisterForkApplyCb(neatAbi.UnRegister, unRegisterForkApplyCb)
 
 	core.RegisterValidateCb(neatAbi.SetCommission, setCommisstionValidateCb)
 	core.RegisterApplyCb(neatAbi.SetCommission, setCommisstionApplyCb)
 
 	core.RegisterValidateCb(neatAbi.EditValidator, editValidatorValidateCb)
+	core.RegisterForkApplyCb(neatAbi.EditValidator, editValidatorApplyCb)
 
 	core.RegisterValidateCb(neatAbi.SetAddress, setAddressValidateCb)
 	core.RegisterApplyCb(neatAbi.SetAddress, setAddressApplyCb)
+
+	core.RegisterValidateCb(neatAbi.ReportDoubleSign, reportDoubleSignValidateCb)
+	core.RegisterForkApplyCb(neatAbi.ReportDoubleSign, reportDoubleSignApplyCb)
+
+	core.RegisterValidateCb(neatAbi.UnBanned, unBannedValidateCb)
+	core.RegisterForkApplyCb(neatAbi.UnBanned, unBannedApplyCb)
+
+	core.RegisterValidateCb(neatAbi.VoteNextEpoch, voteNextEpochValidateCb)
+	core.RegisterForkApplyCb(neatAbi.VoteNextEpoch, voteNextEpochApplyCb)
+
+	core.RegisterValidateCb(neatAbi.RevealVote, revealVoteValidateCb)
+	core.RegisterForkApplyCb(neatAbi.RevealVote, revealVoteApplyCb)
+
+	core.RegisterStakingHandlerCb(newStakingHandler)
 }
 
 func withdrawRewardValidateCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) error {
@@ -1717,12 +2181,16 @@ func withdrawRewardApplyCb(tx *types.Transaction, state *state.StateDB, bc *core
 		return err
 	}
 
-	state.SubRewardBalanceByDelegateAddress(from, args.DelegateAddress, args.Amount)
-	state.AddBalance(from, args.Amount)
+	applyWithdrawReward(state, from, args.DelegateAddress, args.Amount)
 
 	return nil
 }
 
+func applyWithdrawReward(state *state.StateDB, from, delegateAddress common.Address, amount *big.Int) {
+	state.SubRewardBalanceByDelegateAddress(from, delegateAddress, amount)
+	state.AddBalance(from, amount)
+}
+
 func withDrawRewardValidation(from common.Address, tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) (*neatAbi.WithdrawRewardArgs, error) {
 
 	var args neatAbi.WithdrawRewardArgs
@@ -1731,20 +2199,28 @@ func withDrawRewardValidation(from common.Address, tx *types.Transaction, state
 		return nil, err
 	}
 
-	reward := state.GetRewardBalanceByDelegateAddress(from, args.DelegateAddress)
+	if err := validateWithdrawReward(from, args.DelegateAddress, args.Amount, state); err != nil {
+		return nil, err
+	}
+	return &args, nil
+}
+
+func validateWithdrawReward(from, delegateAddress common.Address, amount *big.Int, state *state.StateDB) error {
+
+	reward := state.GetRewardBalanceByDelegateAddress(from, delegateAddress)
 
 	if reward.Sign() < 1 {
-		return nil, fmt.Errorf("have no reward to withdraw")
+		return fmt.Errorf("have no reward to withdraw")
 	}
 
-	if args.Amount.Sign() == -1 {
-		return nil, fmt.Errorf("widthdraw amount can not be negative")
+	if amount.Sign() == -1 {
+		return fmt.Errorf("widthdraw amount can not be negative")
 	}
 
-	if args.Amount.Cmp(reward) == 1 {
-		return nil, fmt.Errorf("reward balance not enough, withdraw amount %v, but balance %v, delegate address %v", args.Amount, reward, args.DelegateAddress)
+	if amount.Cmp(reward) == 1 {
+		return fmt.Errorf("reward balance not enough, withdraw amount %v, but balance %v, delegate address %v", amount, reward, delegateAddress)
 	}
-	return &args, nil
+	return nil
 }
 
 func registerValidateCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) error {
@@ -1790,6 +2266,16 @@ func registerApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.Block
 	return nil
 }
 
+// registerForkApplyCb also adds the candidate to the candidate set from the
+// NeatFork block on
+func registerForkApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain, ops *types.PendingOps) error {
+	if err := registerApplyCb(tx, state, bc, ops); err != nil {
+		return err
+	}
+	state.MarkAddressCandidate(derivedAddressFromTx(tx))
+	return nil
+}
+
 func registerValidation(from common.Address, tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) (*neatAbi.RegisterArgs, error) {
 
 	if !state.IsCleanAddress(from) {
@@ -1854,8 +2340,9 @@ func unRegisterApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.Blo
 			allRefund = false
 
 			refunded := state.GetPendingRefundBalanceByUser(from, key)
+			redelegated := state.GetPendingRedelegateOutByUser(from, key)
 
-			state.AddPendingRefundBalanceByUser(from, key, new(big.Int).Sub(depositProxiedBalance, refunded))
+			state.AddPendingRefundBalanceByUser(from, key, new(big.Int).Sub(new(big.Int).Sub(depositProxiedBalance, refunded), redelegated))
 
 			state.MarkDelegateAddressRefund(from)
 		}
@@ -1867,6 +2354,18 @@ func unRegisterApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.Blo
 	return nil
 }
 
+// unRegisterForkApplyCb also removes the candidate from the candidate set and
+// drops its description from the NeatFork block on
+func unRegisterForkApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain, ops *types.PendingOps) error {
+	if err := unRegisterApplyCb(tx, state, bc, ops); err != nil {
+		return err
+	}
+	from := derivedAddressFromTx(tx)
+	state.ClearCandidateSetByAddress(from)
+	state.SetCandidateDescription(from, nil)
+	return nil
+}
+
 func unRegisterValidation(from common.Address, tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) error {
 
 	if !state.IsCandidate(from) {
@@ -1910,12 +2409,7 @@ func delegateApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.Block
 		return verror
 	}
 
-	amount := tx.Value()
-
-	state.SubBalance(from, amount)
-	state.AddDelegateBalance(from, amount)
-
-	state.AddProxiedBalanceByUser(args.Candidate, from, amount)
+	applyDelegate(state, from, args.Candidate, tx.Value())
 
 	verror = updateNextEpochValidatorVoteSet(tx, state, bc, args.Candidate, ops)
 	if verror != nil {
@@ -1937,16 +2431,25 @@ func delegateValidation(from common.Address, tx *types.Transaction, state *state
 		return nil, err
 	}
 
-	if !state.IsCandidate(args.Candidate) {
-		return nil, core.ErrNotCandidate
+	if err := validateDelegate(from, args.Candidate, state, bc); err != nil {
+		return nil, err
 	}
 
-	depositBalance := state.GetDepositProxiedBalanceByUser(args.Candidate, from)
+	return &args, nil
+}
+
+func validateDelegate(from, candidate common.Address, state *state.StateDB, bc *core.BlockChain) error {
+
+	if !state.IsCandidate(candidate) {
+		return core.ErrNotCandidate
+	}
+
+	depositBalance := state.GetDepositProxiedBalanceByUser(candidate, from)
 	if depositBalance.Sign() == 0 {
 
-		delegatedAddressNumber := state.GetProxiedAddressNumber(args.Candidate)
+		delegatedAddressNumber := state.GetProxiedAddressNumber(candidate)
 		if delegatedAddressNumber >= maxDelegationAddresses {
-			return nil, core.ErrExceedDelegationAddressLimit
+			return core.ErrExceedDelegationAddressLimit
 		}
 	}
 
@@ -1954,13 +2457,20 @@ func delegateValidation(from common.Address, tx *types.Transaction, state *state
 	if nc, ok := bc.Engine().(consensus.NeatCon); ok {
 		ep = nc.GetEpoch().GetEpochByBlockNumber(bc.CurrentBlock().NumberU64())
 	}
-	if _, supernode := ep.Validators.GetByAddress(args.Candidate.Bytes()); supernode != nil && supernode.RemainingEpoch > 0 {
+	if _, supernode := ep.Validators.GetByAddress(candidate.Bytes()); supernode != nil && supernode.RemainingEpoch > 0 {
 		if depositBalance.Sign() == 0 {
-			return nil, core.ErrCannotDelegate
+			return core.ErrCannotDelegate
 		}
 	}
 
-	return &args, nil
+	return nil
+}
+
+func applyDelegate(state *state.StateDB, from, candidate common.Address, amount *big.Int) {
+	state.SubBalance(from, amount)
+	state.AddDelegateBalance(from, amount)
+
+	state.AddProxiedBalanceByUser(candidate, from, amount)
 }
 
 func unDelegateValidateCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) error {
@@ -1985,28 +2495,34 @@ func unDelegateApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.Blo
 		return verror
 	}
 
-	proxiedBalance := state.GetProxiedBalanceByUser(args.Candidate, from)
+	applyUnDelegate(state, from, args.Candidate, args.Amount)
+
+	verror = updateNextEpochValidatorVoteSet(tx, state, bc, args.Candidate, ops)
+	if verror != nil {
+		return verror
+	}
+
+	return nil
+}
+
+// applyUnDelegate refunds the proxied balance immediately, the rest of the
+// amount is refunded from the deposit at the end of the epoch
+func applyUnDelegate(state *state.StateDB, from, candidate common.Address, amount *big.Int) {
+	proxiedBalance := state.GetProxiedBalanceByUser(candidate, from)
 	var immediatelyRefund *big.Int
-	if args.Amount.Cmp(proxiedBalance) <= 0 {
-		immediatelyRefund = args.Amount
+	if amount.Cmp(proxiedBalance) <= 0 {
+		immediatelyRefund = amount
 	} else {
 		immediatelyRefund = proxiedBalance
-		restRefund := new(big.Int).Sub(args.Amount, proxiedBalance)
-		state.AddPendingRefundBalanceByUser(args.Candidate, from, restRefund)
+		restRefund := new(big.Int).Sub(amount, proxiedBalance)
+		state.AddPendingRefundBalanceByUser(candidate, from, restRefund)
 
-		state.MarkDelegateAddressRefund(args.Candidate)
+		state.MarkDelegateAddressRefund(candidate)
 	}
 
-	state.SubProxiedBalanceByUser(args.Candidate, from, immediatelyRefund)
+	state.SubProxiedBalanceByUser(candidate, from, immediatelyRefund)
 	state.SubDelegateBalance(from, immediatelyRefund)
 	state.AddBalance(from, immediatelyRefund)
-
-	verror = updateNextEpochValidatorVoteSet(tx, state, bc, args.Candidate, ops)
-	if verror != nil {
-		return verror
-	}
-
-	return nil
 }
 
 func unDelegateValidation(from common.Address, tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) (*neatAbi.UnDelegateArgs, error) {
@@ -2017,37 +2533,212 @@ func unDelegateValidation(from common.Address, tx *types.Transaction, state *sta
 		return nil, err
 	}
 
-	if args.Amount.Sign() == -1 {
-		return nil, fmt.Errorf("undelegate amount can not be negative")
+	if err := validateUnDelegate(from, args.Candidate, args.Amount, state, bc); err != nil {
+		return nil, err
 	}
 
-	if from == args.Candidate {
-		return nil, core.ErrCancelSelfDelegate
+	return &args, nil
+}
+
+func validateUnDelegate(from, candidate common.Address, amount *big.Int, state *state.StateDB, bc *core.BlockChain) error {
+
+	if amount.Sign() == -1 {
+		return fmt.Errorf("undelegate amount can not be negative")
+	}
+
+	if from == candidate {
+		return core.ErrCancelSelfDelegate
 	}
 
 	var ep *epoch.Epoch
 	if nc, ok := bc.Engine().(consensus.NeatCon); ok {
 		ep = nc.GetEpoch().GetEpochByBlockNumber(bc.CurrentBlock().NumberU64())
 	}
-	if _, supernode := ep.Validators.GetByAddress(args.Candidate.Bytes()); supernode != nil && supernode.RemainingEpoch > 0 {
-		return nil, core.ErrCannotUnBond
+	if _, supernode := ep.Validators.GetByAddress(candidate.Bytes()); supernode != nil && supernode.RemainingEpoch > 0 {
+		return core.ErrCannotUnBond
 	}
 
-	proxiedBalance := state.GetProxiedBalanceByUser(args.Candidate, from)
-	depositProxiedBalance := state.GetDepositProxiedBalanceByUser(args.Candidate, from)
-	pendingRefundBalance := state.GetPendingRefundBalanceByUser(args.Candidate, from)
+	proxiedBalance := state.GetProxiedBalanceByUser(candidate, from)
+	depositProxiedBalance := state.GetDepositProxiedBalanceByUser(candidate, from)
+	pendingRefundBalance := state.GetPendingRefundBalanceByUser(candidate, from)
 
 	netDeposit := new(big.Int).Sub(depositProxiedBalance, pendingRefundBalance)
+	netDeposit.Sub(netDeposit, state.GetPendingRedelegateOutByUser(candidate, from))
 
 	availableRefundBalance := new(big.Int).Add(proxiedBalance, netDeposit)
-	if args.Amount.Cmp(availableRefundBalance) == 1 {
-		return nil, core.ErrInsufficientProxiedBalance
+	if amount.Cmp(availableRefundBalance) == 1 {
+		return core.ErrInsufficientProxiedBalance
 	}
 
 	if _, err := getEpoch(bc); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func redelegateValidateCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) error {
+	from := derivedAddressFromTx(tx)
+	_, verror := redelegateValidation(from, tx, state, bc)
+	if verror != nil {
+		return verror
+	}
+	return nil
+}
+
+func redelegateApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain, ops *types.PendingOps) error {
+
+	from := derivedAddressFromTx(tx)
+	args, verror := redelegateValidation(from, tx, state, bc)
+	if verror != nil {
+		return verror
+	}
+
+	verror = updateValidation(bc)
+	if verror != nil {
+		return verror
+	}
+
+	// the delegation not bonded yet moves at once, the bonded one stays
+	// bonded and moves when entering the next epoch
+	proxiedBalance := state.GetProxiedBalanceByUser(args.From, from)
+	immediately := args.Amount
+	if immediately.Cmp(proxiedBalance) == 1 {
+		immediately = proxiedBalance
+		state.AddPendingRedelegation(from, args.From, args.To, new(big.Int).Sub(args.Amount, proxiedBalance))
+	}
+	if immediately.Sign() == 1 {
+		state.SubProxiedBalanceByUser(args.From, from, immediately)
+		state.AddProxiedBalanceByUser(args.To, from, immediately)
+	}
+
+	verror = updateNextEpochValidatorVoteSet(tx, state, bc, args.From, ops)
+	if verror != nil {
+		return verror
+	}
+	verror = updateNextEpochValidatorVoteSet(tx, state, bc, args.To, ops)
+	if verror != nil {
+		return verror
+	}
+
+	return nil
+}
+
+func redelegateValidation(from common.Address, tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) (*neatAbi.RedelegateArgs, error) {
+
+	var args neatAbi.RedelegateArgs
+	data := tx.Data()
+	if err := neatAbi.ChainABI.UnpackMethodInputs(&args, neatAbi.Redelegate.String(), data[4:]); err != nil {
+		return nil, err
+	}
+
+	if args.Amount.Sign() != 1 {
+		return nil, fmt.Errorf("redelegate amount must be positive")
+	}
+
+	if args.From == args.To {
+		return nil, core.ErrRedelegateSameCandidate
+	}
+
+	if from == args.From {
+		return nil, core.ErrCancelSelfDelegate
+	}
+
+	if !state.IsCandidate(args.To) {
+		return nil, core.ErrNotCandidate
+	}
+
+	ep, err := getEpoch(bc)
+	if err != nil {
+		return nil, err
+	}
+	if _, supernode := ep.Validators.GetByAddress(args.From.Bytes()); supernode != nil && supernode.RemainingEpoch > 0 {
+		return nil, core.ErrCannotUnBond
+	}
+
+	// no hopping, the delegation redelegated to the candidate in this epoch
+	// has to stay until the next epoch
+	if state.GetPendingRedelegateInByUser(args.From, from).Sign() > 0 {
+		return nil, core.ErrRedelegateHop
+	}
+	if state.GetPendingRedelegateOutByUser(args.From, from).Sign() == 0 && state.GetPendingRedelegationsByUser(from) >= maxPendingRedelegations {
+		return nil, core.ErrExceedRedelegationLimit
+	}
+
+	proxiedBalance := state.GetProxiedBalanceByUser(args.From, from)
+	depositProxiedBalance := state.GetDepositProxiedBalanceByUser(args.From, from)
+	pendingRefundBalance := state.GetPendingRefundBalanceByUser(args.From, from)
+	pendingRedelegateOut := state.GetPendingRedelegateOutByUser(args.From, from)
+
+	available := new(big.Int).Add(proxiedBalance, depositProxiedBalance)
+	available.Sub(available, pendingRefundBalance)
+	available.Sub(available, pendingRedelegateOut)
+	if args.Amount.Cmp(available) == 1 {
+		return nil, core.ErrInsufficientProxiedBalance
+	}
+
+	toDeposit := state.GetDepositProxiedBalanceByUser(args.To, from)
+	if toDeposit.Sign() == 0 && state.GetProxiedBalanceByUser(args.To, from).Sign() == 0 {
+		if state.GetProxiedAddressNumber(args.To) >= maxDelegationAddresses {
+			return nil, core.ErrExceedDelegationAddressLimit
+		}
+	}
+	if _, supernode := ep.Validators.GetByAddress(args.To.Bytes()); supernode != nil && supernode.RemainingEpoch > 0 {
+		if toDeposit.Sign() == 0 {
+			return nil, core.ErrCannotDelegate
+		}
+	}
+
+	return &args, nil
+}
+
+func setAutoCompoundValidateCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) error {
+	from := derivedAddressFromTx(tx)
+	_, err := setAutoCompoundValidation(from, tx, state, bc)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func setAutoCompoundApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain, ops *types.PendingOps) error {
+	from := derivedAddressFromTx(tx)
+	args, err := setAutoCompoundValidation(from, tx, state, bc)
+	if err != nil {
+		return err
+	}
+
+	state.SetAutoCompound(from, args.Candidate, args.Enable)
+
+	return nil
+}
+
+func setAutoCompoundValidation(from common.Address, tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) (*neatAbi.SetAutoCompoundArgs, error) {
+
+	var args neatAbi.SetAutoCompoundArgs
+	data := tx.Data()
+	if err := neatAbi.ChainABI.UnpackMethodInputs(&args, neatAbi.SetAutoCompound.String(), data[4:]); err != nil {
 		return nil, err
 	}
 
+	// the flag can always be turned off
+	if !args.Enable {
+		return &args, nil
+	}
+
+	if from == args.Candidate {
+		return nil, core.ErrAutoCompoundSelf
+	}
+
+	if !state.IsCandidate(args.Candidate) {
+		return nil, core.ErrNotCandidate
+	}
+
+	if state.GetProxiedBalanceByUser(args.Candidate, from).Sign() == 0 && state.GetDepositProxiedBalanceByUser(args.Candidate, from).Sign() == 0 {
+		return nil, core.ErrNoDelegation
+	}
+
 	return &args, nil
 }
 
@@ -2123,16 +2814,289 @@ func setAddressValidation(from common.Address, tx *types.Transaction, state *sta
 	return &args, nil
 }
 
+func reportDoubleSignValidateCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) error {
+	ev, err := reportDoubleSignValidation(tx, state, bc)
+	if err != nil {
+		return err
+	}
+
+	if state.IsTombstoned(ev.Address()) {
+		return core.ErrAlreadyBanned
+	}
+
+	return nil
+}
+
+// The offender is slashed in Finalize, so a report for an already tombstoned
+// validator is still applied and simply ignored there.
+func reportDoubleSignApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain, ops *types.PendingOps) error {
+	_, err := reportDoubleSignValidation(tx, state, bc)
+	return err
+}
+
+func reportDoubleSignValidation(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) (*ntcTypes.DuplicateVoteEvidence, error) {
+	var args neatAbi.ReportDoubleSignArgs
+	data := tx.Data()
+	if err := neatAbi.ChainABI.UnpackMethodInputs(&args, neatAbi.ReportDoubleSign.String(), data[4:]); err != nil {
+		return nil, err
+	}
+
+	ev, err := ntcTypes.DecodeDuplicateVoteEvidence(args.Evidence)
+	if err != nil {
+		return nil, err
+	}
+	if err := ev.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	ep, err := getEpoch(bc)
+	if err != nil {
+		return nil, err
+	}
+
+	if ev.Height() > bc.CurrentBlock().NumberU64()+1 {
+		return nil, errors.New("double sign evidence from the future")
+	}
+
+	evEpoch := ep.GetEpochByBlockNumber(ev.Height())
+	if evEpoch == nil || ep.Number-evEpoch.Number > 1 {
+		return nil, core.ErrEvidenceTooOld
+	}
+
+	if err := ev.Verify(bc.Config().NeatChainId, evEpoch.Validators); err != nil {
+		return nil, err
+	}
+
+	return ev, nil
+}
+
+func unBannedValidateCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) error {
+	from := derivedAddressFromTx(tx)
+	verror := unBannedValidation(from, tx, state, bc)
+	if verror != nil {
+		return verror
+	}
+	return nil
+}
+
+func unBannedApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain, ops *types.PendingOps) error {
+	from := derivedAddressFromTx(tx)
+	verror := unBannedValidation(from, tx, state, bc)
+	if verror != nil {
+		return verror
+	}
+
+	state.ClearBannedSetByAddress(from)
+
+	return nil
+}
+
+func unBannedValidation(from common.Address, tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) error {
+	if _, banned := state.GetBannedSet()[from]; !banned {
+		return core.ErrNotBanned
+	}
+
+	if state.IsTombstoned(from) {
+		return core.ErrTombstoned
+	}
+
+	// the downtime ban lasts at least DowntimeJailBlocks
+	if bc.CurrentBlock().NumberU64() < state.GetJailHeight(from)+params.DowntimeJailBlocks {
+		return core.ErrJailPeriod
+	}
+
+	ep, err := getEpoch(bc)
+	if err != nil {
+		return err
+	}
+
+	// the banned validator is removed when entering the next epoch
+	if ep.Validators.HasAddress(from.Bytes()) {
+		return core.ErrBannedStillValidator
+	}
+
+	return nil
+}
+
+func voteNextEpochValidateCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) error {
+	_, verror := voteNextEpochValidation(tx, bc)
+	if verror != nil {
+		return verror
+	}
+	return nil
+}
+
+func voteNextEpochApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain, ops *types.PendingOps) error {
+	args, verror := voteNextEpochValidation(tx, bc)
+	if verror != nil {
+		return verror
+	}
+
+	op := types.VoteNextEpochOp{
+		From:     derivedAddressFromTx(tx),
+		VoteHash: args.VoteHash,
+		TxHash:   tx.Hash(),
+	}
+	if ok := ops.Append(&op); !ok {
+		return fmt.Errorf("pending ops conflict: %v", op)
+	}
+	return nil
+}
+
+func voteNextEpochValidation(tx *types.Transaction, bc *core.BlockChain) (*neatAbi.VoteNextEpochArgs, error) {
+	var args neatAbi.VoteNextEpochArgs
+	data := tx.Data()
+	if err := neatAbi.ChainABI.UnpackMethodInputs(&args, neatAbi.VoteNextEpoch.String(), data[4:]); err != nil {
+		return nil, err
+	}
+
+	ep, err := getEpoch(bc)
+	if err != nil {
+		return nil, err
+	}
+	if ep.GetNextEpoch() == nil {
+		return nil, errors.New("next epoch has not been proposed, you can not vote for it")
+	}
+	if !ep.CheckInHashVoteStage(bc.CurrentBlock().NumberU64()) {
+		return nil, core.ErrNotInHashVoteStage
+	}
+
+	return &args, nil
+}
+
+func revealVoteValidateCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) error {
+	from := derivedAddressFromTx(tx)
+	_, verror := revealVoteValidation(from, tx, state, bc)
+	if verror != nil {
+		return verror
+	}
+	return nil
+}
+
+func revealVoteApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain, ops *types.PendingOps) error {
+	from := derivedAddressFromTx(tx)
+	args, verror := revealVoteValidation(from, tx, state, bc)
+	if verror != nil {
+		return verror
+	}
+
+	// lock the part of the amount not covered by the delegations
+	deposit := new(big.Int).Sub(args.Amount, netProxiedBalance(state, from))
+	if currentDeposit := state.GetDepositBalance(from); deposit.Cmp(currentDeposit) == 1 {
+		difference := new(big.Int).Sub(deposit, currentDeposit)
+		state.SubBalance(from, difference)
+		state.AddDepositBalance(from, difference)
+	}
+
+	var blsPK goCrypto.BLSPubKey
+	copy(blsPK[:], args.PubKey)
+
+	op := types.RevealVoteOp{
+		From:   from,
+		Pubkey: blsPK,
+		Amount: args.Amount,
+		Salt:   args.Salt,
+		TxHash: tx.Hash(),
+	}
+	if ok := ops.Append(&op); !ok {
+		return fmt.Errorf("pending ops conflict: %v", op)
+	}
+	return nil
+}
+
+func revealVoteValidation(from common.Address, tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) (*neatAbi.RevealVoteArgs, error) {
+	var args neatAbi.RevealVoteArgs
+	data := tx.Data()
+	if err := neatAbi.ChainABI.UnpackMethodInputs(&args, neatAbi.RevealVote.String(), data[4:]); err != nil {
+		return nil, err
+	}
+
+	ep, err := getEpoch(bc)
+	if err != nil {
+		return nil, err
+	}
+	if ep.GetNextEpoch() == nil {
+		return nil, errors.New("next epoch has not been proposed, you can not reveal the vote")
+	}
+	if !ep.CheckInRevealVoteStage(bc.CurrentBlock().NumberU64()) {
+		return nil, core.ErrNotInRevealVoteStage
+	}
+
+	voteSet := ep.GetNextEpoch().GetEpochValidatorVoteSet()
+	if voteSet == nil {
+		return nil, core.ErrVoteNotFound
+	}
+	vote, exist := voteSet.GetVoteByAddress(from)
+	if !exist {
+		return nil, core.ErrVoteNotFound
+	}
+	if args.Amount == nil {
+		return nil, core.ErrVoteAmountTooLow
+	}
+	if vote.VoteHash != calcVoteHash(from, args.PubKey, args.Amount, args.Salt) {
+		return nil, core.ErrVoteHashMismatch
+	}
+
+	if err := goCrypto.CheckConsensusPubKey(from, args.PubKey, args.Signature); err != nil {
+		return nil, err
+	}
+
+	// the delegations count in the voting power, the rest comes from the balance
+	netProxied := netProxiedBalance(state, from)
+	if args.Amount.Sign() > 0 && args.Amount.Cmp(netProxied) == -1 {
+		return nil, core.ErrVoteAmountTooLow
+	}
+	selfAmount := new(big.Int).Sub(args.Amount, netProxied)
+	if selfAmount.Cmp(new(big.Int).Add(state.GetBalance(from), state.GetDepositBalance(from))) == 1 {
+		return nil, core.ErrVoteAmountTooHight
+	}
+
+	return &args, nil
+}
+
+func netProxiedBalance(state *state.StateDB, address common.Address) *big.Int {
+	proxied := new(big.Int).Add(state.GetTotalProxiedBalance(address), state.GetTotalDepositProxiedBalance(address))
+	proxied.Sub(proxied, state.GetTotalPendingRefundBalance(address))
+	proxied.Sub(proxied, state.GetTotalPendingRedelegateOut(address))
+	return proxied.Add(proxied, state.GetTotalPendingRedelegateIn(address))
+}
+
 func editValidatorValidateCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) error {
 	from := derivedAddressFromTx(tx)
+	_, err := editValidatorValidation(from, tx, state)
+	return err
+}
+
+func editValidatorApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain, ops *types.PendingOps) error {
+	from := derivedAddressFromTx(tx)
+	args, err := editValidatorValidation(from, tx, state)
+	if err != nil {
+		return err
+	}
+
+	state.SetCandidateDescription(from, newCandidateDescription(args))
+
+	return nil
+}
+
+func newCandidateDescription(args *neatAbi.EditValidatorArgs) *state.CandidateDescription {
+	return &state.CandidateDescription{
+		Moniker:  args.Moniker,
+		Website:  args.Website,
+		Identity: args.Identity,
+		Details:  args.Details,
+	}
+}
+
+func editValidatorValidation(from common.Address, tx *types.Transaction, state *state.StateDB) (*neatAbi.EditValidatorArgs, error) {
 	if !state.IsCandidate(from) {
-		return errors.New("you are not a validator or candidate")
+		return nil, errors.New("you are not a validator or candidate")
 	}
 
 	var args neatAbi.EditValidatorArgs
 	data := tx.Data()
 	if err := neatAbi.ChainABI.UnpackMethodInputs(&args, neatAbi.EditValidator.String(), data[4:]); err != nil {
-		return err
+		return nil, err
 	}
 
 	if len([]byte(args.Details)) > maxEditValidatorLength ||
@@ -2140,10 +3104,10 @@ func editValidatorValidateCb(tx *types.Transaction, state *state.StateDB, bc *co
 		len([]byte(args.Moniker)) > maxEditValidatorLength ||
 		len([]byte(args.Website)) > maxEditValidatorLength {
 
-		return fmt.Errorf("args length too long, more than %v", maxEditValidatorLength)
+		return nil, fmt.Errorf("args length too long, more than %v", maxEditValidatorLength)
 	}
 
-	return nil
+	return &args, nil
 }
 
 func concatCopyPreAllocate(slices [][]byte) []byte {
@@ -2173,7 +3137,7 @@ func getEpoch(bc *core.BlockChain) (*epoch.Epoch, error) {
 }
 
 func derivedAddressFromTx(tx *types.Transaction) (from common.Address) {
-	signer := types.NewEIP155Signer(tx.ChainId())
+	signer := types.LatestSignerForChainID(tx.ChainId())
 	from, _ = types.Sender(signer, tx)
 	return
 }
@@ -2200,10 +3164,7 @@ func updateNextEpochValidatorVoteSet(tx *types.Transaction, state *state.StateDB
 		return err
 	}
 
-	proxiedBalance := state.GetTotalProxiedBalance(candidate)
-	depositProxiedBalance := state.GetTotalDepositProxiedBalance(candidate)
-	pendingRefundBalance := state.GetTotalPendingRefundBalance(candidate)
-	netProxied := new(big.Int).Sub(new(big.Int).Add(proxiedBalance, depositProxiedBalance), pendingRefundBalance)
+	netProxied := netProxiedBalance(state, candidate)
 
 	if netProxied.Sign() == -1 {
 		return errors.New("validator voting power can not be negative")
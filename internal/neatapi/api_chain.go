@@ -0,0 +1,1129 @@
+package neatapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	goCrypto "github.com/neatio-net/crypto-go"
+	dbm "github.com/neatio-net/db-go"
+	"github.com/neatio-net/neatio/chain/accounts"
+	"github.com/neatio-net/neatio/chain/consensus/neatcon/epoch"
+	ntcTypes "github.com/neatio-net/neatio/chain/consensus/neatcon/types"
+	"github.com/neatio-net/neatio/chain/core"
+	"github.com/neatio-net/neatio/chain/core/rawdb"
+	"github.com/neatio-net/neatio/chain/core/state"
+	"github.com/neatio-net/neatio/chain/core/types"
+	neatAbi "github.com/neatio-net/neatio/neatabi/abi"
+	"github.com/neatio-net/neatio/network/rpc"
+	"github.com/neatio-net/neatio/params"
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/common/hexutil"
+	"github.com/neatio-net/neatio/utilities/common/math"
+	"github.com/neatio-net/neatio/utilities/rlp"
+)
+
+// PublicChainAPI sends the transactions creating, joining and decommissioning the side chains
+type PublicChainAPI struct {
+	am        *accounts.Manager
+	b         Backend
+	nonceLock *AddrLocker
+}
+
+func NewPublicChainAPI(b Backend, nonceLock *AddrLocker) *PublicChainAPI {
+	return &PublicChainAPI{b.AccountManager(), b, nonceLock}
+}
+
+// CreateSideChain applies for a new side chain, the official minimum deposit
+// is paid as the startup cost and refunded if the chain fails to launch
+func (s *PublicChainAPI) CreateSideChain(ctx context.Context, from common.Address, chainId string, minValidators hexutil.Uint, minDepositAmount *hexutil.Big, startBlock, endBlock *hexutil.Big, gasPrice *hexutil.Big) (common.Hash, error) {
+
+	if minDepositAmount == nil || startBlock == nil || endBlock == nil {
+		return common.Hash{}, fmt.Errorf("min deposit amount, start block and end block are required")
+	}
+
+	input, err := neatAbi.ChainABI.Pack(neatAbi.CreateSideChain.String(), chainId, uint16(minValidators), (*big.Int)(minDepositAmount), (*big.Int)(startBlock), (*big.Int)(endBlock))
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	defaultGas := neatAbi.CreateSideChain.RequiredGas()
+
+	args := SendTxArgs{
+		From:     from,
+		To:       &neatAbi.NeatioSmartContractAddress,
+		Gas:      (*hexutil.Uint64)(&defaultGas),
+		GasPrice: gasPrice,
+		Value:    (*hexutil.Big)(math.MustParseBig256(core.OFFICIAL_MINIMUM_DEPOSIT)),
+		Input:    (*hexutil.Bytes)(&input),
+		Nonce:    nil,
+	}
+
+	return SendTransaction(ctx, args, s.am, s.b, s.nonceLock)
+}
+
+// JoinSideChain joins the pending side chain as a validator with the deposit
+func (s *PublicChainAPI) JoinSideChain(ctx context.Context, from common.Address, pubkey goCrypto.BLSPubKey, chainId string, depositAmount *hexutil.Big, signature hexutil.Bytes, gasPrice *hexutil.Big) (common.Hash, error) {
+
+	if chainId == "" || s.b.ChainConfig().NeatChainId == chainId {
+		return common.Hash{}, fmt.Errorf("invalid side chain id %v", chainId)
+	}
+
+	if err := goCrypto.CheckConsensusPubKey(from, pubkey.Bytes(), signature); err != nil {
+		return common.Hash{}, err
+	}
+
+	input, err := neatAbi.ChainABI.Pack(neatAbi.JoinSideChain.String(), pubkey.Bytes(), chainId, signature)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	defaultGas := neatAbi.JoinSideChain.RequiredGas()
+
+	args := SendTxArgs{
+		From:     from,
+		To:       &neatAbi.NeatioSmartContractAddress,
+		Gas:      (*hexutil.Uint64)(&defaultGas),
+		GasPrice: gasPrice,
+		Value:    depositAmount,
+		Input:    (*hexutil.Bytes)(&input),
+		Nonce:    nil,
+	}
+
+	return SendTransaction(ctx, args, s.am, s.b, s.nonceLock)
+}
+
+// DecommissionSideChain stops the side chain before its end block, the proof
+// data of the last side chain block is recorded as its final state if given
+func (s *PublicChainAPI) DecommissionSideChain(ctx context.Context, from common.Address, chainId string, proofData hexutil.Bytes, gasPrice *hexutil.Big) (common.Hash, error) {
+
+	if chainId == "" || s.b.ChainConfig().NeatChainId == chainId {
+		return common.Hash{}, fmt.Errorf("invalid side chain id %v", chainId)
+	}
+
+	input, err := neatAbi.ChainABI.Pack(neatAbi.DecommissionSideChain.String(), chainId, []byte(proofData))
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	defaultGas := neatAbi.DecommissionSideChain.RequiredGas()
+
+	args := SendTxArgs{
+		From:     from,
+		To:       &neatAbi.NeatioSmartContractAddress,
+		Gas:      (*hexutil.Uint64)(&defaultGas),
+		GasPrice: gasPrice,
+		Value:    nil,
+		Input:    (*hexutil.Bytes)(&input),
+		Nonce:    nil,
+	}
+
+	return SendTransaction(ctx, args, s.am, s.b, s.nonceLock)
+}
+
+const (
+	sideChainPending        = "pending"
+	sideChainRunning        = "running"
+	sideChainDecommissioned = "decommissioned"
+)
+
+// SideChainInfo is the entry of the side chain in the chain info db, the last
+// anchored height is the latest side chain block verified on the main chain
+type SideChainInfo struct {
+	ChainId            string                `json:"chainId"`
+	Status             string                `json:"status"`
+	Owner              common.Address        `json:"owner"`
+	MinValidators      hexutil.Uint          `json:"minValidators"`
+	MinDepositAmount   *hexutil.Big          `json:"minDepositAmount"`
+	StartBlock         *hexutil.Big          `json:"startBlock"`
+	EndBlock           *hexutil.Big          `json:"endBlock"`
+	TotalDeposit       *hexutil.Big          `json:"totalDeposit"`
+	Validators         []*SideChainValidator `json:"validators"`
+	Epoch              *ntcTypes.EpochApi    `json:"epoch,omitempty"`
+	LastAnchoredHeight *hexutil.Uint64       `json:"lastAnchoredHeight,omitempty"`
+}
+
+type SideChainValidator struct {
+	Address       common.Address `json:"address"`
+	PubKey        string         `json:"pubKey"`
+	DepositAmount *hexutil.Big   `json:"depositAmount"`
+}
+
+// ListSideChains returns the running side chains
+func (s *PublicChainAPI) ListSideChains(ctx context.Context) ([]*SideChainInfo, error) {
+	db, err := chainInfoDB(s.b.GetCrossChainHelper())
+	if err != nil {
+		return nil, err
+	}
+
+	ids := core.GetSideChainIds(db)
+	chains := make([]*SideChainInfo, 0, len(ids))
+	for _, id := range ids {
+		if ci := core.GetChainInfo(db, id); ci != nil {
+			chains = append(chains, newSideChainInfo(db, &ci.CoreChainInfo, ci.Epoch, sideChainRunning))
+		}
+	}
+	return chains, nil
+}
+
+// GetPendingSideChains returns the side chains waiting for their validators to launch
+func (s *PublicChainAPI) GetPendingSideChains(ctx context.Context) ([]*SideChainInfo, error) {
+	db, err := chainInfoDB(s.b.GetCrossChainHelper())
+	if err != nil {
+		return nil, err
+	}
+
+	ids := core.GetPendingSideChainIds(db)
+	chains := make([]*SideChainInfo, 0, len(ids))
+	for _, id := range ids {
+		if cci := core.GetPendingSideChainData(db, id); cci != nil {
+			chains = append(chains, newSideChainInfo(db, cci, nil, sideChainPending))
+		}
+	}
+	return chains, nil
+}
+
+// GetSideChain returns the side chain, pending, running or decommissioned
+func (s *PublicChainAPI) GetSideChain(ctx context.Context, chainId string) (*SideChainInfo, error) {
+	cch := s.b.GetCrossChainHelper()
+	db, err := chainInfoDB(cch)
+	if err != nil {
+		return nil, err
+	}
+
+	if chainId == "" || chainId == cch.GetMainChainId() {
+		return nil, fmt.Errorf("invalid side chain id %v", chainId)
+	}
+
+	if ci := core.GetChainInfo(db, chainId); ci != nil {
+		status := sideChainRunning
+		if core.GetDecommissionedChain(db, chainId) != nil {
+			status = sideChainDecommissioned
+		}
+		return newSideChainInfo(db, &ci.CoreChainInfo, ci.Epoch, status), nil
+	}
+	if cci := core.GetPendingSideChainData(db, chainId); cci != nil {
+		return newSideChainInfo(db, cci, nil, sideChainPending), nil
+	}
+	return nil, fmt.Errorf("side chain %v not found", chainId)
+}
+
+// chainInfoDB returns the side chain registry, a side chain running in its own
+// process does not hold it
+func chainInfoDB(cch core.CrossChainHelper) (dbm.DB, error) {
+	if db := cch.GetChainInfoDB(); db != nil {
+		return db, nil
+	}
+	return nil, errors.New("the side chain registry is served by the main chain node")
+}
+
+func newSideChainInfo(db dbm.DB, cci *core.CoreChainInfo, ep *epoch.Epoch, status string) *SideChainInfo {
+	info := &SideChainInfo{
+		ChainId:          cci.ChainId,
+		Status:           status,
+		Owner:            cci.Owner,
+		MinValidators:    hexutil.Uint(cci.MinValidators),
+		MinDepositAmount: (*hexutil.Big)(cci.MinDepositAmount),
+		StartBlock:       (*hexutil.Big)(cci.StartBlock),
+		EndBlock:         (*hexutil.Big)(cci.EndBlock),
+		TotalDeposit:     (*hexutil.Big)(cci.TotalDeposit()),
+		Validators:       make([]*SideChainValidator, 0, len(cci.JoinedValidators)),
+	}
+
+	for _, jv := range cci.JoinedValidators {
+		var pubKey string
+		if jv.PubKey != nil {
+			pubKey = jv.PubKey.KeyString()
+		}
+		info.Validators = append(info.Validators, &SideChainValidator{
+			Address:       jv.Address,
+			PubKey:        pubKey,
+			DepositAmount: (*hexutil.Big)(jv.DepositAmount),
+		})
+	}
+
+	if ep != nil {
+		info.Epoch = ep.ToEpochApi()
+	}
+	if anchor := core.GetSideChainAnchor(db, cci.ChainId); anchor != nil {
+		info.LastAnchoredHeight = (*hexutil.Uint64)(&anchor.Height)
+	}
+	return info
+}
+
+// SideChainEvent is sent when a side chain is launched or decommissioned
+type SideChainEvent struct {
+	Type    string `json:"type"`
+	ChainId string `json:"chainId"`
+}
+
+// SideChainEvents subscribes to the launch and the decommission of the side chains
+func (s *PublicChainAPI) SideChainEvents(ctx context.Context) (*rpc.Subscription, error) {
+
+	if !s.b.ChainConfig().IsMainChain() {
+		return nil, errors.New("side chain events are sent by the main chain")
+	}
+
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		createCh := make(chan core.CreateSideChainEvent, 10)
+		decommissionCh := make(chan core.DecommissionSideChainEvent, 10)
+		createSub := s.b.SubscribeCreateSideChainEvent(createCh)
+		decommissionSub := s.b.SubscribeDecommissionSideChainEvent(decommissionCh)
+		defer createSub.Unsubscribe()
+		defer decommissionSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-createCh:
+				notifier.Notify(rpcSub.ID, &SideChainEvent{Type: "launch", ChainId: ev.ChainId})
+			case ev := <-decommissionCh:
+				notifier.Notify(rpcSub.ID, &SideChainEvent{Type: "decommission", ChainId: ev.ChainId})
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// GetCrossChainMessageProof returns the proof data of the cross chain message
+// emitted in the log of the tx, the log index counts the logs of the tx only
+func (s *PublicChainAPI) GetCrossChainMessageProof(ctx context.Context, txHash common.Hash, logIndex hexutil.Uint) (hexutil.Bytes, error) {
+
+	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(s.b.ChainDb(), txHash)
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %x not found", txHash)
+	}
+	header := rawdb.ReadHeader(s.b.ChainDb(), blockHash, blockNumber)
+	if header == nil {
+		return nil, fmt.Errorf("block %x not found", blockHash)
+	}
+	receipts, err := s.b.GetReceipts(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	proofData, err := types.NewCrossChainMessageProofData(header, receipts, uint(index), uint(logIndex))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := proofData.Message(); err != nil {
+		return nil, err
+	}
+
+	return rlp.EncodeToBytes(proofData)
+}
+
+// DeliverCrossChainMessage sends the cross chain message proved by the proof
+// data to its target, the gas beyond the fixed cost is given to the target call
+func (s *PublicChainAPI) DeliverCrossChainMessage(ctx context.Context, from common.Address, proofData hexutil.Bytes, gas *hexutil.Uint64, gasPrice *hexutil.Big) (common.Hash, error) {
+
+	input, err := neatAbi.ChainABI.Pack(neatAbi.DeliverCrossChainMessage.String(), []byte(proofData))
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	if gas == nil {
+		defaultGas := neatAbi.DeliverCrossChainMessage.RequiredGas() + 200000
+		gas = (*hexutil.Uint64)(&defaultGas)
+	}
+
+	args := SendTxArgs{
+		From:     from,
+		To:       &neatAbi.NeatioSmartContractAddress,
+		Gas:      gas,
+		GasPrice: gasPrice,
+		Value:    nil,
+		Input:    (*hexutil.Bytes)(&input),
+		Nonce:    nil,
+	}
+
+	return SendTransaction(ctx, args, s.am, s.b, s.nonceLock)
+}
+
+// TransferToSideChain burns the amount on this side chain, once the burn is
+// settled on the main chain the same address claims it on the target side chain
+func (s *PublicChainAPI) TransferToSideChain(ctx context.Context, from common.Address, toChainId string, amount *hexutil.Big, gasPrice *hexutil.Big) (common.Hash, error) {
+
+	if s.b.ChainConfig().IsMainChain() {
+		return common.Hash{}, errors.New("side chain transfers are sent from a side chain")
+	}
+	if toChainId == "" || s.b.ChainConfig().NeatChainId == toChainId {
+		return common.Hash{}, fmt.Errorf("invalid side chain id %v", toChainId)
+	}
+	if amount == nil || (*big.Int)(amount).Sign() <= 0 {
+		return common.Hash{}, errors.New("side chain transfer amount must be positive")
+	}
+
+	input, err := neatAbi.ChainABI.Pack(neatAbi.TransferToSideChain.String(), toChainId, common.Hash{}, []byte{})
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	defaultGas := neatAbi.TransferToSideChain.RequiredGas()
+
+	args := SendTxArgs{
+		From:     from,
+		To:       &neatAbi.NeatioSmartContractAddress,
+		Gas:      (*hexutil.Uint64)(&defaultGas),
+		GasPrice: gasPrice,
+		Value:    amount,
+		Input:    (*hexutil.Bytes)(&input),
+		Nonce:    nil,
+	}
+
+	return SendTransaction(ctx, args, s.am, s.b, s.nonceLock)
+}
+
+// SettleTransferToSideChain moves the transfer burned by the tx of the side
+// chain to the target side chain, the tx3 proof data of the burn must have
+// been received from the side chain
+func (s *PublicChainAPI) SettleTransferToSideChain(ctx context.Context, from common.Address, fromChainId string, txHash common.Hash, gasPrice *hexutil.Big) (common.Hash, error) {
+
+	if !s.b.ChainConfig().IsMainChain() {
+		return common.Hash{}, errors.New("side chain transfers are settled on the main chain")
+	}
+
+	proofData := s.b.GetCrossChainHelper().GetTX3ProofData(fromChainId, txHash)
+	if proofData == nil {
+		return common.Hash{}, fmt.Errorf("tx3 proof data of %x not found", txHash)
+	}
+	bs, err := rlp.EncodeToBytes(proofData)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	_, _, burnArgs, err := types.DecodeSideChainTransfer(bs, txHash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	input, err := neatAbi.ChainABI.Pack(neatAbi.TransferToSideChain.String(), burnArgs.ToChainId, txHash, bs)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	defaultGas := neatAbi.TransferToSideChain.RequiredGas()
+
+	args := SendTxArgs{
+		From:     from,
+		To:       &neatAbi.NeatioSmartContractAddress,
+		Gas:      (*hexutil.Uint64)(&defaultGas),
+		GasPrice: gasPrice,
+		Value:    nil,
+		Input:    (*hexutil.Bytes)(&input),
+		Nonce:    nil,
+	}
+
+	return SendTransaction(ctx, args, s.am, s.b, s.nonceLock)
+}
+
+// ClaimTransferToSideChain credits the transfer settled by the tx of the main
+// chain on this side chain, anyone may send it for the address burning it
+func (s *PublicChainAPI) ClaimTransferToSideChain(ctx context.Context, from common.Address, txHash common.Hash, gasPrice *hexutil.Big) (common.Hash, error) {
+
+	if s.b.ChainConfig().IsMainChain() {
+		return common.Hash{}, errors.New("side chain transfers are claimed on the target side chain")
+	}
+
+	input, err := neatAbi.ChainABI.Pack(neatAbi.TransferToSideChain.String(), s.b.ChainConfig().NeatChainId, txHash, []byte{})
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	defaultGas := neatAbi.TransferToSideChain.RequiredGas()
+
+	args := SendTxArgs{
+		From:     from,
+		To:       &neatAbi.NeatioSmartContractAddress,
+		Gas:      (*hexutil.Uint64)(&defaultGas),
+		GasPrice: gasPrice,
+		Value:    nil,
+		Input:    (*hexutil.Bytes)(&input),
+		Nonce:    nil,
+	}
+
+	return SendTransaction(ctx, args, s.am, s.b, s.nonceLock)
+}
+
+// SetBlockReward sets the reward per block of the side chain, sent by the
+// owner it is set at once, sent by a validator it is a vote which sets the
+// reward when more than 2/3 of the voting power agrees
+func (s *PublicChainAPI) SetBlockReward(ctx context.Context, from common.Address, reward *hexutil.Big, gasPrice *hexutil.Big) (common.Hash, error) {
+
+	if s.b.ChainConfig().IsMainChain() {
+		return common.Hash{}, errors.New("the block reward is set on a side chain")
+	}
+	if reward == nil || (*big.Int)(reward).Sign() < 0 {
+		return common.Hash{}, errors.New("block reward must not be negative")
+	}
+
+	input, err := neatAbi.ChainABI.Pack(neatAbi.SetBlockReward.String(), s.b.ChainConfig().NeatChainId, (*big.Int)(reward))
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	defaultGas := neatAbi.SetBlockReward.RequiredGas()
+
+	args := SendTxArgs{
+		From:     from,
+		To:       &neatAbi.NeatioSmartContractAddress,
+		Gas:      (*hexutil.Uint64)(&defaultGas),
+		GasPrice: gasPrice,
+		Value:    (*hexutil.Big)(big.NewInt(0)),
+		Input:    (*hexutil.Bytes)(&input),
+		Nonce:    nil,
+	}
+
+	return SendTransaction(ctx, args, s.am, s.b, s.nonceLock)
+}
+
+type RewardPool struct {
+	Address         common.Address     `json:"address"`
+	Balance         *hexutil.Big       `json:"balance"`
+	RewardPerBlock  *hexutil.Big       `json:"rewardPerBlock"`
+	RemainingBlocks *hexutil.Big       `json:"remainingBlocks"`
+	Votes           []*BlockRewardVote `json:"votes"`
+}
+
+type BlockRewardVote struct {
+	Validator common.Address `json:"validator"`
+	Reward    *hexutil.Big   `json:"reward"`
+}
+
+// GetRewardPool returns the pool the side chain block rewards are paid from
+// and the number of blocks it lasts at the current reward, the remaining
+// blocks are null when no reward is paid
+func (s *PublicChainAPI) GetRewardPool(ctx context.Context, blockNr rpc.BlockNumber) (*RewardPool, error) {
+
+	if s.b.ChainConfig().IsMainChain() {
+		return nil, errors.New("the reward pool is on the side chains")
+	}
+
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+
+	balance := state.GetBalance(ntcTypes.SideChainRewardAddress)
+	reward := state.GetSideChainRewardPerBlock()
+	if reward == nil {
+		reward = big.NewInt(0)
+	}
+
+	pool := &RewardPool{
+		Address:        ntcTypes.SideChainRewardAddress,
+		Balance:        (*hexutil.Big)(balance),
+		RewardPerBlock: (*hexutil.Big)(reward),
+		Votes:          make([]*BlockRewardVote, 0),
+	}
+	if reward.Sign() > 0 {
+		// the last block is paid whatever is left in the pool
+		remaining := new(big.Int).Add(balance, reward)
+		remaining.Sub(remaining, big.NewInt(1))
+		remaining.Quo(remaining, reward)
+		pool.RemainingBlocks = (*hexutil.Big)(remaining)
+	}
+	for _, v := range state.GetBlockRewardVotes() {
+		pool.Votes = append(pool.Votes, &BlockRewardVote{
+			Validator: v.Validator,
+			Reward:    (*hexutil.Big)(v.Reward),
+		})
+	}
+	return pool, nil
+}
+
+// RelayData is what the side chain block sends to the main chain, the proof
+// data is set if the block carries an epoch and the tx3 proof data is set if
+// the block withdraws or transfers to another side chain
+type RelayData struct {
+	Number       hexutil.Uint64 `json:"number"`
+	Hash         common.Hash    `json:"hash"`
+	ProofData    hexutil.Bytes  `json:"proofData,omitempty"`
+	TX3ProofData hexutil.Bytes  `json:"tx3ProofData,omitempty"`
+}
+
+// GetRelayData returns the data of the side chain block to relay to the main chain
+func (s *PublicChainAPI) GetRelayData(ctx context.Context, blockNr rpc.BlockNumber) (*RelayData, error) {
+
+	if s.b.ChainConfig().IsMainChain() {
+		return nil, errors.New("the main chain does not relay")
+	}
+
+	block, err := s.b.BlockByNumber(ctx, blockNr)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	ncExtra, err := ntcTypes.ExtractNeatConExtra(block.Header())
+	if err != nil {
+		return nil, err
+	}
+
+	data := &RelayData{
+		Number: hexutil.Uint64(block.NumberU64()),
+		Hash:   block.Hash(),
+	}
+	if ncExtra.NeedToSave {
+		proofData, err := types.NewSideChainProofData(block)
+		if err != nil {
+			return nil, err
+		}
+		if data.ProofData, err = rlp.EncodeToBytes(proofData); err != nil {
+			return nil, err
+		}
+	}
+	if ncExtra.NeedToBroadcast {
+		receipts, err := s.b.GetReceipts(ctx, block.Hash())
+		if err != nil {
+			return nil, err
+		}
+		proofData, err := types.NewTX3ProofData(block, receipts)
+		if err != nil {
+			return nil, err
+		}
+		if data.TX3ProofData, err = rlp.EncodeToBytes(proofData); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// BroadcastTX3ProofData validates the proof data of the withdrawals of a side
+// chain block, keeps it for the WithdrawFromMainChain and TransferToSideChain txs
+// and sends it to the peers
+func (s *PublicChainAPI) BroadcastTX3ProofData(ctx context.Context, bs hexutil.Bytes) error {
+
+	if !s.b.ChainConfig().IsMainChain() {
+		return errors.New("tx3 proof data is only sent to the main chain")
+	}
+
+	var proofData types.TX3ProofData
+	if err := rlp.DecodeBytes(bs, &proofData); err != nil {
+		return err
+	}
+
+	cch := s.b.GetCrossChainHelper()
+	if err := cch.ValidateTX3ProofData(&proofData); err != nil {
+		return err
+	}
+	if err := cch.WriteTX3ProofData(&proofData); err != nil {
+		return err
+	}
+
+	s.b.BroadcastTX3ProofData(&proofData)
+	return nil
+}
+
+func init() {
+	core.RegisterValidateCb(neatAbi.CreateSideChain, createSideChainValidateCb)
+	core.RegisterForkApplyCb(neatAbi.CreateSideChain, createSideChainApplyCb)
+
+	core.RegisterValidateCb(neatAbi.JoinSideChain, joinSideChainValidateCb)
+	core.RegisterForkApplyCb(neatAbi.JoinSideChain, joinSideChainApplyCb)
+
+	core.RegisterValidateCb(neatAbi.SaveDataToMainChain, saveDataToMainChainValidateCb)
+	core.RegisterForkApplyCb(neatAbi.SaveDataToMainChain, saveDataToMainChainApplyCb)
+
+	core.RegisterValidateCb(neatAbi.DecommissionSideChain, decommissionSideChainValidateCb)
+	core.RegisterForkApplyCb(neatAbi.DecommissionSideChain, decommissionSideChainApplyCb)
+
+	core.RegisterValidateCb(neatAbi.DeliverCrossChainMessage, deliverCrossChainMessageValidateCb)
+	core.RegisterApplyCb(neatAbi.DeliverCrossChainMessage, deliverCrossChainMessageApplyCb)
+
+	core.RegisterValidateCb(neatAbi.TransferToSideChain, transferToSideChainValidateCb)
+	core.RegisterForkApplyCb(neatAbi.TransferToSideChain, transferToSideChainApplyCb)
+
+	core.RegisterValidateCb(neatAbi.SetBlockReward, setBlockRewardValidateCb)
+	core.RegisterForkApplyCb(neatAbi.SetBlockReward, setBlockRewardApplyCb)
+}
+
+func createSideChainValidateCb(tx *types.Transaction, state *state.StateDB, cch core.CrossChainHelper) error {
+	from := derivedAddressFromTx(tx)
+	_, err := createSideChainValidation(from, tx, state, cch)
+	return err
+}
+
+func createSideChainApplyCb(tx *types.Transaction, state *state.StateDB, ops *types.PendingOps, cch core.CrossChainHelper, mining bool) error {
+	from := derivedAddressFromTx(tx)
+	args, err := createSideChainValidation(from, tx, state, cch)
+	if err != nil {
+		return err
+	}
+
+	// the startup cost is kept in the chain balance of the owner until the chain launches or expires
+	createChainAmount := tx.Value()
+	state.SubBalance(from, createChainAmount)
+	state.AddChainBalance(from, createChainAmount)
+
+	op := types.CreateSideChainOp{
+		From:             from,
+		ChainId:          args.ChainId,
+		MinValidators:    args.MinValidators,
+		MinDepositAmount: args.MinDepositAmount,
+		StartBlock:       args.StartBlock,
+		EndBlock:         args.EndBlock,
+	}
+	if ok := ops.Append(&op); !ok {
+		return fmt.Errorf("pending ops conflict: %v", op)
+	}
+
+	return nil
+}
+
+func createSideChainValidation(from common.Address, tx *types.Transaction, state *state.StateDB, cch core.CrossChainHelper) (*neatAbi.CreateSideChainArgs, error) {
+
+	var args neatAbi.CreateSideChainArgs
+	data := tx.Data()
+	if err := neatAbi.ChainABI.UnpackMethodInputs(&args, neatAbi.CreateSideChain.String(), data[4:]); err != nil {
+		return nil, err
+	}
+
+	if err := cch.CanCreateSideChain(from, args.ChainId, args.MinValidators, args.MinDepositAmount, tx.Value(), args.StartBlock, args.EndBlock); err != nil {
+		return nil, err
+	}
+
+	return &args, nil
+}
+
+func joinSideChainValidateCb(tx *types.Transaction, state *state.StateDB, cch core.CrossChainHelper) error {
+	from := derivedAddressFromTx(tx)
+	_, err := joinSideChainValidation(from, tx, state, cch)
+	return err
+}
+
+func joinSideChainApplyCb(tx *types.Transaction, state *state.StateDB, ops *types.PendingOps, cch core.CrossChainHelper, mining bool) error {
+	from := derivedAddressFromTx(tx)
+	args, err := joinSideChainValidation(from, tx, state, cch)
+	if err != nil {
+		return err
+	}
+
+	// the deposit is locked until the side chain launches or expires
+	depositAmount := tx.Value()
+	state.SubBalance(from, depositAmount)
+	state.AddSideChainDepositBalance(from, args.ChainId, depositAmount)
+
+	var blsPK goCrypto.BLSPubKey
+	copy(blsPK[:], args.PubKey)
+
+	op := types.JoinSideChainOp{
+		From:          from,
+		PubKey:        blsPK,
+		ChainId:       args.ChainId,
+		DepositAmount: depositAmount,
+	}
+	if ok := ops.Append(&op); !ok {
+		return fmt.Errorf("pending ops conflict: %v", op)
+	}
+
+	return nil
+}
+
+func joinSideChainValidation(from common.Address, tx *types.Transaction, state *state.StateDB, cch core.CrossChainHelper) (*neatAbi.JoinSideChainArgs, error) {
+
+	var args neatAbi.JoinSideChainArgs
+	data := tx.Data()
+	if err := neatAbi.ChainABI.UnpackMethodInputs(&args, neatAbi.JoinSideChain.String(), data[4:]); err != nil {
+		return nil, err
+	}
+
+	if err := cch.ValidateJoinSideChain(from, args.PubKey, args.ChainId, tx.Value(), args.Signature); err != nil {
+		return nil, err
+	}
+
+	return &args, nil
+}
+
+func saveDataToMainChainValidateCb(tx *types.Transaction, state *state.StateDB, cch core.CrossChainHelper) error {
+	_, err := saveDataToMainChainValidation(tx, cch)
+	return err
+}
+
+// saveDataToMainChainApplyCb anchors the side chain block, the epoch and the
+// state root it carries are saved once the main chain block is committed
+func saveDataToMainChainApplyCb(tx *types.Transaction, state *state.StateDB, ops *types.PendingOps, cch core.CrossChainHelper, mining bool) error {
+	args, err := saveDataToMainChainValidation(tx, cch)
+	if err != nil {
+		return err
+	}
+
+	// contracts verify the side chain headers against the blocks saved in the state
+	var proofData types.SideChainProofData
+	if err := rlp.DecodeBytes(args.Data, &proofData); err != nil {
+		return err
+	}
+	chainId, err := core.HeaderChainId(proofData.Header)
+	if err != nil {
+		return err
+	}
+	state.SetSideChainAnchor(chainId, proofData.Header.Number.Uint64(), proofData.Header.Hash(), proofData.Header.ReceiptHash)
+
+	op := types.SaveDataToMainChainOp{
+		Data: args.Data,
+	}
+	if ok := ops.Append(&op); !ok {
+		return fmt.Errorf("pending ops conflict: %v", op)
+	}
+
+	return nil
+}
+
+func saveDataToMainChainValidation(tx *types.Transaction, cch core.CrossChainHelper) (*neatAbi.SaveDataToMainChainArgs, error) {
+
+	var args neatAbi.SaveDataToMainChainArgs
+	data := tx.Data()
+	if err := neatAbi.ChainABI.UnpackMethodInputs(&args, neatAbi.SaveDataToMainChain.String(), data[4:]); err != nil {
+		return nil, err
+	}
+
+	if err := cch.VerifySideChainProofData(args.Data); err != nil {
+		return nil, err
+	}
+
+	return &args, nil
+}
+
+func decommissionSideChainValidateCb(tx *types.Transaction, state *state.StateDB, cch core.CrossChainHelper) error {
+	from := derivedAddressFromTx(tx)
+	_, err := decommissionSideChainValidation(from, tx, state, cch)
+	return err
+}
+
+func decommissionSideChainApplyCb(tx *types.Transaction, state *state.StateDB, ops *types.PendingOps, cch core.CrossChainHelper, mining bool) error {
+	from := derivedAddressFromTx(tx)
+	args, err := decommissionSideChainValidation(from, tx, state, cch)
+	if err != nil {
+		return err
+	}
+
+	op := types.DecommissionSideChainOp{
+		ChainId:   args.ChainId,
+		ProofData: args.ProofData,
+	}
+	if ok := ops.Append(&op); !ok {
+		return fmt.Errorf("pending ops conflict: %v", op)
+	}
+
+	// the deposits locked since the launch are refunded
+	return cch.SettleSideChainDeposits(args.ChainId, state)
+}
+
+func decommissionSideChainValidation(from common.Address, tx *types.Transaction, state *state.StateDB, cch core.CrossChainHelper) (*neatAbi.DecommissionSideChainArgs, error) {
+
+	var args neatAbi.DecommissionSideChainArgs
+	data := tx.Data()
+	if err := neatAbi.ChainABI.UnpackMethodInputs(&args, neatAbi.DecommissionSideChain.String(), data[4:]); err != nil {
+		return nil, err
+	}
+
+	if err := cch.ValidateDecommissionSideChain(from, args.ChainId, args.ProofData); err != nil {
+		return nil, err
+	}
+
+	return &args, nil
+}
+
+func deliverCrossChainMessageValidateCb(tx *types.Transaction, state *state.StateDB, cch core.CrossChainHelper) error {
+	_, err := deliverCrossChainMessageValidation(tx, state, cch)
+	return err
+}
+
+// deliverCrossChainMessageApplyCb only marks the message delivered, the target
+// is called by the state processor with the gas left by the tx
+func deliverCrossChainMessageApplyCb(tx *types.Transaction, state *state.StateDB, ops *types.PendingOps, cch core.CrossChainHelper, mining bool) error {
+	proofData, err := deliverCrossChainMessageValidation(tx, state, cch)
+	if err != nil {
+		return err
+	}
+
+	state.MarkCrossChainMessageDelivered(proofData.MessageId())
+	return nil
+}
+
+func deliverCrossChainMessageValidation(tx *types.Transaction, state *state.StateDB, cch core.CrossChainHelper) (*types.CrossChainMessageProofData, error) {
+
+	proofData, msg, err := core.DecodeCrossChainMessage(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx.ChainId().Cmp(params.EIP155ChainId(msg.ToChainId)) != 0 {
+		return nil, core.ErrCrossChainMessageWrongChain
+	}
+
+	if state.IsCrossChainMessageDelivered(proofData.MessageId()) {
+		return nil, core.ErrCrossChainMessageDelivered
+	}
+
+	if _, err := cch.VerifyCrossChainMessageHeader(proofData.Header); err != nil {
+		return nil, err
+	}
+
+	return proofData, nil
+}
+
+// the TransferToSideChain tx burns on the source side chain, settles on the
+// main chain and is claimed on the target side chain
+func transferToSideChainValidateCb(tx *types.Transaction, state *state.StateDB, cch core.CrossChainHelper) error {
+	args, err := unpackTransferToSideChain(tx)
+	if err != nil {
+		return err
+	}
+
+	if isMainChainTx(tx, cch) {
+		_, _, _, _, err = settleTransferToSideChainValidation(tx, args, state, cch)
+	} else if args.TxHash == (common.Hash{}) {
+		err = burnTransferToSideChainValidation(tx, args, cch)
+	} else {
+		_, _, err = claimTransferToSideChainValidation(tx, args, state, cch)
+	}
+	return err
+}
+
+func transferToSideChainApplyCb(tx *types.Transaction, state *state.StateDB, ops *types.PendingOps, cch core.CrossChainHelper, mining bool) error {
+	args, err := unpackTransferToSideChain(tx)
+	if err != nil {
+		return err
+	}
+
+	if isMainChainTx(tx, cch) {
+		id, burn, fromOwner, toOwner, err := settleTransferToSideChainValidation(tx, args, state, cch)
+		if err != nil {
+			return err
+		}
+
+		// the value backing the transfer moves from the source to the target side chain
+		state.SubChainBalance(fromOwner, burn.Value())
+		state.AddChainBalance(toOwner, burn.Value())
+		state.MarkCrossChainMessageDelivered(id)
+	} else if args.TxHash == (common.Hash{}) {
+		if err := burnTransferToSideChainValidation(tx, args, cch); err != nil {
+			return err
+		}
+
+		state.SubBalance(derivedAddressFromTx(tx), tx.Value())
+	} else {
+		id, burn, err := claimTransferToSideChainValidation(tx, args, state, cch)
+		if err != nil {
+			return err
+		}
+
+		state.AddBalance(derivedAddressFromTx(burn), burn.Value())
+		state.MarkCrossChainMessageDelivered(id)
+	}
+	return nil
+}
+
+func unpackTransferToSideChain(tx *types.Transaction) (*neatAbi.TransferToSideChainArgs, error) {
+	var args neatAbi.TransferToSideChainArgs
+	data := tx.Data()
+	if err := neatAbi.ChainABI.UnpackMethodInputs(&args, neatAbi.TransferToSideChain.String(), data[4:]); err != nil {
+		return nil, err
+	}
+	return &args, nil
+}
+
+func isMainChainTx(tx *types.Transaction, cch core.CrossChainHelper) bool {
+	return tx.ChainId().Cmp(params.EIP155ChainId(cch.GetMainChainId())) == 0
+}
+
+func burnTransferToSideChainValidation(tx *types.Transaction, args *neatAbi.TransferToSideChainArgs, cch core.CrossChainHelper) error {
+
+	if len(args.ProofData) != 0 {
+		return errors.New("no proof data is sent with the side chain transfer")
+	}
+	if tx.Value().Sign() <= 0 {
+		return errors.New("side chain transfer amount must be positive")
+	}
+
+	if args.ToChainId == cch.GetMainChainId() || tx.ChainId().Cmp(params.EIP155ChainId(args.ToChainId)) == 0 {
+		return fmt.Errorf("invalid side chain id %v", args.ToChainId)
+	}
+	if !cch.CheckSideChainRunning(args.ToChainId) {
+		return fmt.Errorf("side chain %v is not running", args.ToChainId)
+	}
+
+	return nil
+}
+
+// settleTransferToSideChainValidation checks the burn against the tx3 proof
+// data and returns the chain owners holding the chain balances
+func settleTransferToSideChainValidation(tx *types.Transaction, args *neatAbi.TransferToSideChainArgs, state *state.StateDB, cch core.CrossChainHelper) (common.Hash, *types.Transaction, common.Address, common.Address, error) {
+
+	if tx.Value().Sign() != 0 {
+		return common.Hash{}, nil, common.Address{}, common.Address{}, errors.New("no value is sent with the side chain transfer settlement")
+	}
+
+	proofData, burn, burnArgs, err := types.DecodeSideChainTransfer(args.ProofData, args.TxHash)
+	if err != nil {
+		return common.Hash{}, nil, common.Address{}, common.Address{}, err
+	}
+	if burnArgs.ToChainId != args.ToChainId {
+		return common.Hash{}, nil, common.Address{}, common.Address{}, errors.New("params are not consistent with tx in side chain")
+	}
+
+	if err := cch.ValidateTX3ProofData(proofData); err != nil {
+		return common.Hash{}, nil, common.Address{}, common.Address{}, err
+	}
+	ncExtra, err := ntcTypes.ExtractNeatConExtra(proofData.Header)
+	if err != nil {
+		return common.Hash{}, nil, common.Address{}, common.Address{}, err
+	}
+
+	id := types.SideChainTransferId(ncExtra.ChainID, args.TxHash)
+	if state.IsCrossChainMessageDelivered(id) {
+		return common.Hash{}, nil, common.Address{}, common.Address{}, core.ErrSideChainTransferSettled
+	}
+
+	fromCi := core.GetChainInfo(cch.GetChainInfoDB(), ncExtra.ChainID)
+	if fromCi == nil {
+		return common.Hash{}, nil, common.Address{}, common.Address{}, fmt.Errorf("chain info %s not found", ncExtra.ChainID)
+	}
+	if ncExtra.ChainID == args.ToChainId || !cch.CheckSideChainRunning(args.ToChainId) {
+		return common.Hash{}, nil, common.Address{}, common.Address{}, fmt.Errorf("side chain %v is not running", args.ToChainId)
+	}
+	toCi := core.GetChainInfo(cch.GetChainInfoDB(), args.ToChainId)
+	if toCi == nil {
+		return common.Hash{}, nil, common.Address{}, common.Address{}, fmt.Errorf("chain info %s not found", args.ToChainId)
+	}
+
+	if state.GetChainBalance(fromCi.Owner).Cmp(burn.Value()) < 0 {
+		return common.Hash{}, nil, common.Address{}, common.Address{}, fmt.Errorf("insufficient chain balance of side chain %v", ncExtra.ChainID)
+	}
+
+	return id, burn, fromCi.Owner, toCi.Owner, nil
+}
+
+// claimTransferToSideChainValidation checks the settle tx in the main chain,
+// the tx3 proof data in it was validated when the main chain took it
+func claimTransferToSideChainValidation(tx *types.Transaction, args *neatAbi.TransferToSideChainArgs, state *state.StateDB, cch core.CrossChainHelper) (common.Hash, *types.Transaction, error) {
+
+	if len(args.ProofData) != 0 || tx.Value().Sign() != 0 {
+		return common.Hash{}, nil, errors.New("no proof data nor value is sent with the side chain transfer claim")
+	}
+	if tx.ChainId().Cmp(params.EIP155ChainId(args.ToChainId)) != 0 {
+		return common.Hash{}, nil, errors.New("side chain transfer not sent to this chain")
+	}
+
+	settle := cch.GetTxFromMainChain(args.TxHash)
+	if settle == nil {
+		return common.Hash{}, nil, fmt.Errorf("tx %x not found in main chain", args.TxHash)
+	}
+	if !neatAbi.IsNeatChainContractAddr(settle.To()) || len(settle.Data()) < 4 {
+		return common.Hash{}, nil, errors.New("not a side chain transfer settled in main chain")
+	}
+	if function, err := neatAbi.FunctionTypeFromId(settle.Data()[:4]); err != nil || function != neatAbi.TransferToSideChain {
+		return common.Hash{}, nil, errors.New("not a side chain transfer settled in main chain")
+	}
+	settleArgs, err := unpackTransferToSideChain(settle)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	if settleArgs.ToChainId != args.ToChainId {
+		return common.Hash{}, nil, errors.New("side chain transfer not sent to this chain")
+	}
+
+	proofData, burn, _, err := types.DecodeSideChainTransfer(settleArgs.ProofData, settleArgs.TxHash)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	ncExtra, err := ntcTypes.ExtractNeatConExtra(proofData.Header)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	id := types.SideChainTransferId(ncExtra.ChainID, settleArgs.TxHash)
+	if state.IsCrossChainMessageDelivered(id) {
+		return common.Hash{}, nil, core.ErrSideChainTransferSettled
+	}
+
+	return id, burn, nil
+}
+
+func setBlockRewardValidateCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) error {
+	from := derivedAddressFromTx(tx)
+	_, _, _, err := setBlockRewardValidation(from, tx, state, bc)
+	return err
+}
+
+func setBlockRewardApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain, ops *types.PendingOps) error {
+	from := derivedAddressFromTx(tx)
+	args, isOwner, ep, err := setBlockRewardValidation(from, tx, state, bc)
+	if err != nil {
+		return err
+	}
+
+	if isOwner {
+		state.SetSideChainRewardPerBlock(args.Reward)
+		state.ClearBlockRewardVotes()
+		return nil
+	}
+
+	state.SetBlockRewardVote(from, args.Reward)
+
+	// the votes of the addresses no longer in the validator set do not count
+	votingPower := big.NewInt(0)
+	for _, v := range state.GetBlockRewardVotes() {
+		if v.Reward.Cmp(args.Reward) != 0 {
+			continue
+		}
+		if _, val := ep.Validators.GetByAddress(v.Validator.Bytes()); val != nil {
+			votingPower.Add(votingPower, val.VotingPower)
+		}
+	}
+	quorum := new(big.Int).Mul(ep.Validators.TotalVotingPower(), big.NewInt(2))
+	if votingPower.Mul(votingPower, big.NewInt(3)).Cmp(quorum) > 0 {
+		state.SetSideChainRewardPerBlock(args.Reward)
+		state.ClearBlockRewardVotes()
+	}
+
+	return nil
+}
+
+// setBlockRewardValidation returns whether the sender is the owner of the side
+// chain, the epoch is returned for counting the votes of the validators
+func setBlockRewardValidation(from common.Address, tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) (*neatAbi.SetBlockRewardArgs, bool, *epoch.Epoch, error) {
+
+	if bc.Config().IsMainChain() {
+		return nil, false, nil, errors.New("the block reward is set on a side chain")
+	}
+	if tx.Value().Sign() != 0 {
+		return nil, false, nil, errors.New("set block reward does not take any value")
+	}
+
+	var args neatAbi.SetBlockRewardArgs
+	data := tx.Data()
+	if err := neatAbi.ChainABI.UnpackMethodInputs(&args, neatAbi.SetBlockReward.String(), data[4:]); err != nil {
+		return nil, false, nil, err
+	}
+
+	if args.ChainId != bc.Config().NeatChainId {
+		return nil, false, nil, fmt.Errorf("invalid chain id %v", args.ChainId)
+	}
+
+	ep, err := getEpoch(bc)
+	if err != nil {
+		return nil, false, nil, err
+	}
+
+	// the owner is saved in the genesis of the side chain
+	owner := common.BytesToAddress(state.GetState(neatAbi.NeatioSideChainsAddress, neatAbi.SideChainOwnerKey).Bytes())
+	if owner != (common.Address{}) && owner == from {
+		return &args, true, ep, nil
+	}
+
+	if !ep.Validators.HasAddress(from.Bytes()) {
+		return nil, false, nil, errors.New("only the owner or the validators of the side chain can set the block reward")
+	}
+
+	return &args, false, ep, nil
+}
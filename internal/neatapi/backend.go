@@ -5,6 +5,7 @@ import (
 	"math/big"
 
 	"github.com/neatio-net/neatio/chain/accounts"
+	"github.com/neatio-net/neatio/chain/consensus"
 	"github.com/neatio-net/neatio/chain/core"
 	"github.com/neatio-net/neatio/chain/core/state"
 	"github.com/neatio-net/neatio/chain/core/types"
@@ -21,6 +22,8 @@ type Backend interface {
 	Downloader() *downloader.Downloader
 	ProtocolVersion() int
 	SuggestPrice(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	FeeHistory(ctx context.Context, blockCount int, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error)
 	ChainDb() neatdb.Database
 	EventMux() *event.TypeMux
 	AccountManager() *accounts.Manager
@@ -36,6 +39,8 @@ type Backend interface {
 	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
 	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
 	SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription
+	SubscribeCreateSideChainEvent(ch chan<- core.CreateSideChainEvent) event.Subscription
+	SubscribeDecommissionSideChainEvent(ch chan<- core.DecommissionSideChainEvent) event.Subscription
 
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
 	GetPoolTransactions() (types.Transactions, error)
@@ -47,6 +52,7 @@ type Backend interface {
 
 	ChainConfig() *params.ChainConfig
 	CurrentBlock() *types.Block
+	Engine() consensus.Engine
 
 	GetCrossChainHelper() core.CrossChainHelper
 
@@ -123,6 +129,11 @@ func GetAPIs(apiBackend Backend, solcPath string) []rpc.API {
 			Version:   "1.0",
 			Service:   NewPublicNEATAPI(apiBackend, nonceLock),
 			Public:    true,
+		}, {
+			Namespace: "chain",
+			Version:   "1.0",
+			Service:   NewPublicChainAPI(apiBackend, nonceLock),
+			Public:    true,
 		},
 	}
 	return append(compiler, all...)
@@ -0,0 +1,85 @@
+package neatapi
+
+import (
+	"math/big"
+
+	"github.com/neatio-net/neatio/chain/core"
+	"github.com/neatio-net/neatio/chain/core/state"
+	"github.com/neatio-net/neatio/chain/core/types"
+	"github.com/neatio-net/neatio/utilities/common"
+)
+
+// stakingHandler applies the operations of the staking precompile with the
+// rules of the delegation transactions, the calling contract is the delegator
+type stakingHandler struct {
+	state *state.StateDB
+	bc    *core.BlockChain
+}
+
+func newStakingHandler(state *state.StateDB, bc *core.BlockChain) core.StakingHandler {
+	return &stakingHandler{state: state, bc: bc}
+}
+
+func (h *stakingHandler) Delegate(delegator, candidate common.Address, amount *big.Int) error {
+	if amount.Sign() == -1 {
+		return core.ErrDelegateAmount
+	}
+	if err := updateValidation(h.bc); err != nil {
+		return err
+	}
+	if err := validateDelegate(delegator, candidate, h.state, h.bc); err != nil {
+		return err
+	}
+
+	applyDelegate(h.state, delegator, candidate, amount)
+	h.state.TouchStakingCandidate(candidate)
+	return nil
+}
+
+func (h *stakingHandler) UnDelegate(delegator, candidate common.Address, amount *big.Int) error {
+	if err := updateValidation(h.bc); err != nil {
+		return err
+	}
+	if err := validateUnDelegate(delegator, candidate, amount, h.state, h.bc); err != nil {
+		return err
+	}
+
+	applyUnDelegate(h.state, delegator, candidate, amount)
+	h.state.TouchStakingCandidate(candidate)
+	return nil
+}
+
+func (h *stakingHandler) WithdrawReward(delegator, delegateAddress common.Address, amount *big.Int) error {
+	if err := validateWithdrawReward(delegator, delegateAddress, amount, h.state); err != nil {
+		return err
+	}
+
+	applyWithdrawReward(h.state, delegator, delegateAddress, amount)
+	return nil
+}
+
+func (h *stakingHandler) GetDelegation(delegator, candidate common.Address) (proxied, depositProxied, pendingRefund, reward *big.Int) {
+	return h.state.GetProxiedBalanceByUser(candidate, delegator),
+		h.state.GetDepositProxiedBalanceByUser(candidate, delegator),
+		h.state.GetPendingRefundBalanceByUser(candidate, delegator),
+		h.state.GetRewardBalanceByDelegateAddress(delegator, candidate)
+}
+
+func (h *stakingHandler) GetCandidate(candidate common.Address) (isCandidate bool, commission uint8, proxied, depositProxied *big.Int) {
+	return h.state.IsCandidate(candidate),
+		h.state.GetCommission(candidate),
+		h.state.GetTotalProxiedBalance(candidate),
+		h.state.GetTotalDepositProxiedBalance(candidate)
+}
+
+// UpdateNextEpochVoteSet updates the next epoch vote set with the voting power
+// of the candidates touched by the transaction, like the delegation
+// transactions do, the touches of the reverted calls are dropped by the state
+func (h *stakingHandler) UpdateNextEpochVoteSet(tx *types.Transaction, ops *types.PendingOps) error {
+	for _, candidate := range h.state.StakingCandidates() {
+		if err := updateNextEpochValidatorVoteSet(tx, h.state, h.bc, candidate, ops); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package neatapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/neatio-net/neatio/chain/accounts"
+	"github.com/neatio-net/neatio/chain/accounts/keystore"
+	"github.com/neatio-net/neatio/chain/consensus"
+	"github.com/neatio-net/neatio/chain/consensus/neatcon/epoch"
+	"github.com/neatio-net/neatio/chain/core"
+	"github.com/neatio-net/neatio/chain/log"
+	neatAbi "github.com/neatio-net/neatio/neatabi/abi"
+	"github.com/neatio-net/neatio/utilities/common"
+	"github.com/neatio-net/neatio/utilities/common/hexutil"
+	"github.com/neatio-net/neatio/utilities/crypto"
+)
+
+const voteStoreDir = "votes"
+
+// VoteRecord is what is needed to reveal a vote for the next epoch. It is
+// saved in the keystore directory of the voting account when the vote hash is
+// sent, so the salt survives restarts.
+type VoteRecord struct {
+	Epoch        hexutil.Uint64 `json:"epoch"`
+	From         common.Address `json:"from"`
+	PubKey       hexutil.Bytes  `json:"pubKey"`
+	Amount       *hexutil.Big   `json:"amount"`
+	Salt         string         `json:"salt"`
+	Signature    hexutil.Bytes  `json:"signature"`
+	VoteHash     common.Hash    `json:"voteHash"`
+	VoteTxHash   common.Hash    `json:"voteTxHash"`
+	RevealTxHash common.Hash    `json:"revealTxHash"`
+}
+
+func calcVoteHash(from common.Address, pubkey []byte, amount *big.Int, salt string) common.Hash {
+	byteData := [][]byte{
+		from.Bytes(),
+		pubkey,
+		amount.Bytes(),
+		[]byte(salt),
+	}
+	return crypto.Keccak256Hash(concatCopyPreAllocate(byteData))
+}
+
+func newVoteSalt() (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	return hexutil.Encode(salt)[2:], nil
+}
+
+// voteRecordFile returns <keystore>/votes/<address>.json of the account
+func voteRecordFile(am *accounts.Manager, from common.Address) (string, error) {
+	account, err := fetchKeystore(am).Find(accounts.Account{Address: from})
+	if err != nil {
+		return "", err
+	}
+	if account.URL.Scheme != keystore.KeyStoreScheme {
+		return "", fmt.Errorf("account %x is not in the keystore", from)
+	}
+	name := strings.ToLower(from.Hex()[2:]) + ".json"
+	return filepath.Join(filepath.Dir(account.URL.Path), voteStoreDir, name), nil
+}
+
+func saveVoteRecord(am *accounts.Manager, record *VoteRecord) error {
+	file, err := voteRecordFile(am, record.From)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+func loadVoteRecord(am *accounts.Manager, from common.Address) (*VoteRecord, error) {
+	file, err := voteRecordFile(am, from)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var record VoteRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func getEpochFromBackend(b Backend) (*epoch.Epoch, error) {
+	var ep *epoch.Epoch
+	if nc, ok := b.Engine().(consensus.NeatCon); ok {
+		ep = nc.GetEpoch().GetEpochByBlockNumber(b.CurrentBlock().NumberU64())
+	}
+	if ep == nil {
+		return nil, errors.New("epoch is nil, are you running on NeatCon Consensus Engine")
+	}
+	return ep, nil
+}
+
+func sendRevealVote(ctx context.Context, b Backend, nonceLock *AddrLocker, record *VoteRecord, gasPrice *hexutil.Big) (common.Hash, error) {
+	input, err := neatAbi.ChainABI.Pack(neatAbi.RevealVote.String(), []byte(record.PubKey), (*big.Int)(record.Amount), record.Salt, []byte(record.Signature))
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	defaultGas := neatAbi.RevealVote.RequiredGas()
+
+	args := SendTxArgs{
+		From:     record.From,
+		To:       &neatAbi.NeatioSmartContractAddress,
+		Gas:      (*hexutil.Uint64)(&defaultGas),
+		GasPrice: gasPrice,
+		Value:    nil,
+		Input:    (*hexutil.Bytes)(&input),
+		Nonce:    nil,
+	}
+	hash, err := SendTransaction(ctx, args, b.AccountManager(), b, nonceLock)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	record.RevealTxHash = hash
+	if err := saveVoteRecord(b.AccountManager(), record); err != nil {
+		log.Warnf("Failed to save the vote record of %x after reveal: %v", record.From, err)
+	}
+	return hash, nil
+}
+
+// VoteRevealer reveals the votes saved in the keystore directory once the
+// reveal stage of the epoch opens, the voting accounts must be unlocked
+type VoteRevealer struct {
+	b         Backend
+	nonceLock *AddrLocker
+	quit      chan struct{}
+}
+
+func NewVoteRevealer(b Backend) *VoteRevealer {
+	return &VoteRevealer{
+		b:         b,
+		nonceLock: new(AddrLocker),
+		quit:      make(chan struct{}),
+	}
+}
+
+func (vr *VoteRevealer) Start() {
+	go vr.loop()
+}
+
+func (vr *VoteRevealer) Stop() {
+	close(vr.quit)
+}
+
+func (vr *VoteRevealer) loop() {
+	headCh := make(chan core.ChainHeadEvent, 10)
+	headSub := vr.b.SubscribeChainHeadEvent(headCh)
+	defer headSub.Unsubscribe()
+
+	for {
+		select {
+		case head := <-headCh:
+			vr.revealVotes(head.Block.NumberU64())
+		case <-headSub.Err():
+			return
+		case <-vr.quit:
+			return
+		}
+	}
+}
+
+func (vr *VoteRevealer) revealVotes(height uint64) {
+	ep, err := getEpochFromBackend(vr.b)
+	if err != nil || !ep.CheckInRevealVoteStage(height) {
+		return
+	}
+	next := ep.GetNextEpoch()
+	if next == nil {
+		return
+	}
+
+	for _, account := range fetchKeystore(vr.b.AccountManager()).Accounts() {
+		record, err := loadVoteRecord(vr.b.AccountManager(), account.Address)
+		if err != nil || uint64(record.Epoch) != next.Number {
+			continue
+		}
+
+		// revealed already, or the reveal tx is still waiting in the pool
+		if voteSet := next.GetEpochValidatorVoteSet(); voteSet != nil {
+			if vote, exist := voteSet.GetVoteByAddress(record.From); exist && vote.VoteHash == record.VoteHash && vote.Salt == record.Salt {
+				continue
+			}
+		}
+		if (record.RevealTxHash != common.Hash{}) && vr.b.GetPoolTransaction(record.RevealTxHash) != nil {
+			continue
+		}
+
+		hash, err := sendRevealVote(context.Background(), vr.b, vr.nonceLock, record, nil)
+		if err != nil {
+			log.Warnf("Failed to reveal the vote of %x for epoch %v: %v", record.From, next.Number, err)
+			continue
+		}
+		log.Infof("Revealed the vote of %x for epoch %v, tx hash: %x", record.From, next.Number, hash)
+	}
+}
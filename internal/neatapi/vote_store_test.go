@@ -0,0 +1,67 @@
+package neatapi
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neatio-net/neatio/chain/accounts"
+	"github.com/neatio-net/neatio/chain/accounts/keystore"
+	"github.com/neatio-net/neatio/utilities/common/hexutil"
+)
+
+func TestVoteRecordStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vote_store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	am := accounts.NewManager(ks)
+	account, err := ks.NewAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loadVoteRecord(am, account.Address); err == nil {
+		t.Fatalf("expected an error before any vote")
+	}
+
+	salt, err := newVoteSalt()
+	if err != nil || len(salt) != 32 {
+		t.Fatalf("bad salt %q, error %v", salt, err)
+	}
+	pubkey := []byte{1, 2, 3}
+	amount := big.NewInt(1000)
+	record := &VoteRecord{
+		Epoch:    5,
+		From:     account.Address,
+		PubKey:   pubkey,
+		Amount:   (*hexutil.Big)(amount),
+		Salt:     salt,
+		VoteHash: calcVoteHash(account.Address, pubkey, amount, salt),
+	}
+	if err := saveVoteRecord(am, record); err != nil {
+		t.Fatalf("save vote record error %v", err)
+	}
+
+	file := filepath.Join(dir, voteStoreDir, strings.ToLower(account.Address.Hex()[2:])+".json")
+	if info, err := os.Stat(file); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("vote record not saved in the keystore directory, %v", err)
+	}
+
+	loaded, err := loadVoteRecord(am, account.Address)
+	if err != nil {
+		t.Fatalf("load vote record error %v", err)
+	}
+	if loaded.Epoch != 5 || loaded.Salt != salt || loaded.Amount.ToInt().Cmp(amount) != 0 || loaded.VoteHash != record.VoteHash {
+		t.Errorf("vote record mismatch, got %+v want %+v", loaded, record)
+	}
+	if calcVoteHash(account.Address, pubkey, amount, "other salt") == record.VoteHash {
+		t.Errorf("vote hash does not depend on the salt")
+	}
+}
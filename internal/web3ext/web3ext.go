@@ -15,6 +15,8 @@ var Modules = map[string]string{
 	"swarmfs":    SWARMFS_JS,
 	"txpool":     TxPool_JS,
 	"istanbul":   Istanbul_JS,
+	"neatcon":    NeatCon_JS,
+	"chain":      Chain_JS,
 }
 
 const Chequebook_JS = `
@@ -412,6 +414,12 @@ web3._extend({
 			call: 'eth_getRawTransactionByHash',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'feeHistory',
+			call: 'eth_feeHistory',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
 		new web3._extend.Method({
 			name: 'getRawTransactionFromBlock',
 			call: function(args) {
@@ -428,6 +436,11 @@ web3._extend({
 		}),
 	],
 	properties: [
+		new web3._extend.Property({
+			name: 'maxPriorityFeePerGas',
+			getter: 'eth_maxPriorityFeePerGas',
+			outputFormatter: web3._extend.utils.toBigNumber
+		}),
 		new web3._extend.Property({
 			name: 'pendingTransactions',
 			getter: 'eth_pendingTransactions',
@@ -477,6 +490,12 @@ web3._extend({
 			call: 'neat_getRawTransactionByHash',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'feeHistory',
+			call: 'neat_feeHistory',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
 		new web3._extend.Method({
 			name: 'getRawTransactionFromBlock',
 			call: function(args) {
@@ -507,16 +526,6 @@ web3._extend({
 			params: 4,
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputAddressFormatter, null, null]
 		}),
-		new web3._extend.Method({
-			name: 'voteNextEpoch',
-			call: 'neat_voteNextEpoch',
-			params: 3
-		}),
-		new web3._extend.Method({
-			name: 'revealVote',
-			call: 'neat_revealVote',
-			params: 6
-		}),
 		new web3._extend.Method({
 			name: 'getCurrentEpochNumber',
 			call: 'neat_getCurrentEpochNumber'
@@ -581,6 +590,30 @@ web3._extend({
 			call: 'neat_unDelegate',
 			params: 4
 		}),
+		new web3._extend.Method({
+			name: 'redelegate',
+			call: 'neat_redelegate',
+			params: 5,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputAddressFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'getPendingRedelegations',
+			call: 'neat_getPendingRedelegations',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'setAutoCompound',
+			call: 'neat_setAutoCompound',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputAddressFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'getAutoCompound',
+			call: 'neat_getAutoCompound',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'register',
 			call: 'neat_register',
@@ -599,6 +632,30 @@ web3._extend({
 			params: 2,
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'getCandidates',
+			call: 'neat_getCandidates',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'unBanned',
+			call: 'neat_unBanned',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'voteNextEpoch',
+			call: 'neat_voteNextEpoch',
+			params: 5,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'revealVote',
+			call: 'neat_revealVote',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+		}),
 		new web3._extend.Method({
 			name: 'getBannedStatus',
 			call: 'neat_getBannedStatus',
@@ -619,6 +676,11 @@ web3._extend({
 		})
 	],
 	properties: [
+		new web3._extend.Property({
+			name: 'maxPriorityFeePerGas',
+			getter: 'neat_maxPriorityFeePerGas',
+			outputFormatter: web3._extend.utils.toBigNumber
+		}),
 		new web3._extend.Property({
 			name: 'pendingTransactions',
 			getter: 'eth_pendingTransactions',
@@ -865,3 +927,123 @@ web3._extend({
 	]
 });
 `
+
+const NeatCon_JS = `
+web3._extend({
+	property: 'neatcon',
+	methods: [
+		new web3._extend.Method({
+			name: 'getValidatorsAtHeight',
+			call: 'neatcon_getValidatorsAtHeight',
+			params: 1,
+			inputFormatter: [web3._extend.utils.toHex]
+		}),
+		new web3._extend.Method({
+			name: 'getEpochHistory',
+			call: 'neatcon_getEpochHistory',
+			params: 2,
+			inputFormatter: [web3._extend.utils.toHex, web3._extend.utils.toHex]
+		}),
+		new web3._extend.Method({
+			name: 'getSigningInfo',
+			call: 'neatcon_getSigningInfo',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.utils.toHex]
+		})
+	]
+});
+`
+
+const Chain_JS = `
+web3._extend({
+	property: 'chain',
+	methods: [
+		new web3._extend.Method({
+			name: 'createSideChain',
+			call: 'chain_createSideChain',
+			params: 7,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.utils.toHex, web3._extend.utils.toHex, web3._extend.utils.toHex, web3._extend.utils.toHex, null]
+		}),
+		new web3._extend.Method({
+			name: 'joinSideChain',
+			call: 'chain_joinSideChain',
+			params: 6,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null, web3._extend.utils.toHex, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'decommissionSideChain',
+			call: 'chain_decommissionSideChain',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'getCrossChainMessageProof',
+			call: 'chain_getCrossChainMessageProof',
+			params: 2,
+			inputFormatter: [null, web3._extend.utils.toHex]
+		}),
+		new web3._extend.Method({
+			name: 'deliverCrossChainMessage',
+			call: 'chain_deliverCrossChainMessage',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.utils.toHex, null]
+		}),
+		new web3._extend.Method({
+			name: 'transferToSideChain',
+			call: 'chain_transferToSideChain',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.utils.toHex, null]
+		}),
+		new web3._extend.Method({
+			name: 'settleTransferToSideChain',
+			call: 'chain_settleTransferToSideChain',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'claimTransferToSideChain',
+			call: 'chain_claimTransferToSideChain',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'listSideChains',
+			call: 'chain_listSideChains',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getSideChain',
+			call: 'chain_getSideChain',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getPendingSideChains',
+			call: 'chain_getPendingSideChains',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'setBlockReward',
+			call: 'chain_setBlockReward',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.utils.toHex, null]
+		}),
+		new web3._extend.Method({
+			name: 'getRewardPool',
+			call: 'chain_getRewardPool',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getRelayData',
+			call: 'chain_getRelayData',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'broadcastTX3ProofData',
+			call: 'chain_broadcastTX3ProofData',
+			params: 1
+		})
+	]
+});
+`
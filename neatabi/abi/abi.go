@@ -16,14 +16,17 @@ type FunctionType struct {
 }
 
 var (
-	CreateSideChain       = FunctionType{0, true, true, false}
-	JoinSideChain         = FunctionType{1, true, true, false}
-	DepositInMainChain    = FunctionType{2, true, true, false}
-	DepositInSideChain    = FunctionType{3, true, false, true}
-	WithdrawFromSideChain = FunctionType{4, true, false, true}
-	WithdrawFromMainChain = FunctionType{5, true, true, false}
-	SaveDataToMainChain   = FunctionType{6, true, true, false}
-	SetBlockReward        = FunctionType{7, true, false, true}
+	CreateSideChain          = FunctionType{0, true, true, false}
+	JoinSideChain            = FunctionType{1, true, true, false}
+	DepositInMainChain       = FunctionType{2, true, true, false}
+	DepositInSideChain       = FunctionType{3, true, false, true}
+	WithdrawFromSideChain    = FunctionType{4, true, false, true}
+	WithdrawFromMainChain    = FunctionType{5, true, true, false}
+	SaveDataToMainChain      = FunctionType{6, true, true, false}
+	SetBlockReward           = FunctionType{7, false, false, true}
+	DecommissionSideChain    = FunctionType{8, true, true, false}
+	DeliverCrossChainMessage = FunctionType{9, true, true, true}
+	TransferToSideChain      = FunctionType{24, true, true, true}
 
 	VoteNextEpoch  = FunctionType{10, false, true, true}
 	RevealVote     = FunctionType{11, false, true, true}
@@ -37,6 +40,10 @@ var (
 	SetCommission  = FunctionType{19, false, true, true}
 	SetAddress     = FunctionType{20, false, true, true}
 
+	ReportDoubleSign = FunctionType{21, false, true, true}
+	Redelegate       = FunctionType{22, false, true, true}
+	SetAutoCompound  = FunctionType{23, false, true, true}
+
 	Unknown = FunctionType{-1, false, false, false}
 )
 
@@ -68,11 +75,17 @@ func (t FunctionType) RequiredGas() uint64 {
 		return 0
 	case SaveDataToMainChain:
 		return 0
+	case DecommissionSideChain:
+		return 42000
+	case DeliverCrossChainMessage:
+		return 42000
+	case TransferToSideChain:
+		return 42000
 	case VoteNextEpoch:
 		return 21000
 	case RevealVote:
 		return 21000
-	case Delegate, UnDelegate, Register, UnRegister:
+	case Delegate, UnDelegate, Redelegate, Register, UnRegister:
 		return 21000
 	case SetBlockReward:
 		return 21000
@@ -86,6 +99,10 @@ func (t FunctionType) RequiredGas() uint64 {
 		return 21000
 	case SetAddress:
 		return 21000
+	case SetAutoCompound:
+		return 21000
+	case ReportDoubleSign:
+		return 42000
 	default:
 		return 0
 	}
@@ -107,6 +124,12 @@ func (t FunctionType) String() string {
 		return "WithdrawFromMainChain"
 	case SaveDataToMainChain:
 		return "SaveDataToMainChain"
+	case DecommissionSideChain:
+		return "DecommissionSideChain"
+	case DeliverCrossChainMessage:
+		return "DeliverCrossChainMessage"
+	case TransferToSideChain:
+		return "TransferToSideChain"
 	case VoteNextEpoch:
 		return "VoteNextEpoch"
 	case RevealVote:
@@ -131,6 +154,12 @@ func (t FunctionType) String() string {
 		return "SetCommission"
 	case SetAddress:
 		return "SetAddress"
+	case ReportDoubleSign:
+		return "ReportDoubleSign"
+	case Redelegate:
+		return "Redelegate"
+	case SetAutoCompound:
+		return "SetAutoCompound"
 	default:
 		return "UnKnown"
 	}
@@ -152,6 +181,12 @@ func StringToFunctionType(s string) FunctionType {
 		return WithdrawFromMainChain
 	case "SaveDataToMainChain":
 		return SaveDataToMainChain
+	case "DecommissionSideChain":
+		return DecommissionSideChain
+	case "DeliverCrossChainMessage":
+		return DeliverCrossChainMessage
+	case "TransferToSideChain":
+		return TransferToSideChain
 	case "VoteNextEpoch":
 		return VoteNextEpoch
 	case "RevealVote":
@@ -176,6 +211,12 @@ func StringToFunctionType(s string) FunctionType {
 		return SetCommission
 	case "SetAddress":
 		return SetAddress
+	case "ReportDoubleSign":
+		return ReportDoubleSign
+	case "Redelegate":
+		return Redelegate
+	case "SetAutoCompound":
+		return SetAutoCompound
 	default:
 		return Unknown
 	}
@@ -195,6 +236,36 @@ type JoinSideChainArgs struct {
 	Signature []byte
 }
 
+type SaveDataToMainChainArgs struct {
+	Data []byte
+}
+
+type DecommissionSideChainArgs struct {
+	ChainId   string
+	ProofData []byte
+}
+
+type DeliverCrossChainMessageArgs struct {
+	ProofData []byte
+}
+
+// TransferToSideChainArgs burns the value on the source side chain, moves it
+// on the main chain with the tx3 proof data of the burn, and credits it on the
+// target side chain with the hash of the main chain tx
+type TransferToSideChainArgs struct {
+	ToChainId string
+	TxHash    common.Hash
+	ProofData []byte
+}
+
+// CrossChainMessageArgs is the data of the CrossChainMessage event emitted by
+// the contract sending the message
+type CrossChainMessageArgs struct {
+	ToChainId string
+	Target    common.Address
+	Payload   []byte
+}
+
 type DepositInMainChainArgs struct {
 	ChainId string
 }
@@ -234,6 +305,17 @@ type UnDelegateArgs struct {
 	Amount    *big.Int
 }
 
+type RedelegateArgs struct {
+	From   common.Address
+	To     common.Address
+	Amount *big.Int
+}
+
+type SetAutoCompoundArgs struct {
+	Candidate common.Address
+	Enable    bool
+}
+
 type RegisterArgs struct {
 	Pubkey     []byte
 	Signature  []byte
@@ -268,6 +350,19 @@ type SetAddressArgs struct {
 	FAddress common.Address
 }
 
+type ReportDoubleSignArgs struct {
+	Evidence []byte
+}
+
+type GetDelegationArgs struct {
+	Delegator common.Address
+	Candidate common.Address
+}
+
+type GetCandidateArgs struct {
+	Candidate common.Address
+}
+
 const jsonChainABI = `
 [
 	{
@@ -383,6 +478,69 @@ const jsonChainABI = `
 			}
 		]
 	},
+	{
+		"type": "function",
+		"name": "DeliverCrossChainMessage",
+		"constant": false,
+		"inputs": [
+			{
+				"name": "proofData",
+				"type": "bytes"
+			}
+		]
+	},
+	{
+		"type": "function",
+		"name": "TransferToSideChain",
+		"constant": false,
+		"inputs": [
+			{
+				"name": "toChainId",
+				"type": "string"
+			},
+			{
+				"name": "txHash",
+				"type": "bytes32"
+			},
+			{
+				"name": "proofData",
+				"type": "bytes"
+			}
+		]
+	},
+	{
+		"type": "event",
+		"name": "CrossChainMessage",
+		"inputs": [
+			{
+				"name": "toChainId",
+				"type": "string"
+			},
+			{
+				"name": "target",
+				"type": "address"
+			},
+			{
+				"name": "payload",
+				"type": "bytes"
+			}
+		]
+	},
+	{
+		"type": "function",
+		"name": "DecommissionSideChain",
+		"constant": false,
+		"inputs": [
+			{
+				"name": "chainId",
+				"type": "string"
+			},
+			{
+				"name": "proofData",
+				"type": "bytes"
+			}
+		]
+	},
 	{
 		"type": "function",
 		"name": "VoteNextEpoch",
@@ -548,21 +706,219 @@ const jsonChainABI = `
 				"type": "address"
 			}
 		]
+	},
+	{
+		"type": "function",
+		"name": "ReportDoubleSign",
+		"constant": false,
+		"inputs": [
+			{
+				"name": "evidence",
+				"type": "bytes"
+			}
+		]
+	},
+	{
+		"type": "function",
+		"name": "Redelegate",
+		"constant": false,
+		"inputs": [
+			{
+				"name": "from",
+				"type": "address"
+			},
+			{
+				"name": "to",
+				"type": "address"
+			},
+			{
+				"name": "amount",
+				"type": "uint256"
+			}
+		]
+	},
+	{
+		"type": "function",
+		"name": "SetAutoCompound",
+		"constant": false,
+		"inputs": [
+			{
+				"name": "candidate",
+				"type": "address"
+			},
+			{
+				"name": "enable",
+				"type": "bool"
+			}
+		]
+	}
+]`
+
+// the target contract of a cross chain message implements this method, it is
+// called from NeatioSmartContractAddress when the message is delivered
+const jsonCrossChainReceiverABI = `
+[
+	{
+		"type": "function",
+		"name": "receiveCrossChainMessage",
+		"constant": false,
+		"inputs": [
+			{
+				"name": "fromChainId",
+				"type": "string"
+			},
+			{
+				"name": "sender",
+				"type": "address"
+			},
+			{
+				"name": "payload",
+				"type": "bytes"
+			}
+		]
+	}
+]`
+
+// the staking precompile, a contract calling it delegates, undelegates and
+// withdraws rewards as the delegator
+const jsonStakingABI = `
+[
+	{
+		"type": "function",
+		"name": "delegate",
+		"stateMutability": "payable",
+		"inputs": [
+			{
+				"name": "candidate",
+				"type": "address"
+			}
+		],
+		"outputs": []
+	},
+	{
+		"type": "function",
+		"name": "undelegate",
+		"stateMutability": "nonpayable",
+		"inputs": [
+			{
+				"name": "candidate",
+				"type": "address"
+			},
+			{
+				"name": "amount",
+				"type": "uint256"
+			}
+		],
+		"outputs": []
+	},
+	{
+		"type": "function",
+		"name": "withdrawReward",
+		"stateMutability": "nonpayable",
+		"inputs": [
+			{
+				"name": "delegateAddress",
+				"type": "address"
+			},
+			{
+				"name": "amount",
+				"type": "uint256"
+			}
+		],
+		"outputs": []
+	},
+	{
+		"type": "function",
+		"name": "getDelegation",
+		"stateMutability": "view",
+		"inputs": [
+			{
+				"name": "delegator",
+				"type": "address"
+			},
+			{
+				"name": "candidate",
+				"type": "address"
+			}
+		],
+		"outputs": [
+			{
+				"name": "proxiedBalance",
+				"type": "uint256"
+			},
+			{
+				"name": "depositProxiedBalance",
+				"type": "uint256"
+			},
+			{
+				"name": "pendingRefundBalance",
+				"type": "uint256"
+			},
+			{
+				"name": "rewardBalance",
+				"type": "uint256"
+			}
+		]
+	},
+	{
+		"type": "function",
+		"name": "getCandidate",
+		"stateMutability": "view",
+		"inputs": [
+			{
+				"name": "candidate",
+				"type": "address"
+			}
+		],
+		"outputs": [
+			{
+				"name": "candidate",
+				"type": "bool"
+			},
+			{
+				"name": "commission",
+				"type": "uint8"
+			},
+			{
+				"name": "proxiedBalance",
+				"type": "uint256"
+			},
+			{
+				"name": "depositProxiedBalance",
+				"type": "uint256"
+			}
+		]
 	}
 ]`
 
 var NeatioSideChainsAddress = common.HexToAddress("0x0000000000000000000000000000000000001010")
 
+// SideChainOwnerKey is the storage slot of NeatioSideChainsAddress holding the
+// owner of the side chain, set in the side chain genesis
+var SideChainOwnerKey = common.BytesToHash([]byte("SideChainOwner"))
+
 var NeatioSmartContractAddress = common.HexToAddress("0x0000000000000000000000000000000000001001")
 
 var ChainABI abi.ABI
 
+var CrossChainReceiverABI abi.ABI
+
+var StakingABI abi.ABI
+
 func init() {
 	var err error
 	ChainABI, err = abi.JSON(strings.NewReader(jsonChainABI))
 	if err != nil {
 		panic("fail to create the chain ABI: " + err.Error())
 	}
+	CrossChainReceiverABI, err = abi.JSON(strings.NewReader(jsonCrossChainReceiverABI))
+	if err != nil {
+		panic("fail to create the cross chain receiver ABI: " + err.Error())
+	}
+	StakingABI, err = abi.JSON(strings.NewReader(jsonStakingABI))
+	if err != nil {
+		panic("fail to create the staking ABI: " + err.Error())
+	}
 }
 
 func IsNeatChainContractAddr(addr *common.Address) bool {
@@ -12,7 +12,6 @@ import (
 	"github.com/neatio-net/neatio/network/rpc"
 	"github.com/neatio-net/neatio/utilities/common"
 	"github.com/neatio-net/neatio/utilities/common/hexutil"
-	"github.com/neatio-net/neatio/utilities/rlp"
 )
 
 type Client struct {
@@ -31,6 +30,10 @@ func NewClient(c *rpc.Client) *Client {
 	return &Client{c}
 }
 
+func (ec *Client) Close() {
+	ec.c.Close()
+}
+
 func (ec *Client) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
 	return ec.getBlock(ctx, "eth_getBlockByHash", hash, true)
 }
@@ -376,7 +379,7 @@ func (ec *Client) EstimateGas(ctx context.Context, msg neatio.CallMsg) (uint64,
 
 func (ec *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
 	fmt.Printf("transaction args Client tx %v\n", tx)
-	data, err := rlp.EncodeToBytes(tx)
+	data, err := tx.MarshalBinary()
 	if err != nil {
 		return err
 	}
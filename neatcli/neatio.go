@@ -7,6 +7,7 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/neatio-net/neatio"
 	"github.com/neatio-net/neatio/chain/core/types"
 	"github.com/neatio-net/neatio/chain/log"
 	neatAbi "github.com/neatio-net/neatio/neatabi/abi"
@@ -29,21 +30,30 @@ func (ec *Client) BlockNumber(ctx context.Context) (*big.Int, error) {
 }
 
 func (ec *Client) SendDataToMainChain(ctx context.Context, data []byte, prv *ecdsa.PrivateKey, mainChainId string) (common.Hash, error) {
+	signTx := func(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+		return types.SignTx(tx, types.NewEIP155Signer(chainID), prv)
+	}
+	return ec.SendDataToMainChainBySigner(ctx, data, crypto.PubkeyToAddress(prv.PublicKey), signTx, mainChainId)
+}
+
+// SignTxFn signs the transaction for the chain id
+type SignTxFn func(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+
+// SendDataToMainChainBySigner sends the data from the account, the signing is
+// left to signTx for keys which are not held in the process
+func (ec *Client) SendDataToMainChainBySigner(ctx context.Context, data []byte, account common.Address, signTx SignTxFn, mainChainId string) (common.Hash, error) {
 
 	bs, err := neatAbi.ChainABI.Pack(neatAbi.SaveDataToMainChain.String(), data)
 	if err != nil {
 		return common.Hash{}, err
 	}
 
-	account := crypto.PubkeyToAddress(prv.PublicKey)
-
 	nonce, err := ec.NonceAt(ctx, account, nil)
 	if err != nil {
 		return common.Hash{}, err
 	}
 
-	digest := crypto.Keccak256([]byte(mainChainId))
-	signer := types.NewEIP155Signer(new(big.Int).SetBytes(digest[:]))
+	chainID := params.EIP155ChainId(mainChainId)
 
 	var hash = common.Hash{}
 
@@ -58,7 +68,7 @@ func (ec *Client) SendDataToMainChain(ctx context.Context, data []byte, prv *ecd
 
 		tx := types.NewTransaction(nonce, neatAbi.NeatioSmartContractAddress, nil, 0, gasPrice, bs)
 
-		signedTx, err := types.SignTx(tx, signer, prv)
+		signedTx, err := signTx(tx, chainID)
 		if err != nil {
 			return err
 		}
@@ -93,6 +103,90 @@ func (ec *Client) BroadcastDataToMainChain(ctx context.Context, chainId string,
 	return err
 }
 
+// Redelegate moves the delegation of the account from one candidate to
+// another, the account has to be unlocked on the node
+func (ec *Client) Redelegate(ctx context.Context, from, fromCandidate, toCandidate common.Address, amount *big.Int) (common.Hash, error) {
+	var hash common.Hash
+	err := ec.c.CallContext(ctx, &hash, "neat_redelegate", from, fromCandidate, toCandidate, (*hexutil.Big)(amount), nil)
+	return hash, err
+}
+
+type Redelegation struct {
+	From   common.Address `json:"from"`
+	To     common.Address `json:"to"`
+	Amount *hexutil.Big   `json:"amount"`
+}
+
+// PendingRedelegations returns the redelegations of the delegator waiting for the next epoch
+func (ec *Client) PendingRedelegations(ctx context.Context, delegator common.Address, blockNumber *big.Int) ([]*Redelegation, error) {
+	var redelegations []*Redelegation
+	err := ec.c.CallContext(ctx, &redelegations, "neat_getPendingRedelegations", delegator, toBlockNumArg(blockNumber))
+	return redelegations, err
+}
+
+func (ec *Client) SetAutoCompound(ctx context.Context, from, candidate common.Address, enable bool) (common.Hash, error) {
+	var hash common.Hash
+	err := ec.c.CallContext(ctx, &hash, "neat_setAutoCompound", from, candidate, enable, nil)
+	return hash, err
+}
+
+// AutoCompound returns the candidates the delegator compounds the rewards to
+func (ec *Client) AutoCompound(ctx context.Context, delegator common.Address, blockNumber *big.Int) ([]common.Address, error) {
+	var candidates []common.Address
+	err := ec.c.CallContext(ctx, &candidates, "neat_getAutoCompound", delegator, toBlockNumArg(blockNumber))
+	return candidates, err
+}
+
+type RelayData struct {
+	Number       hexutil.Uint64 `json:"number"`
+	Hash         common.Hash    `json:"hash"`
+	ProofData    hexutil.Bytes  `json:"proofData"`
+	TX3ProofData hexutil.Bytes  `json:"tx3ProofData"`
+}
+
+// RelayData returns what the side chain block sends to the main chain
+func (ec *Client) RelayData(ctx context.Context, number *big.Int) (*RelayData, error) {
+	var data *RelayData
+	err := ec.c.CallContext(ctx, &data, "chain_getRelayData", toBlockNumArg(number))
+	if err == nil && data == nil {
+		err = neatio.NotFound
+	}
+	return data, err
+}
+
+// CrossChainMessageProof returns the proof data of the cross chain message
+// emitted in the log of the tx, ready to be delivered on the target chain
+func (ec *Client) CrossChainMessageProof(ctx context.Context, txHash common.Hash, logIndex uint) ([]byte, error) {
+	var proofData hexutil.Bytes
+	err := ec.c.CallContext(ctx, &proofData, "chain_getCrossChainMessageProof", txHash, hexutil.Uint(logIndex))
+	return proofData, err
+}
+
+// DeliverCrossChainMessage sends the proof data to the target chain of the
+// message, the account has to be unlocked on the node
+func (ec *Client) DeliverCrossChainMessage(ctx context.Context, from common.Address, proofData []byte, gas uint64) (common.Hash, error) {
+	var hash common.Hash
+	err := ec.c.CallContext(ctx, &hash, "chain_deliverCrossChainMessage", from, hexutil.Bytes(proofData), hexutil.Uint64(gas), nil)
+	return hash, err
+}
+
+type SideChain struct {
+	ChainId string         `json:"chainId"`
+	Status  string         `json:"status"`
+	Owner   common.Address `json:"owner"`
+}
+
+// SideChain returns the side chain in the registry of the main chain node, the
+// status is pending, running or decommissioned
+func (ec *Client) SideChain(ctx context.Context, chainId string) (*SideChain, error) {
+	var info *SideChain
+	err := ec.c.CallContext(ctx, &info, "chain_getSideChain", chainId)
+	if err == nil && info == nil {
+		err = neatio.NotFound
+	}
+	return info, err
+}
+
 func retry(attemps int, sleep time.Duration, fn func() error) error {
 
 	if err := fn(); err != nil {
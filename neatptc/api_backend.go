@@ -111,6 +111,9 @@ func (b *EthApiBackend) GetEVM(ctx context.Context, msg core.Message, state *sta
 	vmError := func() error { return nil }
 
 	context := core.NewEVMContext(msg, header, b.eth.BlockChain(), nil)
+	if staking := core.NewStakingHandler(state, b.eth.BlockChain()); staking != nil {
+		context.Staking = staking
+	}
 	return vm.NewEVM(context, state, b.eth.chainConfig, vmCfg), vmError, nil
 }
 
@@ -130,6 +133,14 @@ func (b *EthApiBackend) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) e
 	return b.eth.BlockChain().SubscribeChainSideEvent(ch)
 }
 
+func (b *EthApiBackend) SubscribeCreateSideChainEvent(ch chan<- core.CreateSideChainEvent) event.Subscription {
+	return b.eth.BlockChain().SubscribeCreateSideChainEvent(ch)
+}
+
+func (b *EthApiBackend) SubscribeDecommissionSideChainEvent(ch chan<- core.DecommissionSideChainEvent) event.Subscription {
+	return b.eth.BlockChain().SubscribeDecommissionSideChainEvent(ch)
+}
+
 func (b *EthApiBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription {
 	return b.eth.BlockChain().SubscribeLogsEvent(ch)
 }
@@ -182,6 +193,14 @@ func (b *EthApiBackend) SuggestPrice(ctx context.Context) (*big.Int, error) {
 	return b.gpo.SuggestPrice(ctx)
 }
 
+func (b *EthApiBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return b.gpo.SuggestTipCap(ctx)
+}
+
+func (b *EthApiBackend) FeeHistory(ctx context.Context, blockCount int, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error) {
+	return b.gpo.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+}
+
 func (b *EthApiBackend) ChainDb() neatdb.Database {
 	return b.eth.ChainDb()
 }
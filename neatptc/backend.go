@@ -63,6 +63,8 @@ type NeatIO struct {
 	bloomRequests chan chan *bloombits.Retrieval
 	bloomIndexer  *core.ChainIndexer
 
+	signingIndexer *core.ChainIndexer
+
 	ApiBackend *EthApiBackend
 
 	miner    *miner.Miner
@@ -73,6 +75,8 @@ type NeatIO struct {
 	networkId     uint64
 	netRPCService *neatapi.PublicNetAPI
 
+	voteRevealer *neatapi.VoteRevealer
+
 	lock sync.RWMutex
 }
 
@@ -160,10 +164,21 @@ func New(ctx *node.ServiceContext, config *Config, cliCtx *cli.Context,
 	}
 	neatChain.bloomIndexer.Start(neatChain.blockchain)
 
+	neatChain.signingIndexer = ntcBackend.NewSigningIndexer(chainDb, neatChain.engine)
+	neatChain.signingIndexer.Start(neatChain.blockchain)
+	if ntc, ok := neatChain.engine.(interface {
+		SetSigningIndexer(neatdb.Database, *core.ChainIndexer)
+	}); ok {
+		ntc.SetSigningIndexer(chainDb, neatChain.signingIndexer)
+	}
+
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = ctx.ResolvePath(config.TxPool.Journal)
 	}
 	neatChain.txPool = core.NewTxPool(config.TxPool, neatChain.chainConfig, neatChain.blockchain, cch)
+	if ntc, ok := neatChain.engine.(interface{ SetTxPool(*core.TxPool) }); ok {
+		ntc.SetTxPool(neatChain.txPool)
+	}
 
 	if neatChain.protocolManager, err = NewProtocolManager(neatChain.chainConfig, config.SyncMode, config.NetworkId, neatChain.eventMux, neatChain.txPool, neatChain.engine, neatChain.blockchain, chainDb, cch); err != nil {
 		return nil, err
@@ -389,6 +404,11 @@ func (s *NeatIO) Start(srvr *p2p.Server) error {
 
 	go s.loopForMiningEvent()
 
+	if s.config.AutoRevealVote {
+		s.voteRevealer = neatapi.NewVoteRevealer(s.ApiBackend)
+		s.voteRevealer.Start()
+	}
+
 	if s.config.PruneStateData && s.chainConfig.NeatChainId == "side_0" {
 		go s.StartScanAndPrune(0)
 	}
@@ -397,7 +417,11 @@ func (s *NeatIO) Start(srvr *p2p.Server) error {
 }
 
 func (s *NeatIO) Stop() error {
+	if s.voteRevealer != nil {
+		s.voteRevealer.Stop()
+	}
 	s.bloomIndexer.Close()
+	s.signingIndexer.Close()
 	s.blockchain.Stop()
 	s.protocolManager.Stop()
 	s.txPool.Stop()
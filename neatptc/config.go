@@ -87,6 +87,8 @@ type Config struct {
 
 	PruneStateData bool
 	PruneBlockData bool
+
+	AutoRevealVote bool
 }
 
 type configMarshaling struct {
@@ -0,0 +1,145 @@
+package gasprice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/neatio-net/neatio/chain/consensus/misc"
+	"github.com/neatio-net/neatio/chain/core/types"
+	"github.com/neatio-net/neatio/network/rpc"
+)
+
+// maxFeeHistory is the maximum number of blocks returned by a fee history query
+const maxFeeHistory = 1024
+
+var (
+	errInvalidPercentile = errors.New("invalid reward percentile")
+	errRequestBeyondHead = errors.New("request beyond head block")
+	errMissingBlock      = errors.New("missing block")
+)
+
+type txGasAndReward struct {
+	gasUsed uint64
+	reward  *big.Int
+}
+
+type sortGasAndReward []txGasAndReward
+
+func (s sortGasAndReward) Len() int           { return len(s) }
+func (s sortGasAndReward) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s sortGasAndReward) Less(i, j int) bool { return s[i].reward.Cmp(s[j].reward) < 0 }
+
+// FeeHistory returns the base fees, gas used ratios and the tips at the given
+// percentiles of the gas used for a range of blocks ending with lastBlock. The
+// base fees include the one of the block following the range. Blocks before
+// the London fork report a zero base fee.
+func (gpo *Oracle) FeeHistory(ctx context.Context, blocks int, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error) {
+	if blocks < 1 {
+		return new(big.Int), nil, nil, nil, nil
+	}
+	if blocks > maxFeeHistory {
+		blocks = maxFeeHistory
+	}
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 {
+			return new(big.Int), nil, nil, nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
+		}
+		if i > 0 && p < rewardPercentiles[i-1] {
+			return new(big.Int), nil, nil, nil, fmt.Errorf("%w: #%d:%f > #%d:%f", errInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
+		}
+	}
+
+	head, err := gpo.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if head == nil {
+		return new(big.Int), nil, nil, nil, err
+	}
+	last := head.Number.Uint64()
+	if lastBlock >= 0 {
+		if uint64(lastBlock) > last {
+			return new(big.Int), nil, nil, nil, fmt.Errorf("%w: requested %d, head %d", errRequestBeyondHead, lastBlock, last)
+		}
+		last = uint64(lastBlock)
+	}
+	if uint64(blocks) > last+1 {
+		blocks = int(last + 1)
+	}
+	oldest := last + 1 - uint64(blocks)
+
+	var (
+		reward       [][]*big.Int
+		baseFee      = make([]*big.Int, blocks+1)
+		gasUsedRatio = make([]float64, blocks)
+		config       = gpo.backend.ChainConfig()
+	)
+	if len(rewardPercentiles) != 0 {
+		reward = make([][]*big.Int, blocks)
+	}
+	for i := 0; i < blocks; i++ {
+		block, err := gpo.backend.BlockByNumber(ctx, rpc.BlockNumber(oldest+uint64(i)))
+		if block == nil {
+			if err == nil {
+				err = errMissingBlock
+			}
+			return new(big.Int), nil, nil, nil, err
+		}
+		header := block.Header()
+
+		if baseFee[i] = header.BaseFee; baseFee[i] == nil {
+			baseFee[i] = new(big.Int)
+		}
+		if i == blocks-1 {
+			if next := new(big.Int).Add(header.Number, big.NewInt(1)); config.IsLondon(next) {
+				baseFee[i+1] = misc.CalcBaseFee(config, header)
+			} else {
+				baseFee[i+1] = new(big.Int)
+			}
+		}
+		if header.GasLimit > 0 {
+			gasUsedRatio[i] = float64(header.GasUsed) / float64(header.GasLimit)
+		}
+
+		if len(rewardPercentiles) != 0 {
+			receipts, err := gpo.backend.GetReceipts(ctx, block.Hash())
+			if err != nil {
+				return new(big.Int), nil, nil, nil, err
+			}
+			reward[i] = blockRewards(block, receipts, rewardPercentiles)
+		}
+	}
+	return new(big.Int).SetUint64(oldest), reward, baseFee, gasUsedRatio, nil
+}
+
+// blockRewards returns the effective tips paid at the given percentiles of the
+// gas used by the block, the transactions are weighted by their gas used.
+func blockRewards(block *types.Block, receipts types.Receipts, percentiles []float64) []*big.Int {
+	rewards := make([]*big.Int, len(percentiles))
+	txs := block.Transactions()
+	if len(txs) == 0 || len(receipts) != len(txs) {
+		for i := range rewards {
+			rewards[i] = new(big.Int)
+		}
+		return rewards
+	}
+
+	sorter := make(sortGasAndReward, len(txs))
+	for i, tx := range txs {
+		tip, _ := tx.EffectiveGasTip(block.BaseFee())
+		sorter[i] = txGasAndReward{gasUsed: receipts[i].GasUsed, reward: tip}
+	}
+	sort.Stable(sorter)
+
+	var txIndex int
+	sumGasUsed := sorter[0].gasUsed
+	for i, p := range percentiles {
+		thresholdGasUsed := uint64(float64(block.GasUsed()) * p / 100)
+		for sumGasUsed < thresholdGasUsed && txIndex < len(txs)-1 {
+			txIndex++
+			sumGasUsed += sorter[txIndex].gasUsed
+		}
+		rewards[i] = sorter[txIndex].reward
+	}
+	return rewards
+}
@@ -166,6 +166,45 @@ func (n *Node) startRPC1(services map[reflect.Type]Service) error {
 	return nil
 }
 
+// Stop1 stops the services of the node started by Start1, the p2p server is
+// shared with the other chains and keeps running
+func (n *Node) Stop1() error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.server == nil {
+		return ErrNodeStopped
+	}
+
+	n.stopIPC()
+	n.stopInProc()
+	n.rpcAPIs = nil
+	failure := &StopError{
+		Services: make(map[reflect.Type]error),
+	}
+	for kind, service := range n.services {
+		if err := service.Stop(); err != nil {
+			failure.Services[kind] = err
+		}
+	}
+	n.services = nil
+	n.server = nil
+
+	if n.instanceDirLock != nil {
+		if err := n.instanceDirLock.Release(); err != nil {
+			n.log.Error("Can't release datadir lock", "err", err)
+		}
+		n.instanceDirLock = nil
+	}
+
+	close(n.stop)
+
+	if len(failure.Services) > 0 {
+		return failure
+	}
+	return nil
+}
+
 func (n *Node) GetLogger() log.Logger {
 	return n.log
 }
@@ -435,6 +435,23 @@ func (srv *Server) AddChildProtocolCaps(sideProtocols []Protocol) {
 	}
 }
 
+func (srv *Server) RemoveChildProtocolCaps(sideProtocols []Protocol) {
+	for _, p := range sideProtocols {
+		for i, c := range srv.ourHandshake.Caps {
+			if c == p.cap() {
+				srv.ourHandshake.Caps = append(srv.ourHandshake.Caps[:i], srv.ourHandshake.Caps[i+1:]...)
+				break
+			}
+		}
+		for i, proto := range srv.Protocols {
+			if proto.cap() == p.cap() {
+				srv.Protocols = append(srv.Protocols[:i], srv.Protocols[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
 func (srv *Server) startListening() error {
 
 	listener, err := net.Listen("tcp", srv.ListenAddr)
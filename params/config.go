@@ -52,7 +52,7 @@ var (
 		},
 	}
 
-	TestChainConfig = &ChainConfig{"", big.NewInt(1), big.NewInt(0), big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil}
+	TestChainConfig = &ChainConfig{"", big.NewInt(1), big.NewInt(0), big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, "", nil, big.NewInt(0), nil, nil}
 	TestRules       = TestChainConfig.Rules(new(big.Int))
 )
 
@@ -77,11 +77,40 @@ type ChainConfig struct {
 	PetersburgBlock     *big.Int `json:"petersburgBlock,omitempty"`
 	IstanbulBlock       *big.Int `json:"istanbulBlock,omitempty"`
 
+	// BerlinBlock activates the typed transaction envelopes (EIP-2718), the
+	// access list transactions (EIP-2930) and the warm/cold state access
+	// gas accounting (EIP-2929)
+	BerlinBlock *big.Int `json:"berlinBlock,omitempty"`
+
+	// LondonBlock activates the base fee and the dynamic fee transactions
+	// (EIP-1559), the BASEFEE opcode (EIP-3198), the reduced gas refunds
+	// (EIP-3529) and the rejection of new code starting with 0xEF (EIP-3541).
+	// The collected base fee goes to BaseFeeDestination
+	LondonBlock        *big.Int `json:"londonBlock,omitempty"`
+	BaseFeeDestination string   `json:"baseFeeDestination,omitempty"`
+
+	// ShanghaiBlock activates the PUSH0 opcode (EIP-3855) and the limit and
+	// metering of the init code (EIP-3860)
+	ShanghaiBlock *big.Int `json:"shanghaiBlock,omitempty"`
+
+	// NeatForkBlock activates the state changes of the staking, validator and
+	// side chain transactions added on top of the launched protocol and the
+	// cross chain and staking precompiles. Blocks before the fork are executed
+	// with the original rules
+	NeatForkBlock *big.Int `json:"neatForkBlock,omitempty"`
+
 	NeatCon *NeatConConfig `json:"neatcon,omitempty"`
 
 	ChainLogger log.Logger `json:"-"`
 }
 
+// The destinations of the base fee collected after the London fork
+const (
+	BaseFeeBurn         = "burn"
+	BaseFeeToFoundation = "foundation"
+	BaseFeeToRewardPool = "rewardpool"
+)
+
 type NeatConConfig struct {
 	Epoch          uint64 `json:"epoch"`
 	ProposerPolicy uint64 `json:"policy"`
@@ -103,6 +132,7 @@ func NewSideChainConfig(sideChainID string) *ChainConfig {
 		ConstantinopleBlock: big.NewInt(0),
 		PetersburgBlock:     big.NewInt(0),
 		IstanbulBlock:       big.NewInt(0),
+		NeatForkBlock:       big.NewInt(0),
 		NeatCon: &NeatConConfig{
 			Epoch:          30000,
 			ProposerPolicy: 0,
@@ -123,7 +153,7 @@ func (c *ChainConfig) String() string {
 	default:
 		engine = "unknown"
 	}
-	return fmt.Sprintf("{NeatChainId: %s ChainID: %v Homestead: %v  EIP150: %v EIP155: %v EIP158: %v Byzantium: %v Constantinople: %v Petersburg: %v Istanbul: %v Engine: %v}",
+	return fmt.Sprintf("{NeatChainId: %s ChainID: %v Homestead: %v  EIP150: %v EIP155: %v EIP158: %v Byzantium: %v Constantinople: %v Petersburg: %v Istanbul: %v Berlin: %v London: %v Shanghai: %v NeatFork: %v Engine: %v}",
 		c.NeatChainId,
 		c.ChainId,
 		c.HomesteadBlock,
@@ -134,6 +164,10 @@ func (c *ChainConfig) String() string {
 		c.ConstantinopleBlock,
 		c.PetersburgBlock,
 		c.IstanbulBlock,
+		c.BerlinBlock,
+		c.LondonBlock,
+		c.ShanghaiBlock,
+		c.NeatForkBlock,
 		engine,
 	)
 }
@@ -170,6 +204,22 @@ func (c *ChainConfig) IsIstanbul(num *big.Int) bool {
 	return isForked(c.IstanbulBlock, num)
 }
 
+func (c *ChainConfig) IsBerlin(num *big.Int) bool {
+	return isForked(c.BerlinBlock, num)
+}
+
+func (c *ChainConfig) IsLondon(num *big.Int) bool {
+	return isForked(c.LondonBlock, num)
+}
+
+func (c *ChainConfig) IsShanghai(num *big.Int) bool {
+	return isForked(c.ShanghaiBlock, num)
+}
+
+func (c *ChainConfig) IsNeatFork(num *big.Int) bool {
+	return isForked(c.NeatForkBlock, num)
+}
+
 func (c *ChainConfig) IsEWASM(num *big.Int) bool {
 	return false
 }
@@ -182,6 +232,19 @@ func IsMainChain(chainId string) bool {
 	return chainId == MainnetChainConfig.NeatChainId || chainId == TestnetChainConfig.NeatChainId
 }
 
+// EIP155ChainId returns the chain id signing the transactions of the chain,
+// the main chains keep the fixed one of their config
+func EIP155ChainId(neatChainId string) *big.Int {
+	switch neatChainId {
+	case MainnetChainConfig.NeatChainId:
+		return MainnetChainConfig.ChainId
+	case TestnetChainConfig.NeatChainId:
+		return TestnetChainConfig.ChainId
+	}
+	digest := crypto.Keccak256([]byte(neatChainId))
+	return new(big.Int).SetBytes(digest[:])
+}
+
 func (c *ChainConfig) GasTable(num *big.Int) GasTable {
 	if num == nil {
 		return GasTableHomestead
@@ -239,6 +302,21 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.IstanbulBlock, newcfg.IstanbulBlock, head) {
 		return newCompatError("Istanbul fork block", c.IstanbulBlock, newcfg.IstanbulBlock)
 	}
+	if isForkIncompatible(c.BerlinBlock, newcfg.BerlinBlock, head) {
+		return newCompatError("Berlin fork block", c.BerlinBlock, newcfg.BerlinBlock)
+	}
+	if isForkIncompatible(c.LondonBlock, newcfg.LondonBlock, head) {
+		return newCompatError("London fork block", c.LondonBlock, newcfg.LondonBlock)
+	}
+	if isForked(c.LondonBlock, head) && c.BaseFeeDestination != newcfg.BaseFeeDestination {
+		return newCompatError("base fee destination", c.LondonBlock, newcfg.LondonBlock)
+	}
+	if isForkIncompatible(c.ShanghaiBlock, newcfg.ShanghaiBlock, head) {
+		return newCompatError("Shanghai fork block", c.ShanghaiBlock, newcfg.ShanghaiBlock)
+	}
+	if isForkIncompatible(c.NeatForkBlock, newcfg.NeatForkBlock, head) {
+		return newCompatError("Neat fork block", c.NeatForkBlock, newcfg.NeatForkBlock)
+	}
 	return nil
 }
 
@@ -296,6 +374,8 @@ type Rules struct {
 	ChainId                                                 *big.Int
 	IsHomestead, IsEIP150, IsEIP155, IsEIP158               bool
 	IsByzantium, IsConstantinople, IsPetersburg, IsIstanbul bool
+	IsBerlin, IsLondon, IsShanghai                          bool
+	IsNeatFork                                              bool
 }
 
 func (c *ChainConfig) Rules(num *big.Int) Rules {
@@ -313,5 +393,9 @@ func (c *ChainConfig) Rules(num *big.Int) Rules {
 		IsConstantinople: c.IsConstantinople(num),
 		IsPetersburg:     c.IsPetersburg(num),
 		IsIstanbul:       c.IsIstanbul(num),
+		IsBerlin:         c.IsBerlin(num),
+		IsLondon:         c.IsLondon(num),
+		IsShanghai:       c.IsShanghai(num),
+		IsNeatFork:       c.IsNeatFork(num),
 	}
 }
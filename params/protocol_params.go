@@ -45,6 +45,23 @@ const (
 	SstoreCleanRefundEIP2200 uint64 = 4200
 	SstoreClearRefundEIP2200 uint64 = 15000
 
+	ColdAccountAccessCostEIP2929 uint64 = 2600
+	ColdSloadCostEIP2929         uint64 = 2100
+	WarmStorageReadCostEIP2929   uint64 = 100
+
+	// The refund of clearing a slot after EIP-3529: SSTORE_RESET_GAS - COLD_SLOAD_COST + ACCESS_LIST_STORAGE_KEY_COST
+	SstoreClearsScheduleRefundEIP3529 uint64 = SstoreCleanGasEIP2200 - ColdSloadCostEIP2929 + TxAccessListStorageKeyGas
+
+	RefundQuotient        uint64 = 2
+	RefundQuotientEIP3529 uint64 = 5
+
+	TxAccessListAddressGas    uint64 = 2400
+	TxAccessListStorageKeyGas uint64 = 1900
+
+	BaseFeeChangeDenominator = 8
+	ElasticityMultiplier     = 2
+	InitialBaseFee           = 100 * GWei
+
 	JumpdestGas   uint64 = 1
 	EpochDuration uint64 = 30000
 
@@ -86,7 +103,10 @@ const (
 
 	CreateBySelfdestructGas uint64 = 25000
 
-	MaxCodeSize = 24576
+	MaxCodeSize     = 24576
+	MaxInitCodeSize = 2 * MaxCodeSize
+
+	InitCodeWordGas uint64 = 2
 
 	EcrecoverGas            uint64 = 3000
 	Sha256BaseGas           uint64 = 60
@@ -109,8 +129,18 @@ const (
 	Bn256PairingBaseGasIstanbul      uint64 = 45000
 	Bn256PairingPerPointGasByzantium uint64 = 80000
 	Bn256PairingPerPointGasIstanbul  uint64 = 34000
+
+	SideChainHeaderVerifyGas uint64 = 100000
+	ReceiptProofBaseGas      uint64 = 3000
+	ReceiptProofPerWordGas   uint64 = 12
+	StakingQueryGas          uint64 = 3000
+	StakingUpdateGas         uint64 = 21000
 )
 
+// DowntimeJailBlocks is the minimum number of blocks a validator banned for
+// downtime stays banned before it can unban itself
+const DowntimeJailBlocks uint64 = 86400
+
 var (
 	DifficultyBoundDivisor = big.NewInt(2048)
 	GenesisDifficulty      = big.NewInt(131072)
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/neatio-net/neatio/chain/consensus"
+	"github.com/neatio-net/neatio/chain/consensus/misc"
 	ntcTypes "github.com/neatio-net/neatio/chain/consensus/neatcon/types"
 	"github.com/neatio-net/neatio/chain/core"
 	"github.com/neatio-net/neatio/chain/core/state"
@@ -410,7 +411,7 @@ func (self *worker) makeCurrent(parent *types.Block, header *types.Header) error
 		return err
 	}
 	work := &Work{
-		signer:    types.NewEIP155Signer(self.config.ChainId),
+		signer:    types.LatestSigner(self.config),
 		state:     state,
 		ancestors: set.New(),
 		family:    set.New(),
@@ -464,6 +465,9 @@ func (self *worker) commitNewWork() {
 		Extra:      self.extra,
 		Time:       big.NewInt(tstamp),
 	}
+	if self.config.IsLondon(header.Number) {
+		header.BaseFee = misc.CalcBaseFee(self.config, parent.Header())
+	}
 
 	if self.isRunning() {
 		if self.coinbase == (common.Address{}) {
@@ -576,6 +580,13 @@ func (self *worker) commitTransactionsEx(txs *types.TransactionsByPriceAndNonce,
 			continue
 		}
 
+		if baseFee := self.current.header.BaseFee; baseFee != nil && tx.GasFeeCap().Cmp(baseFee) < 0 {
+			self.logger.Trace("Ignoring transaction below the base fee", "hash", tx.Hash(), "maxFeePerGas", tx.GasFeeCap(), "baseFee", baseFee)
+
+			txs.Pop()
+			continue
+		}
+
 		self.current.state.Prepare(tx.Hash(), common.Hash{}, self.current.tcount)
 
 		logs, err := self.commitTransactionEx(tx, coinbase, gp, totalUsedMoney, cch)
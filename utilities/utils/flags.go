@@ -182,6 +182,16 @@ var (
 		Usage: "Maximum amount of time non-executable transaction are queued",
 		Value: neatptc.DefaultConfig.TxPool.Lifetime,
 	}
+	TxPoolSpecialSlotsFlag = cli.Uint64Flag{
+		Name:  "txpool.specialslots",
+		Usage: "Maximum number of transaction slots reserved for the special chain transactions",
+		Value: neatptc.DefaultConfig.TxPool.SpecialSlots,
+	}
+	TxPoolSpecialAccountSlotsFlag = cli.Uint64Flag{
+		Name:  "txpool.specialaccountslots",
+		Usage: "Maximum number of special chain transactions permitted per account",
+		Value: neatptc.DefaultConfig.TxPool.SpecialAccountSlots,
+	}
 	CacheFlag = cli.IntFlag{
 		Name:  "cache",
 		Usage: "Megabytes of memory allocated to internal caching",
@@ -226,6 +236,10 @@ var (
 		Usage: "Minimal gas price for mining a transactions",
 		Value: neatptc.DefaultConfig.MinerGasPrice,
 	}
+	MinerAutoRevealFlag = cli.BoolFlag{
+		Name:  "miner.autoreveal",
+		Usage: "Reveal the votes for the next epoch saved in the keystore once the reveal stage opens (accounts must be unlocked)",
+	}
 	MinerCoinbaseFlag = cli.StringFlag{
 		Name:  "miner.etherbase",
 		Usage: "Public address for block mining rewards (default = first account)",
@@ -427,6 +441,11 @@ var (
 		Usage: "Specify one or more side chain should be start. Ex: side-1,side-2",
 	}
 
+	SideChainProcessFlag = cli.BoolFlag{
+		Name:  "sidechain.process",
+		Usage: "Run each side chain in a child neatio process, the side chain RPC is served on <datadir>/<chainId>/neatio.ipc",
+	}
+
 	MonikerFlag = cli.StringFlag{
 		Name:  "moniker",
 		Value: "",
@@ -768,6 +787,12 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolSpecialSlotsFlag.Name) {
+		cfg.SpecialSlots = ctx.GlobalUint64(TxPoolSpecialSlotsFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolSpecialAccountSlotsFlag.Name) {
+		cfg.SpecialAccountSlots = ctx.GlobalUint64(TxPoolSpecialAccountSlotsFlag.Name)
+	}
 }
 
 func checkExclusive(ctx *cli.Context, args ...interface{}) {
@@ -865,6 +890,7 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *neatptc.Config) {
 	}
 
 	cfg.PruneStateData = ctx.GlobalBool(PruneFlag.Name)
+	cfg.AutoRevealVote = ctx.GlobalBool(MinerAutoRevealFlag.Name)
 }
 
 func SetGeneralConfig(ctx *cli.Context) {
@@ -82,24 +82,47 @@ func HookupHTTP(chainId string, httpHandler *rpc.Server) error {
 	if httpMux != nil {
 		log.Infof("Hookup HTTP for (chainId, http Handler): (%v, %v)", chainId, httpHandler)
 		if httpHandler != nil {
-			httpMux.Handle("/", httpHandler)
+			// the main chain is hooked up first and also serves the root path
+			if len(httpHandlerMapping) == 0 {
+				httpMux.Handle("/", httpHandler)
+			}
+			httpMux.Handle("/"+chainId, httpHandler)
 			httpHandlerMapping[chainId] = httpHandler
 		}
 	}
 	return nil
 }
 
+func UnhookHTTP(chainId string) {
+	if httpHandler, ok := httpHandlerMapping[chainId]; ok {
+		log.Infof("Unhook HTTP for chainId: %v", chainId)
+		httpHandler.Stop()
+		delete(httpHandlerMapping, chainId)
+	}
+}
+
 func HookupWS(chainId string, wsHandler *rpc.Server) error {
 	if wsMux != nil {
 		log.Infof("Hookup WS for (chainId, ws Handler): (%v, %v)", chainId, wsHandler)
 		if wsHandler != nil {
-			wsMux.Handle("/", wsHandler.WebsocketHandler(wsOrigins))
+			if len(wsHandlerMapping) == 0 {
+				wsMux.Handle("/", wsHandler.WebsocketHandler(wsOrigins))
+			}
+			wsMux.Handle("/"+chainId, wsHandler.WebsocketHandler(wsOrigins))
 			wsHandlerMapping[chainId] = wsHandler
 		}
 	}
 	return nil
 }
 
+func UnhookWS(chainId string) {
+	if wsHandler, ok := wsHandlerMapping[chainId]; ok {
+		log.Infof("Unhook WS for chainId: %v", chainId)
+		wsHandler.Stop()
+		delete(wsHandlerMapping, chainId)
+	}
+}
+
 func startHTTP(endpoint string, cors []string, vhosts []string, timeouts rpc.HTTPTimeouts) error {
 	if endpoint == "" {
 		return nil